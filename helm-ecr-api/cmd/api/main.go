@@ -4,62 +4,226 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
+	"helm-ecr-api/internal/config"
+	"helm-ecr-api/internal/events"
 	"helm-ecr-api/internal/handler"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/middleware"
 	"helm-ecr-api/internal/service"
+	"helm-ecr-api/internal/store"
+	"helm-ecr-api/internal/tracing"
+	"helm-ecr-api/internal/webhook"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"strconv"
+	"sync"
 	"syscall"
-	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
 )
 
 func main() {
+	// 설정 파일 경로는 -config 플래그 또는 CONFIG_FILE 환경 변수로 지정합니다.
+	// 지정하지 않으면 환경 변수만으로 설정을 구성합니다.
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to YAML or JSON config file")
+	flag.Parse()
+
 	// 1. 로거 설정
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	// LevelVar를 사용하면 설정 리로드 시 로그 레벨을 실행 중에 변경할 수 있습니다.
+	logLevel := new(slog.LevelVar)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+	slog.SetDefault(logger) // 요청 범위 로거가 없는 곳(백그라운드 작업 등)에서도 같은 형식을 사용합니다.
+
+	// 2. 설정 로드 및 검증
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		logger.Error("failed to load configuration", "error", err, "path", *configPath)
+		os.Exit(1)
+	}
+	level, _ := cfg.SlogLevel() // Load에서 이미 검증됨
+	logLevel.Set(level)
 
-	// 환경 변수에서 허용할 Helm 리포지토리 목록을 읽어옵니다.
-	allowedReposStr := os.Getenv("HELM_REPOSITORIES")
-	if allowedReposStr == "" {
-		logger.Error("HELM_REPOSITORIES environment variable must be set")
+	// 3. 트레이싱 설정
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "helm-ecr-api",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
 		os.Exit(1)
 	}
-	allowedRepos := strings.Split(allowedReposStr, ",")
 
-	// 2. AWS 설정 로드
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	// 4. AWS 설정 로드
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO())
 	if err != nil {
 		logger.Error("failed to load AWS configuration", "error", err)
 		os.Exit(1)
 	}
+	// AWS SDK 호출마다 스팬을 생성하고 trace context를 전파합니다.
+	otelaws.AppendMiddlewares(&awsCfg.APIOptions)
+
+	signatureKeys, err := cfg.SignatureKeys()
+	if err != nil {
+		logger.Error("failed to load signature public keys", "error", err)
+		os.Exit(1)
+	}
 
-	// 3. 서비스 및 핸들러 계층 초기화 (의존성 주입)
-	ecrSvc := service.NewECRService(cfg, allowedRepos)
+	// 5. 서비스 및 핸들러 계층 초기화 (의존성 주입)
+	svcOpts := []service.Option{
+		service.WithLogger(logger),
+		service.WithRegistry(cfg.Registry.Host, cfg.Registry.Insecure, cfg.Registry.Anonymous),
+		service.WithUpstreamTimeout(cfg.Upstream.Timeout.Duration),
+		service.WithRetry(service.RetryPolicy{
+			MaxAttempts:    cfg.Upstream.Retry.MaxAttempts,
+			InitialBackoff: cfg.Upstream.Retry.InitialBackoff.Duration,
+			MaxBackoff:     cfg.Upstream.Retry.MaxBackoff.Duration,
+		}),
+		service.WithMaxConcurrentDownloads(cfg.RateLimit.MaxConcurrentDownloads),
+		service.WithRenderLimits(cfg.Archive.RenderTimeout.Duration, cfg.RateLimit.MaxConcurrentRenders),
+		service.WithMaxFileSize(int64(cfg.Archive.MaxFileSize)),
+		service.WithImageVerifyAccounts(cfg.ImageVerify.AllowedAccounts),
+		service.WithArchiveLimits(service.ArchiveLimits{
+			MaxCompressedSize:   int64(cfg.Archive.MaxCompressedSize),
+			MaxUncompressedSize: int64(cfg.Archive.MaxUncompressedSize),
+			MaxEntries:          cfg.Archive.MaxEntries,
+			MaxPathLength:       cfg.Archive.MaxPathLength,
+		}),
+		service.WithReadiness(cfg.Health.ReadinessCacheTTL.Duration, cfg.Health.ReadinessTimeout.Duration),
+		// 리로드로 검증을 켤 수 있도록 모드가 off여도 캐시 TTL을 지정합니다.
+		service.WithSignatureVerification(service.SignatureMode(cfg.Signature.Mode), signatureKeys, cfg.Signature.CacheTTL.Duration),
+	}
+	if cfg.RepositoryTag.Key != "" {
+		svcOpts = append(svcOpts, service.WithExposeTag(cfg.RepositoryTag.Key, cfg.RepositoryTag.Value, cfg.RepositoryTag.RefreshInterval.Duration))
+	}
+	// 카탈로그 저장소는 카탈로그를 갱신하는 고루틴(동기화, 이벤트 처리)이 모두 끝난 뒤 닫습니다.
+	var catalogStore *store.Store
+	if cfg.Catalog.Enabled {
+		if cfg.Catalog.Path != "" {
+			catalogStore, err = store.Open(cfg.Catalog.Path)
+			if err != nil {
+				logger.Error("failed to open catalog store", "error", err, "path", cfg.Catalog.Path)
+				os.Exit(1)
+			}
+		}
+		svcOpts = append(svcOpts, service.WithCatalog(catalogStore, cfg.Catalog.RefreshInterval.Duration, cfg.Catalog.Concurrency, cfg.Catalog.MaxStaleness.Duration))
+	} else if cfg.Catalog.Search {
+		svcOpts = append(svcOpts, service.WithSearchIndex(cfg.Catalog.RefreshInterval.Duration, cfg.Catalog.Concurrency))
+	}
+	// 카탈로그에서 발견한 새 차트 태그를 웹훅으로 알립니다.
+	var notifier *webhook.Notifier
+	if cfg.Webhooks.Enabled {
+		endpoints := make([]webhook.Endpoint, len(cfg.Webhooks.Endpoints))
+		for i, e := range cfg.Webhooks.Endpoints {
+			endpoints[i] = webhook.Endpoint{Name: e.Name, URL: e.URL, Secret: e.Secret}
+		}
+		notifier = webhook.New(endpoints,
+			webhook.WithLogger(logger),
+			webhook.WithTimeout(cfg.Webhooks.Timeout.Duration),
+			webhook.WithRetry(cfg.Webhooks.MaxAttempts, cfg.Webhooks.InitialBackoff.Duration, cfg.Webhooks.MaxBackoff.Duration),
+			webhook.WithQueueSize(cfg.Webhooks.QueueSize),
+			webhook.WithDeliveryLogSize(cfg.Webhooks.DeliveryLogSize),
+		)
+		svcOpts = append(svcOpts, service.WithTagListener(notifier.NotifyTag))
+	}
+
+	ecrSvc, err := service.NewECRService(awsCfg, cfg.Repositories, svcOpts...)
+	if err != nil {
+		logger.Error("failed to create ECR service", "error", err)
+		os.Exit(1)
+	}
 	helmHandler := handler.NewHelmHandler(ecrSvc, logger)
+	healthHandler := handler.NewHealthHandler(ecrSvc, logger)
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+	if cfg.RateLimit.RequestsPerSecond > 0 && len(cfg.Server.TrustedProxies) == 0 {
+		logger.Warn("client rate limiting is enabled without server.trustedProxies; clients behind a proxy share one limit")
+	}
+
+	// 백그라운드 작업은 서버 종료 시 함께 중단되도록 취소 가능한 컨텍스트를 사용합니다.
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go ecrSvc.RunTagRefresher(bgCtx)
+	var catalogWriters sync.WaitGroup
+	catalogWriters.Add(1)
+	go func() {
+		defer catalogWriters.Done()
+		ecrSvc.RunCatalogSync(bgCtx)
+	}()
+
+	// ECR 이미지 이벤트를 받아 카탈로그에 바로 반영합니다.
+	if cfg.Events.Enabled {
+		var source events.Source
+		switch cfg.Events.Source {
+		case "sqs":
+			source = events.NewSQSSource(awsCfg, cfg.Events.QueueURL, cfg.Events.WaitTime.Duration, cfg.Events.VisibilityTimeout.Duration)
+		case "file":
+			source = events.NewFileSource(cfg.Events.Path, cfg.Events.PollInterval.Duration)
+		}
+		consumer := events.NewConsumer(source, ecrSvc.ApplyImageAction, logger)
+		catalogWriters.Add(1)
+		go func() {
+			defer catalogWriters.Done()
+			consumer.Run(bgCtx)
+		}()
+	}
+	if notifier != nil {
+		go notifier.Run(bgCtx)
+	}
 
-	// 4. 라우터 설정
+	// 설정 파일이 있으면 SIGHUP 또는 파일 변경 시 설정을 다시 읽습니다.
+	if *configPath != "" {
+		r := &reloader{
+			path:     *configPath,
+			logger:   logger,
+			logLevel: logLevel,
+			service:  ecrSvc,
+			limiter:  rateLimiter,
+			current:  cfg,
+		}
+		go r.run(bgCtx)
+	}
+
+	// 6. 라우터 설정
 	mux := http.NewServeMux()
 
 	// RESTful API 경로 설계 (통합 라우터 버전)
 	// 모든 /v1/helm-charts 요청을 통합 라우터가 처리합니다.
-	mux.HandleFunc("GET /v1/helm-charts", helmHandler.RouteHelmCharts)     // 리스트 조회
-	mux.HandleFunc("GET /v1/helm-charts/{rest...}", helmHandler.RouteHelmCharts)    // 상세 조회 및 파일 조회
+	routeHelmCharts := rateLimiter.Handler(http.HandlerFunc(helmHandler.RouteHelmCharts))
+	mux.Handle("GET /v1/helm-charts", routeHelmCharts)           // 리스트 조회
+	mux.Handle("GET /v1/helm-charts/{rest...}", routeHelmCharts) // 상세 조회, 파일 조회 및 검색
+	if notifier != nil {
+		webhookHandler := handler.NewWebhookHandler(notifier, logger)
+		mux.Handle("GET /v1/webhooks/deliveries", rateLimiter.Handler(http.HandlerFunc(webhookHandler.ListDeliveries))) // 웹훅 전송 기록
+	}
 	mux.HandleFunc("GET /health", helmHandler.HealthCheck)
+	mux.HandleFunc("GET /livez", healthHandler.Livez)   // livenessProbe
+	mux.HandleFunc("GET /readyz", healthHandler.Readyz) // readinessProbe
+	mux.Handle("GET /metrics", metrics.Handler())       // Prometheus 스크랩 엔드포인트
+
+	// 미들웨어는 바깥쪽부터 순서대로 실행됩니다.
+	// Tracing → ClientIP(프록시 뒤의 클라이언트 IP) → RequestID(요청 ID와 요청 범위 로거) → AccessLog → Metrics → 라우터
+	var rootHandler http.Handler = mux
+	rootHandler = middleware.Metrics(rootHandler)
+	rootHandler = middleware.AccessLog(rootHandler)
+	rootHandler = middleware.RequestID(logger)(rootHandler)
+	rootHandler = middleware.ClientIP(cfg.TrustedProxyPrefixes())(rootHandler)
+	rootHandler = middleware.Tracing(rootHandler)
 
-	// 환경 변수에서 포트를 읽어오고, 설정되지 않은 경우 기본값 8080을 사용합니다.
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
 	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: mux,
+		Addr:         ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:      rootHandler,
+		ReadTimeout:  cfg.Server.ReadTimeout.Duration,
+		WriteTimeout: cfg.Server.WriteTimeout.Duration,
+		IdleTimeout:  cfg.Server.IdleTimeout.Duration,
 	}
 
-	// 5. Graceful Shutdown과 함께 서버 시작
+	// 7. Graceful Shutdown과 함께 서버 시작
 	go func() {
 		logger.Info("starting server", "addr", server.Addr)
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -72,8 +236,9 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("shutting down server...")
+	stopBackground()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
@@ -81,5 +246,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	if catalogStore != nil {
+		catalogWriters.Wait()
+		if err := catalogStore.Close(); err != nil {
+			logger.Error("failed to close catalog store", "error", err)
+		}
+	}
+
+	// 서버가 모든 요청을 처리한 뒤 남은 스팬을 내보냅니다.
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("tracing shutdown failed", "error", err)
+	}
+
 	logger.Info("server exited properly")
 }
@@ -0,0 +1,131 @@
+// filepath: helm-ecr-api/cmd/api/reload.go
+package main
+
+import (
+	"context"
+	"helm-ecr-api/internal/config"
+	"helm-ecr-api/internal/middleware"
+	"helm-ecr-api/internal/service"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce는 짧은 시간 안에 연속으로 발생한 파일 변경 이벤트를 한 번의 리로드로 묶는 대기 시간입니다.
+// 편집기나 Kubernetes ConfigMap 업데이트는 한 번의 변경에도 여러 이벤트를 발생시킵니다.
+const reloadDebounce = 500 * time.Millisecond
+
+// reloader는 SIGHUP 또는 설정 파일 변경 시 설정을 다시 읽어 실행 중인 컴포넌트에 반영합니다.
+// 허용 목록, 로그 레벨, 요청 수 제한, 서명 검증 모드와 공개 키는 원자적으로 교체되므로 처리 중인 요청에 영향을 주지 않습니다.
+// 그 외 설정(포트, 타임아웃, 아카이브 제한, 동시 다운로드 제한 등)은 재시작해야 적용됩니다.
+type reloader struct {
+	path     string
+	logger   *slog.Logger
+	logLevel *slog.LevelVar
+	service  *service.ECRService
+	limiter  *middleware.RateLimiter
+	current  *config.Config
+}
+
+// run은 ctx가 취소될 때까지 리로드 트리거를 기다립니다.
+func (r *reloader) run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	// Kubernetes ConfigMap은 심볼릭 링크를 교체하는 방식으로 갱신되므로 파일이 아닌 디렉토리를 감시합니다.
+	var events <-chan fsnotify.Event
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		r.logger.Error("failed to create config file watcher, only SIGHUP reload is available", "error", err)
+	} else {
+		defer watcher.Close()
+		if err := watcher.Add(filepath.Dir(r.path)); err != nil {
+			r.logger.Error("failed to watch config directory, only SIGHUP reload is available", "error", err)
+		} else {
+			events = watcher.Events
+		}
+	}
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.logger.Info("received SIGHUP, reloading configuration")
+			r.reload()
+		case event := <-events:
+			base := filepath.Base(event.Name)
+			if base == filepath.Base(r.path) || base == "..data" {
+				debounce.Reset(reloadDebounce)
+			}
+		case <-debounce.C:
+			r.logger.Info("config file changed, reloading configuration", "path", r.path)
+			r.reload()
+		}
+	}
+}
+
+// reload는 설정을 다시 읽고 검증한 뒤 실행 중에 변경 가능한 항목을 반영합니다.
+// 새 설정이 유효하지 않으면 기존 설정을 그대로 유지합니다.
+func (r *reloader) reload() {
+	cfg, err := config.Load(r.path)
+	if err != nil {
+		r.logger.Error("failed to reload configuration, keeping previous configuration", "error", err)
+		return
+	}
+
+	// 공개 키 파일을 읽지 못하면 다른 설정도 반영하지 않도록 먼저 읽습니다.
+	keys, err := cfg.SignatureKeys()
+	if err != nil {
+		r.logger.Error("failed to load signature public keys, keeping previous configuration", "error", err)
+		return
+	}
+
+	if err := r.service.SetAllowedRepos(cfg.Repositories); err != nil {
+		r.logger.Error("failed to apply repositories, keeping previous configuration", "error", err)
+		return
+	}
+	r.service.SetSignaturePolicy(service.SignatureMode(cfg.Signature.Mode), keys)
+	level, _ := cfg.SlogLevel() // Load에서 이미 검증됨
+	r.logLevel.Set(level)
+	if cfg.RateLimit.RequestsPerSecond != r.current.RateLimit.RequestsPerSecond || cfg.RateLimit.Burst != r.current.RateLimit.Burst {
+		r.limiter.SetLimit(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+	}
+
+	// 실행 중에 반영할 수 없는 설정이 바뀌었으면 재시작이 필요하다는 것을 알립니다.
+	if restartRequired(r.current, cfg) {
+		r.logger.Warn("some configuration changes require a restart to take effect")
+	}
+
+	r.current = cfg
+	r.logger.Info("configuration reloaded", "repositories", len(cfg.Repositories), "log_level", level.String())
+}
+
+// restartRequired는 prev에서 next로 바뀐 설정 중 실행 중에 반영할 수 없는 항목이 있는지 반환합니다.
+// reload가 교체하는 항목(허용 목록, 로그 레벨, 요청 수 제한, 서명 검증 모드와 공개 키)의 변경은 재시작이 필요하지 않습니다.
+func restartRequired(prev, next *config.Config) bool {
+	return !reflect.DeepEqual(next.Server, prev.Server) ||
+		!reflect.DeepEqual(next.RepositoryTag, prev.RepositoryTag) ||
+		!reflect.DeepEqual(next.Registry, prev.Registry) ||
+		!reflect.DeepEqual(next.Upstream, prev.Upstream) ||
+		!reflect.DeepEqual(next.Archive, prev.Archive) ||
+		!reflect.DeepEqual(next.Catalog, prev.Catalog) ||
+		!reflect.DeepEqual(next.Events, prev.Events) ||
+		!reflect.DeepEqual(next.Webhooks, prev.Webhooks) ||
+		!reflect.DeepEqual(next.Tracing, prev.Tracing) ||
+		!reflect.DeepEqual(next.Health, prev.Health) ||
+		!reflect.DeepEqual(next.ImageVerify, prev.ImageVerify) ||
+		next.Signature.CacheTTL != prev.Signature.CacheTTL ||
+		next.RateLimit.MaxConcurrentDownloads != prev.RateLimit.MaxConcurrentDownloads ||
+		next.RateLimit.MaxConcurrentRenders != prev.RateLimit.MaxConcurrentRenders
+}
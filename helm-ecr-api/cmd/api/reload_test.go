@@ -0,0 +1,136 @@
+// filepath: helm-ecr-api/cmd/api/reload_test.go
+package main
+
+import (
+	"context"
+	"helm-ecr-api/internal/config"
+	"helm-ecr-api/internal/middleware"
+	"helm-ecr-api/internal/service"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRestartRequired(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *config.Config)
+		want   bool
+	}{
+		{name: "no change", modify: func(c *config.Config) {}},
+
+		// reload가 실행 중에 교체하는 항목
+		{name: "repositories", modify: func(c *config.Config) { c.Repositories = []string{"other/*"} }},
+		{name: "log level", modify: func(c *config.Config) { c.LogLevel = "debug" }},
+		{name: "rate limit", modify: func(c *config.Config) {
+			c.RateLimit.RequestsPerSecond = 50
+			c.RateLimit.Burst = 100
+		}},
+		{name: "signature mode and keys", modify: func(c *config.Config) {
+			c.Signature.Mode = "verify"
+			c.Signature.PublicKeys = []config.PublicKeyConfig{{Name: "release", Path: "/keys/cosign.pub"}}
+		}},
+
+		// 재시작해야 적용되는 항목
+		{name: "server port", modify: func(c *config.Config) { c.Server.Port = 9000 }, want: true},
+		{name: "trusted proxies", modify: func(c *config.Config) { c.Server.TrustedProxies = []string{"10.0.0.0/8"} }, want: true},
+		{name: "repository tag", modify: func(c *config.Config) { c.RepositoryTag.Key = "helm" }, want: true},
+		{name: "registry", modify: func(c *config.Config) { c.Registry.Host = "localhost:5000" }, want: true},
+		{name: "upstream retry", modify: func(c *config.Config) { c.Upstream.Retry.MaxAttempts = 5 }, want: true},
+		{name: "archive limits", modify: func(c *config.Config) { c.Archive.MaxEntries = 10 }, want: true},
+		{name: "catalog", modify: func(c *config.Config) { c.Catalog.Enabled = true }, want: true},
+		{name: "events", modify: func(c *config.Config) { c.Events.QueueURL = "https://sqs.example.com/q" }, want: true},
+		{name: "webhooks", modify: func(c *config.Config) { c.Webhooks.QueueSize = 10 }, want: true},
+		{name: "tracing", modify: func(c *config.Config) { c.Tracing.SampleRatio = 0.5 }, want: true},
+		{name: "health", modify: func(c *config.Config) { c.Health.ReadinessTimeout = config.Duration{Duration: time.Second} }, want: true},
+		{name: "image verify accounts", modify: func(c *config.Config) { c.ImageVerify.AllowedAccounts = []string{"210987654321"} }, want: true},
+		{name: "signature cache TTL", modify: func(c *config.Config) { c.Signature.CacheTTL = config.Duration{Duration: time.Minute} }, want: true},
+		{name: "concurrent downloads", modify: func(c *config.Config) { c.RateLimit.MaxConcurrentDownloads = 1 }, want: true},
+		{name: "concurrent renders", modify: func(c *config.Config) { c.RateLimit.MaxConcurrentRenders = 1 }, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := config.Default()
+			prev.Repositories = []string{"charts/*"}
+			next := config.Default()
+			next.Repositories = []string{"charts/*"}
+			tt.modify(next)
+			if got := restartRequired(prev, next); got != tt.want {
+				t.Errorf("restartRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestReload는 유효한 설정은 실행 중인 컴포넌트에 반영하고, 유효하지 않은 설정은 무시하는지 확인합니다.
+func TestReload(t *testing.T) {
+	for _, name := range []string{"PORT", "LOG_LEVEL", "HELM_REPOSITORIES", "HELM_REPOSITORY_TAG"} {
+		t.Setenv(name, "")
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("repositories: [charts/*]\n")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := service.NewECRService(aws.Config{Region: "us-east-1"}, cfg.Repositories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logLevel := new(slog.LevelVar)
+	r := &reloader{
+		path:     path,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		logLevel: logLevel,
+		service:  svc,
+		limiter:  middleware.NewRateLimiter(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst),
+		current:  cfg,
+	}
+
+	// allowed는 허용 목록에 없는 리포지토리를 ECR 호출 없이 거절하는지로 허용 여부를 확인합니다.
+	allowed := func(repo string) bool {
+		_, _, err := svc.DescribeHelmChart(context.Background(), repo, "1.0.0", "")
+		return service.ErrorCodeOf(err) != service.CodeRepoNotAllowed
+	}
+	if allowed("other/app") {
+		t.Fatal("other/app is allowed before the reload")
+	}
+
+	// 유효하지 않은 설정(잘못된 로그 레벨)이면 허용 목록도 바꾸지 않습니다.
+	write("repositories: [other/*]\nlogLevel: verbose\n")
+	r.reload()
+	if r.current != cfg || allowed("other/app") || logLevel.Level() != slog.LevelInfo {
+		t.Fatal("an invalid configuration was applied")
+	}
+
+	// 잘못된 패턴도 전체 리로드를 취소합니다.
+	write("repositories: [\"other/[a\"]\nlogLevel: debug\n")
+	r.reload()
+	if r.current != cfg || logLevel.Level() != slog.LevelInfo {
+		t.Fatal("a configuration with an invalid pattern was applied")
+	}
+
+	write("repositories: [other/*]\nlogLevel: debug\n")
+	r.reload()
+	if r.current == cfg || r.current.LogLevel != "debug" {
+		t.Fatalf("configuration was not reloaded: %+v", r.current)
+	}
+	if logLevel.Level() != slog.LevelDebug {
+		t.Errorf("log level = %s, want DEBUG", logLevel.Level())
+	}
+	if allowed("charts/app") {
+		t.Error("charts/app is still allowed after the reload")
+	}
+}
@@ -0,0 +1,627 @@
+// filepath: helm-ecr-api/internal/config/config.go
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"helm-ecr-api/internal/signature"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config는 애플리케이션 전체 설정입니다.
+// 설정 파일(YAML 또는 JSON)에서 읽은 값 위에 환경 변수 값을 덮어써서 구성됩니다.
+// sigs.k8s.io/yaml은 YAML을 JSON으로 변환한 뒤 파싱하므로 json 태그 하나로 두 형식을 모두 지원합니다.
+type Config struct {
+	Server        ServerConfig        `json:"server"`
+	LogLevel      string              `json:"logLevel"`
+	Repositories  []string            `json:"repositories"`
+	RepositoryTag RepositoryTagConfig `json:"repositoryTag"`
+	Registry      RegistryConfig      `json:"registry"`
+	Upstream      UpstreamConfig      `json:"upstream"`
+	Archive       ArchiveConfig       `json:"archive"`
+	Signature     SignatureConfig     `json:"signature"`
+	ImageVerify   ImageVerifyConfig   `json:"imageVerify"`
+	Catalog       CatalogConfig       `json:"catalog"`
+	Events        EventsConfig        `json:"events"`
+	Webhooks      WebhooksConfig      `json:"webhooks"`
+	RateLimit     RateLimitConfig     `json:"rateLimit"`
+	Tracing       TracingConfig       `json:"tracing"`
+	Health        HealthConfig        `json:"health"`
+}
+
+// ServerConfig는 HTTP 서버 설정입니다.
+type ServerConfig struct {
+	Port            int      `json:"port"`
+	ReadTimeout     Duration `json:"readTimeout"`
+	WriteTimeout    Duration `json:"writeTimeout"`
+	IdleTimeout     Duration `json:"idleTimeout"`
+	ShutdownTimeout Duration `json:"shutdownTimeout"`
+	// TrustedProxies는 X-Forwarded-For를 신뢰할 프록시(인그레스, 로드 밸런서)의 CIDR 또는 IP입니다.
+	// 비어 있으면 연결한 주소를 클라이언트 IP로 사용하므로, 프록시 뒤에서는 모든 익명 클라이언트가 하나의 요청 수 제한을 공유합니다.
+	TrustedProxies []string `json:"trustedProxies"`
+}
+
+// RepositoryTagConfig는 ECR 리소스 태그 기반 허용 목록 설정입니다.
+// Key가 비어 있으면 태그 기반 허용 목록을 사용하지 않습니다.
+type RepositoryTagConfig struct {
+	Key             string   `json:"key"`
+	Value           string   `json:"value"`
+	RefreshInterval Duration `json:"refreshInterval"`
+}
+
+// RegistryConfig는 차트를 내려받을 OCI 레지스트리 설정입니다.
+// Host가 비어 있으면 <계정 ID>.dkr.ecr.<리전>.amazonaws.com을 사용합니다.
+type RegistryConfig struct {
+	Host      string `json:"host"`
+	Insecure  bool   `json:"insecure"`  // HTTP(평문)로 접속 (로컬 레지스트리 테스트용)
+	Anonymous bool   `json:"anonymous"` // ECR 인증 토큰 대신 익명으로 접속
+}
+
+// UpstreamConfig는 ECR/레지스트리 호출 설정입니다.
+type UpstreamConfig struct {
+	Timeout Duration    `json:"timeout"`
+	Retry   RetryConfig `json:"retry"`
+}
+
+// RetryConfig는 요청 제한·일시적 장애 시 멱등한 조회 호출의 재시도 설정입니다.
+// 재시도 간격은 지수적으로 증가하며, 0과 상한 사이에서 무작위로 선택됩니다(full jitter).
+type RetryConfig struct {
+	MaxAttempts    int      `json:"maxAttempts"`    // 첫 시도를 포함한 최대 시도 횟수 (1이면 재시도하지 않음)
+	InitialBackoff Duration `json:"initialBackoff"` // 첫 재시도 전 최대 대기 시간
+	MaxBackoff     Duration `json:"maxBackoff"`     // 재시도 간 최대 대기 시간
+}
+
+// ArchiveConfig는 차트 아카이브에서 파일을 제공할 때의 제한 설정입니다.
+// 레지스트리의 아카이브는 신뢰할 수 없으므로 압축 폭탄 등을 막기 위해 해제 시 제한을 적용합니다. 0이면 제한하지 않습니다.
+type ArchiveConfig struct {
+	MaxFileSize         ByteSize `json:"maxFileSize"`         // 파일 조회 API로 제공할 수 있는 파일의 최대 크기
+	MaxCompressedSize   ByteSize `json:"maxCompressedSize"`   // 레지스트리에서 내려받는 압축된 차트 레이어(.tgz)의 최대 크기
+	MaxUncompressedSize ByteSize `json:"maxUncompressedSize"` // 아카이브 전체(하위 차트 아카이브 포함)의 압축 해제 후 최대 크기
+	MaxEntries          int      `json:"maxEntries"`          // 아카이브의 최대 항목 수
+	MaxPathLength       int      `json:"maxPathLength"`       // 항목 경로의 최대 길이
+	RenderTimeout       Duration `json:"renderTimeout"`       // 차트 템플릿 렌더링(이미지 목록, 린트)의 제한 시간
+}
+
+// SignatureConfig는 차트 서명(cosign) 검증 설정입니다.
+type SignatureConfig struct {
+	Mode       string            `json:"mode"`       // off, verify, enforce
+	PublicKeys []PublicKeyConfig `json:"publicKeys"` // 서명 검증에 사용할 공개 키 (하나라도 검증되면 성공)
+	CacheTTL   Duration          `json:"cacheTTL"`   // 매니페스트 다이제스트별 검증 결과를 재사용할 시간
+}
+
+// ImageVerifyConfig는 이미지 목록 API의 ECR 이미지 확인(verify=true, scan=true) 설정입니다.
+type ImageVerifyConfig struct {
+	// AllowedAccounts는 서버의 계정 외에 이미지를 조회할 AWS 계정 ID입니다. 다른 계정의 이미지는 조회하지 않고 skipped로 표시합니다.
+	AllowedAccounts []string `json:"allowedAccounts"`
+}
+
+// PublicKeyConfig는 서명 검증에 사용할 PEM 공개 키 파일과 응답에 표시할 서명자 이름입니다.
+type PublicKeyConfig struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// CatalogConfig는 리포지토리와 차트 버전의 로컬 카탈로그 설정입니다.
+// 활성화하면 허용된 모든 리포지토리의 이미지 목록을 주기적으로 동기화하고, 차트 버전의 Chart.yaml과 values.yaml을 다이제스트당 한 번씩 내려받아 저장합니다.
+// 목록과 상세 조회와 차트 검색은 카탈로그에서 응답합니다.
+// 비활성화해도 Search가 true(기본값)이면 같은 주기로 검색용 메모리 색인만 동기화하여 차트 검색을 제공합니다. (목록과 상세 조회는 ECR을 직접 조회)
+type CatalogConfig struct {
+	Enabled         bool     `json:"enabled"`
+	Search          bool     `json:"search"`          // 카탈로그가 비활성화되어도 검색용 메모리 색인을 동기화
+	Path            string   `json:"path"`            // bbolt 저장소 파일 경로. 비어 있으면 메모리에만 보관 (재시작하면 다시 동기화)
+	RefreshInterval Duration `json:"refreshInterval"` // 전체 동기화 주기
+	Concurrency     int      `json:"concurrency"`     // 새 차트 버전을 동시에 내려받을 수 (rateLimit.maxConcurrentDownloads를 함께 사용)
+	MaxStaleness    Duration `json:"maxStaleness"`    // 이보다 오래된 카탈로그 레코드는 사용하지 않고 ECR을 직접 조회
+}
+
+// EventsConfig는 ECR 이미지 이벤트(EventBridge → SQS) 구독 설정입니다.
+// 이미지 푸시/삭제 이벤트를 받으면 전체 동기화를 기다리지 않고 해당 이미지만 카탈로그에 반영합니다. 카탈로그를 사용해야 합니다.
+type EventsConfig struct {
+	Enabled           bool     `json:"enabled"`
+	Source            string   `json:"source"`            // sqs, file
+	QueueURL          string   `json:"queueURL"`          // source가 sqs일 때 SQS 큐 URL
+	WaitTime          Duration `json:"waitTime"`          // SQS 롱 폴링 대기 시간 (최대 20s)
+	VisibilityTimeout Duration `json:"visibilityTimeout"` // 처리에 실패한 메시지를 다시 받기까지의 시간. 0이면 큐 설정을 사용
+	Path              string   `json:"path"`              // source가 file일 때 이벤트를 한 줄에 하나씩 추가하는 JSON Lines 파일
+	PollInterval      Duration `json:"pollInterval"`      // source가 file일 때 파일 확인 주기
+}
+
+// WebhooksConfig는 새 차트 태그 알림 웹훅 설정입니다.
+// 카탈로그 동기화나 ECR 이미지 이벤트로 허용된 리포지토리에서 새 태그를 발견하면 각 엔드포인트에 서명된 JSON을 POST합니다. 카탈로그를 사용해야 합니다.
+type WebhooksConfig struct {
+	Enabled         bool                    `json:"enabled"`
+	Endpoints       []WebhookEndpointConfig `json:"endpoints"`
+	Timeout         Duration                `json:"timeout"`         // 요청 하나의 타임아웃
+	MaxAttempts     int                     `json:"maxAttempts"`     // 네트워크 에러, 408, 429, 5xx 응답일 때 재시도를 포함한 최대 시도 횟수
+	InitialBackoff  Duration                `json:"initialBackoff"`  // 첫 재시도 전 대기 시간 (시도마다 두 배, 지터 적용)
+	MaxBackoff      Duration                `json:"maxBackoff"`      // 재시도 대기 시간 상한
+	QueueSize       int                     `json:"queueSize"`       // 전송을 기다리는 최대 알림 수. 가득 차면 새 알림은 버림
+	DeliveryLogSize int                     `json:"deliveryLogSize"` // /v1/webhooks/deliveries에서 조회할 수 있는 최근 전송 기록 수
+}
+
+// WebhookEndpointConfig는 웹훅을 받을 URL과 서명 키입니다.
+// Secret이 비어 있으면 X-Webhook-Signature 헤더를 보내지 않습니다.
+type WebhookEndpointConfig struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// RateLimitConfig는 클라이언트별 요청 수 제한과 동시 다운로드 제한 설정입니다.
+type RateLimitConfig struct {
+	RequestsPerSecond      float64 `json:"requestsPerSecond"`      // 클라이언트 IP별 초당 요청 수. 0(기본값)이면 제한하지 않음. 프록시 뒤에서는 Server.TrustedProxies도 지정해야 함
+	Burst                  int     `json:"burst"`                  // RequestsPerSecond를 지정했을 때 순간적으로 허용할 최대 요청 수
+	MaxConcurrentDownloads int     `json:"maxConcurrentDownloads"` // 서버 전체의 동시 차트 레이어 다운로드 수. 0이면 제한하지 않음
+	MaxConcurrentRenders   int     `json:"maxConcurrentRenders"`   // 서버 전체의 동시 차트 템플릿 렌더링 수. 0이면 제한하지 않음
+}
+
+// TracingConfig는 OpenTelemetry 트레이싱 설정입니다.
+type TracingConfig struct {
+	Exporter    string  `json:"exporter"`    // none, otlp, stdout
+	Endpoint    string  `json:"endpoint"`    // OTLP/HTTP 컬렉터 주소 (예: "localhost:4318")
+	Insecure    bool    `json:"insecure"`    // 컬렉터에 HTTP(평문)로 접속
+	SampleRatio float64 `json:"sampleRatio"` // 0.0 ~ 1.0
+}
+
+// HealthConfig는 readiness 점검 설정입니다.
+type HealthConfig struct {
+	ReadinessCacheTTL Duration `json:"readinessCacheTTL"` // 점검 결과를 재사용할 시간
+	ReadinessTimeout  Duration `json:"readinessTimeout"`  // 점검 전체에 적용할 타임아웃
+}
+
+// Duration은 "30s", "5m"과 같은 문자열로 표현되는 time.Duration입니다.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON은 time.ParseDuration 형식의 문자열을 파싱합니다.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalJSON은 Duration을 문자열로 직렬화합니다.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// ByteSize는 "512KiB", "10MiB"와 같은 문자열 또는 바이트 단위 숫자로 표현되는 크기입니다.
+type ByteSize int64
+
+// byteUnits는 ByteSize에서 사용할 수 있는 단위입니다. 긴 접미사를 먼저 확인해야 합니다.
+var byteUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+	{"B", 1},
+}
+
+// UnmarshalJSON은 숫자 또는 단위가 붙은 문자열을 파싱합니다.
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*b = ByteSize(n)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("size must be a number or a string like \"10MiB\": %w", err)
+	}
+	s = strings.TrimSpace(s)
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if rest, ok := strings.CutSuffix(s, unit.suffix); ok {
+			s, multiplier = strings.TrimSpace(rest), unit.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size %q: %w", string(data), err)
+	}
+	*b = ByteSize(n * multiplier)
+	return nil
+}
+
+// MarshalJSON은 ByteSize를 바이트 단위 숫자로 직렬화합니다.
+func (b ByteSize) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(b))
+}
+
+// Default는 기본값이 채워진 Config를 반환합니다.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     Duration{10 * time.Second},
+			WriteTimeout:    Duration{60 * time.Second},
+			IdleTimeout:     Duration{120 * time.Second},
+			ShutdownTimeout: Duration{30 * time.Second},
+		},
+		LogLevel: "info",
+		RepositoryTag: RepositoryTagConfig{
+			RefreshInterval: Duration{5 * time.Minute},
+		},
+		Upstream: UpstreamConfig{
+			Timeout: Duration{30 * time.Second},
+			Retry: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: Duration{100 * time.Millisecond},
+				MaxBackoff:     Duration{2 * time.Second},
+			},
+		},
+		Archive: ArchiveConfig{
+			MaxFileSize:         10 << 20,
+			MaxCompressedSize:   20 << 20,
+			MaxUncompressedSize: 100 << 20,
+			MaxEntries:          10000,
+			MaxPathLength:       1024,
+			RenderTimeout:       Duration{10 * time.Second},
+		},
+		RateLimit: RateLimitConfig{
+			// 클라이언트 IP를 구분하려면 배포 환경의 프록시 대역을 알아야 하므로 요청 수 제한은 명시적으로 켜야 합니다.
+			RequestsPerSecond:      0,
+			Burst:                  20,
+			MaxConcurrentDownloads: 8,
+			MaxConcurrentRenders:   4,
+		},
+		Signature: SignatureConfig{
+			Mode:     "off",
+			CacheTTL: Duration{5 * time.Minute},
+		},
+		Catalog: CatalogConfig{
+			Search:          true,
+			RefreshInterval: Duration{10 * time.Minute},
+			Concurrency:     2,
+			MaxStaleness:    Duration{30 * time.Minute},
+		},
+		Events: EventsConfig{
+			Source:       "sqs",
+			WaitTime:     Duration{20 * time.Second},
+			PollInterval: Duration{time.Second},
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:         Duration{10 * time.Second},
+			MaxAttempts:     5,
+			InitialBackoff:  Duration{time.Second},
+			MaxBackoff:      Duration{time.Minute},
+			QueueSize:       1000,
+			DeliveryLogSize: 200,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1.0,
+		},
+		Health: HealthConfig{
+			ReadinessCacheTTL: Duration{10 * time.Second},
+			ReadinessTimeout:  Duration{5 * time.Second},
+		},
+	}
+}
+
+// Load는 기본값, 설정 파일, 환경 변수 순서로 설정을 구성하고 검증합니다.
+// path가 비어 있으면 설정 파일 없이 기본값과 환경 변수만 사용합니다.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		// UnmarshalStrict는 알 수 없는 필드가 있으면 에러를 반환하여 오타를 조기에 발견합니다.
+		if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnv는 환경 변수가 설정된 항목을 설정 파일 값보다 우선하여 적용합니다.
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid PORT %q: %w", v, err)
+		}
+		c.Server.Port = port
+	}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		c.LogLevel = v
+	}
+	if v := os.Getenv("HELM_REPOSITORIES"); v != "" {
+		c.Repositories = strings.Split(v, ",")
+	}
+	if v := os.Getenv("HELM_REPOSITORY_TAG"); v != "" {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("HELM_REPOSITORY_TAG must be in key=value format: %q", v)
+		}
+		c.RepositoryTag.Key = key
+		c.RepositoryTag.Value = value
+	}
+	if v := os.Getenv("HELM_REPOSITORY_TAG_REFRESH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid HELM_REPOSITORY_TAG_REFRESH_INTERVAL %q: %w", v, err)
+		}
+		c.RepositoryTag.RefreshInterval = Duration{d}
+	}
+	if v := os.Getenv("REGISTRY_HOST"); v != "" {
+		c.Registry.Host = v
+	}
+	if v := os.Getenv("TRACING_EXPORTER"); v != "" {
+		c.Tracing.Exporter = v
+	}
+	if v := os.Getenv("TRACING_ENDPOINT"); v != "" {
+		c.Tracing.Endpoint = v
+	}
+	return nil
+}
+
+// Validate는 설정 값이 올바른지 확인하고, 발견된 모든 문제를 하나의 에러로 묶어 반환합니다.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535: %d", c.Server.Port))
+	}
+	if c.Server.ShutdownTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("server.shutdownTimeout must be positive"))
+	}
+	for i, p := range c.Server.TrustedProxies {
+		if _, err := parsePrefix(p); err != nil {
+			errs = append(errs, fmt.Errorf("server.trustedProxies[%d]: %w", i, err))
+		}
+	}
+	if _, err := c.SlogLevel(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(c.Repositories) == 0 && c.RepositoryTag.Key == "" {
+		errs = append(errs, errors.New("repositories or repositoryTag.key must be set"))
+	}
+	if c.RepositoryTag.Key != "" && c.RepositoryTag.RefreshInterval.Duration <= 0 {
+		errs = append(errs, errors.New("repositoryTag.refreshInterval must be positive"))
+	}
+	if c.Upstream.Timeout.Duration < 0 {
+		errs = append(errs, errors.New("upstream.timeout must not be negative"))
+	}
+	if c.Upstream.Retry.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("upstream.retry.maxAttempts must be at least 1: %d", c.Upstream.Retry.MaxAttempts))
+	}
+	if c.Upstream.Retry.InitialBackoff.Duration <= 0 || c.Upstream.Retry.MaxBackoff.Duration < c.Upstream.Retry.InitialBackoff.Duration {
+		errs = append(errs, errors.New("upstream.retry backoffs must be positive and initialBackoff must not exceed maxBackoff"))
+	}
+	if c.Archive.MaxFileSize < 0 || c.Archive.MaxCompressedSize < 0 || c.Archive.MaxUncompressedSize < 0 || c.Archive.MaxEntries < 0 || c.Archive.MaxPathLength < 0 {
+		errs = append(errs, errors.New("archive limits must not be negative"))
+	}
+	if c.RateLimit.RequestsPerSecond < 0 {
+		errs = append(errs, errors.New("rateLimit.requestsPerSecond must not be negative"))
+	}
+	if c.RateLimit.RequestsPerSecond > 0 && c.RateLimit.Burst < 1 {
+		errs = append(errs, fmt.Errorf("rateLimit.burst must be at least 1: %d", c.RateLimit.Burst))
+	}
+	if c.RateLimit.MaxConcurrentDownloads < 0 {
+		errs = append(errs, errors.New("rateLimit.maxConcurrentDownloads must not be negative"))
+	}
+	if c.RateLimit.MaxConcurrentRenders < 0 {
+		errs = append(errs, errors.New("rateLimit.maxConcurrentRenders must not be negative"))
+	}
+	if c.Archive.RenderTimeout.Duration < 0 {
+		errs = append(errs, errors.New("archive.renderTimeout must not be negative"))
+	}
+	// 검색 색인도 카탈로그의 동기화 주기와 동시성 설정을 사용합니다.
+	if c.Catalog.Enabled || c.Catalog.Search {
+		if c.Catalog.RefreshInterval.Duration <= 0 {
+			errs = append(errs, errors.New("catalog.refreshInterval must be positive"))
+		}
+		if c.Catalog.Concurrency < 1 {
+			errs = append(errs, fmt.Errorf("catalog.concurrency must be at least 1: %d", c.Catalog.Concurrency))
+		}
+	}
+	if c.Catalog.Enabled {
+		if c.Catalog.MaxStaleness.Duration < c.Catalog.RefreshInterval.Duration {
+			errs = append(errs, fmt.Errorf("catalog.maxStaleness must not be shorter than catalog.refreshInterval: %s", c.Catalog.MaxStaleness))
+		}
+	}
+	if c.Events.Enabled {
+		if !c.Catalog.Enabled {
+			errs = append(errs, errors.New("events requires catalog.enabled"))
+		}
+		switch c.Events.Source {
+		case "sqs":
+			if c.Events.QueueURL == "" {
+				errs = append(errs, errors.New("events.queueURL is required when events.source is sqs"))
+			}
+			if c.Events.WaitTime.Duration < 0 || c.Events.WaitTime.Duration > 20*time.Second {
+				errs = append(errs, fmt.Errorf("events.waitTime must be between 0s and 20s: %s", c.Events.WaitTime))
+			}
+			if c.Events.VisibilityTimeout.Duration < 0 {
+				errs = append(errs, errors.New("events.visibilityTimeout must not be negative"))
+			}
+		case "file":
+			if c.Events.Path == "" {
+				errs = append(errs, errors.New("events.path is required when events.source is file"))
+			}
+			if c.Events.PollInterval.Duration <= 0 {
+				errs = append(errs, errors.New("events.pollInterval must be positive"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("events.source must be one of sqs, file: %q", c.Events.Source))
+		}
+	}
+	if c.Webhooks.Enabled {
+		if !c.Catalog.Enabled {
+			errs = append(errs, errors.New("webhooks requires catalog.enabled"))
+		}
+		if len(c.Webhooks.Endpoints) == 0 {
+			errs = append(errs, errors.New("webhooks.endpoints must be set when webhooks are enabled"))
+		}
+		if c.Webhooks.Timeout.Duration <= 0 {
+			errs = append(errs, errors.New("webhooks.timeout must be positive"))
+		}
+		if c.Webhooks.MaxAttempts < 1 {
+			errs = append(errs, fmt.Errorf("webhooks.maxAttempts must be at least 1: %d", c.Webhooks.MaxAttempts))
+		}
+		if c.Webhooks.InitialBackoff.Duration <= 0 || c.Webhooks.MaxBackoff.Duration < c.Webhooks.InitialBackoff.Duration {
+			errs = append(errs, errors.New("webhooks backoffs must be positive and initialBackoff must not exceed maxBackoff"))
+		}
+		if c.Webhooks.QueueSize < 1 {
+			errs = append(errs, fmt.Errorf("webhooks.queueSize must be at least 1: %d", c.Webhooks.QueueSize))
+		}
+		if c.Webhooks.DeliveryLogSize < 1 {
+			errs = append(errs, fmt.Errorf("webhooks.deliveryLogSize must be at least 1: %d", c.Webhooks.DeliveryLogSize))
+		}
+	}
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be one of none, otlp, stdout: %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sampleRatio must be between 0 and 1: %v", c.Tracing.SampleRatio))
+	}
+	if c.Health.ReadinessCacheTTL.Duration < 0 {
+		errs = append(errs, errors.New("health.readinessCacheTTL must not be negative"))
+	}
+	if c.Health.ReadinessTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("health.readinessTimeout must be positive"))
+	}
+
+	switch c.Signature.Mode {
+	case "off":
+	case "verify", "enforce":
+		if len(c.Signature.PublicKeys) == 0 {
+			errs = append(errs, fmt.Errorf("signature.publicKeys must be set when signature.mode is %s", c.Signature.Mode))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("signature.mode must be one of off, verify, enforce: %q", c.Signature.Mode))
+	}
+	if c.Signature.CacheTTL.Duration < 0 {
+		errs = append(errs, errors.New("signature.cacheTTL must not be negative"))
+	}
+	keyNames := make(map[string]struct{}, len(c.Signature.PublicKeys))
+	for i, k := range c.Signature.PublicKeys {
+		if k.Name == "" || k.Path == "" {
+			errs = append(errs, fmt.Errorf("signature.publicKeys[%d]: name and path are required", i))
+			continue
+		}
+		if _, dup := keyNames[k.Name]; dup {
+			errs = append(errs, fmt.Errorf("signature.publicKeys[%d]: duplicate name %q", i, k.Name))
+		}
+		keyNames[k.Name] = struct{}{}
+	}
+	for i, id := range c.ImageVerify.AllowedAccounts {
+		if !isAccountID(id) {
+			errs = append(errs, fmt.Errorf("imageVerify.allowedAccounts[%d] must be a 12-digit AWS account ID: %q", i, id))
+		}
+	}
+	endpointNames := make(map[string]struct{}, len(c.Webhooks.Endpoints))
+	for i, e := range c.Webhooks.Endpoints {
+		if e.Name == "" || e.URL == "" {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d]: name and url are required", i))
+			continue
+		}
+		if u, err := url.Parse(e.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d]: url must be an absolute http or https URL: %q", i, e.URL))
+		}
+		if _, dup := endpointNames[e.Name]; dup {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d]: duplicate name %q", i, e.Name))
+		}
+		endpointNames[e.Name] = struct{}{}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SlogLevel은 LogLevel 문자열("debug", "info", "warn", "error")을 slog.Level로 변환합니다.
+func (c *Config) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return level, fmt.Errorf("invalid logLevel %q: %w", c.LogLevel, err)
+	}
+	return level, nil
+}
+
+// TrustedProxyPrefixes는 server.trustedProxies를 IP 대역 목록으로 변환합니다. Validate에서 검증한 값이어야 합니다.
+func (c *Config) TrustedProxyPrefixes() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(c.Server.TrustedProxies))
+	for _, p := range c.Server.TrustedProxies {
+		if prefix, err := parsePrefix(p); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// parsePrefix는 CIDR("10.0.0.0/8") 또는 단일 IP("10.0.0.1")를 IP 대역으로 변환합니다.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", s)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q", s)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// SignatureKeys는 서명 검증에 사용할 공개 키 파일을 읽습니다.
+func (c *Config) SignatureKeys() ([]signature.Key, error) {
+	keys := make([]signature.Key, 0, len(c.Signature.PublicKeys))
+	for _, k := range c.Signature.PublicKeys {
+		key, err := signature.LoadKey(k.Name, k.Path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// isAccountID는 s가 12자리 AWS 계정 ID인지 확인합니다.
+func isAccountID(s string) bool {
+	if len(s) != 12 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
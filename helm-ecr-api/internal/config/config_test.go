@@ -0,0 +1,316 @@
+// filepath: helm-ecr-api/internal/config/config_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envVars는 applyEnv가 읽는 환경 변수입니다.
+var envVars = []string{
+	"PORT", "LOG_LEVEL", "HELM_REPOSITORIES", "HELM_REPOSITORY_TAG", "HELM_REPOSITORY_TAG_REFRESH_INTERVAL",
+	"REGISTRY_HOST", "TRACING_EXPORTER", "TRACING_ENDPOINT",
+}
+
+// clearEnv는 테스트를 실행하는 환경의 변수가 결과에 영향을 주지 않도록 설정 관련 환경 변수를 비웁니다.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range envVars {
+		t.Setenv(name, "")
+	}
+}
+
+// writeConfig는 임시 디렉터리에 설정 파일을 쓰고 경로를 반환합니다.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// validConfig는 Validate를 통과하는 최소 설정을 반환합니다.
+func validConfig() *Config {
+	cfg := Default()
+	cfg.Repositories = []string{"charts/*"}
+	return cfg
+}
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+	if cfg.Server.Port != 8080 || cfg.LogLevel != "info" || cfg.Signature.Mode != "off" || cfg.Tracing.Exporter != "none" {
+		t.Errorf("Default() = %+v", cfg)
+	}
+	// 클라이언트 IP별 요청 수 제한은 trustedProxies 없이 켜지지 않도록 기본적으로 꺼져 있습니다.
+	if cfg.RateLimit.RequestsPerSecond != 0 {
+		t.Errorf("RateLimit.RequestsPerSecond = %v, want 0", cfg.RateLimit.RequestsPerSecond)
+	}
+	// 기본값만으로는 허용할 리포지토리가 없으므로 검증에 실패해야 합니다.
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "repositories or repositoryTag.key must be set") {
+		t.Errorf("Validate() on the defaults = %v, want a repositories error", err)
+	}
+	if err := validConfig().Validate(); err != nil {
+		t.Errorf("Validate() with repositories = %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string // 파일 이름 (확장자로 형식을 구분하지 않음)
+		data  string
+		env   map[string]string
+		check func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "env only",
+			env:  map[string]string{"HELM_REPOSITORIES": "charts/app,charts/web", "PORT": "9000"},
+			check: func(t *testing.T, cfg *Config) {
+				if !reflect.DeepEqual(cfg.Repositories, []string{"charts/app", "charts/web"}) || cfg.Server.Port != 9000 {
+					t.Errorf("repositories = %v, port = %d", cfg.Repositories, cfg.Server.Port)
+				}
+				// 지정하지 않은 값은 기본값을 유지합니다.
+				if cfg.Upstream.Timeout.Duration != 30*time.Second {
+					t.Errorf("upstream.timeout = %s", cfg.Upstream.Timeout)
+				}
+			},
+		},
+		{
+			name: "yaml file",
+			file: "config.yaml",
+			data: `
+server:
+  port: 9000
+  readTimeout: 5s
+logLevel: debug
+repositories: [charts/app]
+archive:
+  maxFileSize: 2MiB
+  maxEntries: 100
+catalog:
+  search: false
+`,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 9000 || cfg.Server.ReadTimeout.Duration != 5*time.Second || cfg.LogLevel != "debug" {
+					t.Errorf("server = %+v, logLevel = %s", cfg.Server, cfg.LogLevel)
+				}
+				if cfg.Archive.MaxFileSize != 2<<20 || cfg.Archive.MaxEntries != 100 {
+					t.Errorf("archive = %+v", cfg.Archive)
+				}
+				// 파일에 없는 항목은 기본값을 유지합니다.
+				if cfg.Server.WriteTimeout.Duration != 60*time.Second || cfg.Archive.MaxCompressedSize != 20<<20 {
+					t.Errorf("defaults were not kept: %+v %+v", cfg.Server, cfg.Archive)
+				}
+				if cfg.Catalog.Search {
+					t.Error("catalog.search = true, want false")
+				}
+			},
+		},
+		{
+			name: "json file",
+			file: "config.json",
+			data: `{"repositories": ["charts/app"], "archive": {"maxFileSize": 1024}, "upstream": {"timeout": "1m"}}`,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Archive.MaxFileSize != 1024 || cfg.Upstream.Timeout.Duration != time.Minute {
+					t.Errorf("archive = %+v, upstream = %+v", cfg.Archive, cfg.Upstream)
+				}
+			},
+		},
+		{
+			name: "env overrides the file",
+			file: "config.yaml",
+			data: `
+server:
+  port: 9000
+logLevel: warn
+repositories: [charts/app]
+repositoryTag:
+  key: team
+  value: a
+registry:
+  host: file.example.com
+`,
+			env: map[string]string{
+				"PORT":                "9100",
+				"LOG_LEVEL":           "error",
+				"HELM_REPOSITORIES":   "charts/web",
+				"HELM_REPOSITORY_TAG": "helm=true",
+				"REGISTRY_HOST":       "env.example.com",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 9100 || cfg.LogLevel != "error" || cfg.Registry.Host != "env.example.com" {
+					t.Errorf("port = %d, logLevel = %s, registry = %s", cfg.Server.Port, cfg.LogLevel, cfg.Registry.Host)
+				}
+				if !reflect.DeepEqual(cfg.Repositories, []string{"charts/web"}) {
+					t.Errorf("repositories = %v", cfg.Repositories)
+				}
+				if cfg.RepositoryTag.Key != "helm" || cfg.RepositoryTag.Value != "true" {
+					t.Errorf("repositoryTag = %+v", cfg.RepositoryTag)
+				}
+			},
+		},
+		{
+			name: "empty env does not override the file",
+			file: "config.yaml",
+			data: "repositories: [charts/app]\nlogLevel: warn\n",
+			env:  map[string]string{"LOG_LEVEL": ""},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.LogLevel != "warn" {
+					t.Errorf("logLevel = %s, want warn", cfg.LogLevel)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := ""
+			if tt.file != "" {
+				path = writeConfig(t, tt.file, tt.data)
+			}
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string // 비어 있으면 존재하지 않는 파일 경로를 사용
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "missing file", wantErr: "failed to read config file"},
+		{name: "unknown field", data: "repositories: [charts/app]\nrepositorys: [charts/web]\n", wantErr: "failed to parse config file"},
+		{name: "invalid duration", data: "repositories: [charts/app]\nupstream:\n  timeout: 30\n", wantErr: "failed to parse config file"},
+		{name: "invalid size", data: "repositories: [charts/app]\narchive:\n  maxFileSize: 10MB\n", wantErr: "failed to parse config file"},
+		{name: "invalid PORT", data: "repositories: [charts/app]\n", env: map[string]string{"PORT": "http"}, wantErr: "invalid PORT"},
+		{name: "invalid HELM_REPOSITORY_TAG", data: "repositories: [charts/app]\n", env: map[string]string{"HELM_REPOSITORY_TAG": "helm"}, wantErr: "key=value"},
+		{name: "invalid refresh interval env", data: "repositories: [charts/app]\n", env: map[string]string{"HELM_REPOSITORY_TAG_REFRESH_INTERVAL": "often"}, wantErr: "HELM_REPOSITORY_TAG_REFRESH_INTERVAL"},
+		// 환경 변수를 적용한 뒤의 값을 검증합니다.
+		{name: "env value fails validation", data: "repositories: [charts/app]\n", env: map[string]string{"LOG_LEVEL": "verbose"}, wantErr: "invalid logLevel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := filepath.Join(t.TempDir(), "missing.yaml")
+			if tt.data != "" {
+				path = writeConfig(t, "config.yaml", tt.data)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr []string // 비어 있으면 유효해야 함
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{name: "repository tag without repositories", modify: func(c *Config) {
+			c.Repositories = nil
+			c.RepositoryTag.Key = "helm"
+		}},
+		{name: "port out of range", modify: func(c *Config) { c.Server.Port = 70000 }, wantErr: []string{"server.port"}},
+		{name: "invalid trusted proxy", modify: func(c *Config) { c.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy"} }, wantErr: []string{"server.trustedProxies[1]"}},
+		{name: "trusted proxy IP and CIDR", modify: func(c *Config) { c.Server.TrustedProxies = []string{"10.0.0.1", "fd00::/8"} }},
+		{name: "invalid log level", modify: func(c *Config) { c.LogLevel = "verbose" }, wantErr: []string{"invalid logLevel"}},
+		{name: "retry backoffs", modify: func(c *Config) { c.Upstream.Retry.MaxBackoff = Duration{time.Millisecond} }, wantErr: []string{"upstream.retry backoffs"}},
+		{name: "negative archive limit", modify: func(c *Config) { c.Archive.MaxEntries = -1 }, wantErr: []string{"archive limits"}},
+		{name: "rate limit without burst", modify: func(c *Config) {
+			c.RateLimit.RequestsPerSecond = 1
+			c.RateLimit.Burst = 0
+		}, wantErr: []string{"rateLimit.burst"}},
+		{name: "search index needs a refresh interval", modify: func(c *Config) { c.Catalog.RefreshInterval = Duration{} }, wantErr: []string{"catalog.refreshInterval"}},
+		{name: "no search and no catalog", modify: func(c *Config) {
+			c.Catalog.Search = false
+			c.Catalog.RefreshInterval = Duration{}
+			c.Catalog.Concurrency = 0
+		}},
+		{name: "catalog staleness shorter than the interval", modify: func(c *Config) {
+			c.Catalog.Enabled = true
+			c.Catalog.MaxStaleness = Duration{time.Minute}
+		}, wantErr: []string{"catalog.maxStaleness"}},
+		{name: "events without catalog", modify: func(c *Config) {
+			c.Events.Enabled = true
+			c.Events.QueueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/events"
+		}, wantErr: []string{"events requires catalog.enabled"}},
+		{name: "unknown events source", modify: func(c *Config) {
+			c.Catalog.Enabled = true
+			c.Events.Enabled = true
+			c.Events.Source = "kafka"
+		}, wantErr: []string{"events.source"}},
+		{name: "webhooks without endpoints", modify: func(c *Config) {
+			c.Catalog.Enabled = true
+			c.Webhooks.Enabled = true
+		}, wantErr: []string{"webhooks.endpoints must be set"}},
+		{name: "invalid webhook endpoints", modify: func(c *Config) {
+			c.Webhooks.Endpoints = []WebhookEndpointConfig{
+				{Name: "a", URL: "ftp://example.com"},
+				{Name: "a", URL: "https://example.com"},
+				{URL: "https://example.com"},
+			}
+		}, wantErr: []string{"webhooks.endpoints[0]: url", "webhooks.endpoints[1]: duplicate name", "webhooks.endpoints[2]: name and url"}},
+		{name: "signature mode without keys", modify: func(c *Config) { c.Signature.Mode = "enforce" }, wantErr: []string{"signature.publicKeys must be set"}},
+		{name: "unknown signature mode", modify: func(c *Config) { c.Signature.Mode = "strict" }, wantErr: []string{"signature.mode"}},
+		{name: "image verify accounts", modify: func(c *Config) { c.ImageVerify.AllowedAccounts = []string{"123456789012"} }},
+		{name: "invalid image verify account", modify: func(c *Config) {
+			c.ImageVerify.AllowedAccounts = []string{"123456789012", "12345678901a", "1234"}
+		}, wantErr: []string{"imageVerify.allowedAccounts[1]", "imageVerify.allowedAccounts[2]"}},
+		{name: "unknown tracing exporter", modify: func(c *Config) { c.Tracing.Exporter = "jaeger" }, wantErr: []string{"tracing.exporter"}},
+		{
+			// 모든 문제를 한 번에 보고합니다.
+			name: "multiple errors",
+			modify: func(c *Config) {
+				c.Repositories = nil
+				c.Server.Port = 0
+				c.Tracing.SampleRatio = 2
+			},
+			wantErr: []string{"server.port", "repositories or repositoryTag.key", "tracing.sampleRatio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// filepath: helm-ecr-api/internal/events/consumer.go
+package events
+
+import (
+	"context"
+	"errors"
+	"helm-ecr-api/internal/metrics"
+	"log/slog"
+	"time"
+)
+
+// receiveErrorBackoff는 메시지 수신에 실패했을 때 다시 시도하기 전에 기다리는 시간입니다.
+const receiveErrorBackoff = 5 * time.Second
+
+// Message는 Source에서 받은 이벤트 메시지 하나입니다.
+type Message struct {
+	ID     string // 로그에 표시할 메시지 ID
+	Body   []byte
+	handle string // 메시지를 삭제할 때 사용하는 값 (SQS receipt handle)
+}
+
+// Source는 ECR 이미지 이벤트를 전달하는 큐입니다.
+type Source interface {
+	// Receive는 새 메시지를 기다렸다가 반환합니다. 대기 시간 안에 메시지가 없으면 빈 목록을 반환합니다.
+	Receive(ctx context.Context) ([]Message, error)
+	// Ack는 처리를 마친 메시지를 큐에서 삭제합니다. 삭제하지 않은 메시지는 다시 전달될 수 있습니다.
+	Ack(ctx context.Context, msgs []Message) error
+}
+
+// Handler는 이미지 이벤트를 처리합니다. 에러를 반환하면 메시지를 삭제하지 않으므로 Source가 다시 전달할 수 있습니다.
+type Handler func(ctx context.Context, action ImageAction) error
+
+// Consumer는 Source에서 받은 ECR 이미지 이벤트를 Handler로 전달합니다.
+type Consumer struct {
+	source Source
+	handle Handler
+	logger *slog.Logger
+}
+
+// NewConsumer는 source의 이벤트를 handle로 처리하는 Consumer를 생성합니다.
+func NewConsumer(source Source, handle Handler, logger *slog.Logger) *Consumer {
+	return &Consumer{source: source, handle: handle, logger: logger}
+}
+
+// Run은 ctx가 취소될 때까지 이벤트를 받아 처리합니다. 고루틴으로 실행해야 합니다.
+// 형식이 잘못되었거나 지원하지 않는 이벤트, 실패한 작업의 이벤트는 처리하지 않고 삭제합니다.
+func (c *Consumer) Run(ctx context.Context) {
+	c.logger.Info("starting image event consumer")
+	for ctx.Err() == nil {
+		msgs, err := c.source.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Error("failed to receive image events", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(receiveErrorBackoff):
+			}
+			continue
+		}
+
+		var done []Message
+		for _, msg := range msgs {
+			if c.process(ctx, msg) {
+				done = append(done, msg)
+			}
+		}
+		if len(done) > 0 {
+			if err := c.source.Ack(ctx, done); err != nil && ctx.Err() == nil {
+				c.logger.Warn("failed to delete processed image events", "count", len(done), "error", err)
+			}
+		}
+	}
+}
+
+// process는 메시지 하나를 처리하고, 큐에서 삭제해도 되는지 반환합니다.
+func (c *Consumer) process(ctx context.Context, msg Message) bool {
+	action, err := ParseImageAction(msg.Body)
+	switch {
+	case errors.Is(err, ErrUnsupportedEvent):
+		c.logger.Debug("ignoring unsupported event", "message_id", msg.ID, "reason", err)
+		metrics.IncImageEvent("ignored")
+		return true
+	case err != nil:
+		c.logger.Warn("discarding invalid image event", "message_id", msg.ID, "error", err)
+		metrics.IncImageEvent("invalid")
+		return true
+	case action.Result != ResultSuccess:
+		c.logger.Debug("ignoring failed image action", "message_id", msg.ID, "repo", action.Repository, "action", action.Action, "result", action.Result)
+		metrics.IncImageEvent("ignored")
+		return true
+	}
+
+	if err := c.handle(ctx, *action); err != nil {
+		c.logger.Warn("failed to apply image event", "message_id", msg.ID, "repo", action.Repository, "action", action.Action, "digest", action.Digest, "tag", action.Tag, "error", err)
+		metrics.IncImageEvent("failed")
+		return false
+	}
+	c.logger.Debug("applied image event", "message_id", msg.ID, "repo", action.Repository, "action", action.Action, "digest", action.Digest, "tag", action.Tag, "lag", time.Since(action.Time).String())
+	metrics.IncImageEvent("applied")
+	return true
+}
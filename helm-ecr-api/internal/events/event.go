@@ -0,0 +1,100 @@
+// filepath: helm-ecr-api/internal/events/event.go
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ECR 이미지 이벤트 관련 상수
+// 참조: https://docs.aws.amazon.com/AmazonECR/latest/userguide/ecr-eventbridge.html
+const (
+	sourceECR             = "aws.ecr"
+	detailTypeImageAction = "ECR Image Action"
+
+	ActionPush   = "PUSH"
+	ActionDelete = "DELETE"
+
+	ResultSuccess = "SUCCESS"
+)
+
+// ErrUnsupportedEvent는 ECR 이미지 푸시/삭제 이벤트가 아닌 메시지입니다. (예: 이미지 스캔 완료 이벤트)
+// 같은 EventBridge 규칙이나 큐로 다른 이벤트가 함께 들어올 수 있으므로 에러로 처리하지 않고 무시합니다.
+var ErrUnsupportedEvent = errors.New("unsupported event")
+
+// ImageAction은 ECR 이미지 푸시 또는 삭제 이벤트입니다.
+type ImageAction struct {
+	ID         string    // EventBridge 이벤트 ID
+	Time       time.Time // 이벤트 발생 시각
+	Action     string    // ActionPush 또는 ActionDelete
+	Result     string    // ResultSuccess 또는 "FAILURE"
+	Repository string
+	Digest     string // 매니페스트 다이제스트. 태그만 삭제한 이벤트에도 포함됩니다.
+	Tag        string // 태그 없이 푸시하거나 다이제스트로 삭제하면 비어 있음
+}
+
+// eventBridgeEvent는 EventBridge 이벤트의 공통 필드입니다.
+type eventBridgeEvent struct {
+	ID         string          `json:"id"`
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Time       time.Time       `json:"time"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// imageActionDetail은 "ECR Image Action" 이벤트의 detail 필드입니다.
+type imageActionDetail struct {
+	Result         string `json:"result"`
+	RepositoryName string `json:"repository-name"`
+	ImageDigest    string `json:"image-digest"`
+	ActionType     string `json:"action-type"`
+	ImageTag       string `json:"image-tag"`
+}
+
+// snsNotification은 SNS 토픽을 거쳐 SQS로 전달된 메시지의 봉투입니다. (원시 메시지 전달을 사용하지 않는 경우)
+type snsNotification struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// ParseImageAction은 EventBridge가 전달한 ECR 이미지 이벤트(JSON)를 읽습니다.
+// SNS 봉투로 감싼 메시지도 지원합니다. ECR 이미지 푸시/삭제 이벤트가 아니면 ErrUnsupportedEvent를 반환합니다.
+func ParseImageAction(body []byte) (*ImageAction, error) {
+	var n snsNotification
+	if err := json.Unmarshal(body, &n); err == nil && n.Type == "Notification" && n.Message != "" {
+		body = []byte(n.Message)
+	}
+
+	var ev eventBridgeEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, fmt.Errorf("invalid event JSON: %w", err)
+	}
+	if ev.Source != sourceECR || ev.DetailType != detailTypeImageAction {
+		return nil, fmt.Errorf("%w: source %q, detail-type %q", ErrUnsupportedEvent, ev.Source, ev.DetailType)
+	}
+
+	var d imageActionDetail
+	if err := json.Unmarshal(ev.Detail, &d); err != nil {
+		return nil, fmt.Errorf("invalid image action detail: %w", err)
+	}
+	switch d.ActionType {
+	case ActionPush, ActionDelete:
+	default:
+		return nil, fmt.Errorf("%w: action-type %q", ErrUnsupportedEvent, d.ActionType)
+	}
+	if d.RepositoryName == "" {
+		return nil, errors.New("image action event has no repository-name")
+	}
+
+	return &ImageAction{
+		ID:         ev.ID,
+		Time:       ev.Time,
+		Action:     d.ActionType,
+		Result:     d.Result,
+		Repository: d.RepositoryName,
+		Digest:     d.ImageDigest,
+		Tag:        d.ImageTag,
+	}, nil
+}
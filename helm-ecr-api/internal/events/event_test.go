@@ -0,0 +1,132 @@
+// filepath: helm-ecr-api/internal/events/event_test.go
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+const pushEvent = `{
+	"version": "0",
+	"id": "13cde686-328b-6117-af20-0e5566167482",
+	"detail-type": "ECR Image Action",
+	"source": "aws.ecr",
+	"account": "123456789012",
+	"time": "2024-05-01T12:34:56Z",
+	"region": "us-east-1",
+	"resources": [],
+	"detail": {
+		"result": "SUCCESS",
+		"repository-name": "charts/app",
+		"image-digest": "sha256:7f5b2640fe6fb4f46592dfd3410c4a79dac4f89e4782432e0378abcd1234abcd",
+		"action-type": "PUSH",
+		"image-tag": "1.2.3"
+	}
+}`
+
+// snsEnvelope는 event를 SNS 알림 봉투로 감쌉니다.
+func snsEnvelope(t *testing.T, event string) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]string{"Type": "Notification", "MessageId": "m-1", "Message": event})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestParseImageAction(t *testing.T) {
+	want := &ImageAction{
+		ID:         "13cde686-328b-6117-af20-0e5566167482",
+		Time:       time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC),
+		Action:     ActionPush,
+		Result:     ResultSuccess,
+		Repository: "charts/app",
+		Digest:     "sha256:7f5b2640fe6fb4f46592dfd3410c4a79dac4f89e4782432e0378abcd1234abcd",
+		Tag:        "1.2.3",
+	}
+
+	tests := []struct {
+		name            string
+		body            string
+		want            *ImageAction
+		wantUnsupported bool
+		wantErr         bool
+	}{
+		{
+			name: "eventbridge event",
+			body: pushEvent,
+			want: want,
+		},
+		{
+			name: "sns envelope",
+			body: snsEnvelope(t, pushEvent),
+			want: want,
+		},
+		{
+			name: "delete by digest",
+			body: `{"id":"e-2","source":"aws.ecr","detail-type":"ECR Image Action","time":"2024-05-01T00:00:00Z",
+				"detail":{"result":"SUCCESS","repository-name":"charts/app","image-digest":"sha256:abc","action-type":"DELETE"}}`,
+			want: &ImageAction{
+				ID:         "e-2",
+				Time:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				Action:     ActionDelete,
+				Result:     ResultSuccess,
+				Repository: "charts/app",
+				Digest:     "sha256:abc",
+			},
+		},
+		{
+			name:            "scan event",
+			body:            `{"id":"e-3","source":"aws.ecr","detail-type":"ECR Image Scan","detail":{"repository-name":"charts/app"}}`,
+			wantUnsupported: true,
+		},
+		{
+			name:            "other source",
+			body:            `{"id":"e-4","source":"aws.s3","detail-type":"ECR Image Action","detail":{}}`,
+			wantUnsupported: true,
+		},
+		{
+			name:            "unknown action",
+			body:            `{"id":"e-5","source":"aws.ecr","detail-type":"ECR Image Action","detail":{"repository-name":"charts/app","action-type":"REPLICATE"}}`,
+			wantUnsupported: true,
+		},
+		{
+			name:    "missing repository",
+			body:    `{"id":"e-6","source":"aws.ecr","detail-type":"ECR Image Action","detail":{"action-type":"PUSH"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid detail",
+			body:    `{"id":"e-7","source":"aws.ecr","detail-type":"ECR Image Action","detail":"PUSH"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			body:    `PUSH charts/app:1.2.3`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseImageAction([]byte(tt.body))
+			if errors.Is(err, ErrUnsupportedEvent) != tt.wantUnsupported {
+				t.Fatalf("error = %v, want unsupported %v", err, tt.wantUnsupported)
+			}
+			if tt.wantUnsupported {
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseImageAction() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
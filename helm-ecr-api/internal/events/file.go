@@ -0,0 +1,114 @@
+// filepath: helm-ecr-api/internal/events/file.go
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"time"
+)
+
+// maxFileRead는 FileSource가 한 번에 읽는 최대 크기입니다. 이벤트 하나는 1KiB 내외입니다.
+const maxFileRead = 1 << 20
+
+// FileSource는 JSON Lines 파일에 한 줄씩 추가된 이벤트를 읽습니다.
+// SQS 없이 로컬에서 이벤트 처리를 시험할 때 사용합니다. (예: echo '{"source":"aws.ecr",...}' >> events.jsonl)
+// 서버 시작 시점의 파일 끝부터 읽으며, 파일이 잘리면(크기가 줄면) 처음부터 다시 읽습니다.
+// 읽은 줄은 처리 결과와 관계없이 다시 전달하지 않습니다.
+type FileSource struct {
+	path         string
+	pollInterval time.Duration
+	offset       int64
+	started      bool
+}
+
+// NewFileSource는 path 파일을 pollInterval 주기로 확인하는 FileSource를 생성합니다.
+func NewFileSource(path string, pollInterval time.Duration) *FileSource {
+	return &FileSource{path: path, pollInterval: pollInterval}
+}
+
+// Receive는 마지막으로 읽은 위치 이후에 추가된 완전한 줄(개행으로 끝나는 줄)을 반환합니다.
+// 새 줄이 없으면 pollInterval 동안 기다린 뒤 빈 목록을 반환합니다.
+func (f *FileSource) Receive(ctx context.Context) ([]Message, error) {
+	msgs, err := f.readNew()
+	if err != nil || len(msgs) > 0 {
+		return msgs, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(f.pollInterval):
+		return nil, nil
+	}
+}
+
+// Ack는 아무것도 하지 않습니다. 읽은 위치는 Receive에서 이미 옮겼습니다.
+func (f *FileSource) Ack(context.Context, []Message) error {
+	return nil
+}
+
+func (f *FileSource) readNew() ([]Message, error) {
+	file, err := os.Open(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// 파일이 나중에 만들어지면 처음부터 읽습니다.
+		f.started = true
+		f.offset = 0
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !f.started {
+		f.started = true
+		f.offset = info.Size()
+	}
+	if info.Size() < f.offset {
+		f.offset = 0
+	}
+	if info.Size() == f.offset {
+		return nil, nil
+	}
+
+	if _, err := file.Seek(f.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxFileRead))
+	if err != nil {
+		return nil, err
+	}
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		if len(data) == maxFileRead {
+			// 개행 없이 너무 긴 줄은 건너뜁니다.
+			f.offset += int64(len(data))
+			return nil, fmt.Errorf("event line in %s exceeds %d bytes", f.path, maxFileRead)
+		}
+		// 아직 쓰는 중인 줄은 다음에 읽습니다.
+		return nil, nil
+	}
+
+	var msgs []Message
+	lineOffset := f.offset
+	for _, line := range bytes.Split(data[:end], []byte{'\n'}) {
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			msgs = append(msgs, Message{
+				ID:   f.path + ":" + strconv.FormatInt(lineOffset, 10),
+				Body: trimmed,
+			})
+		}
+		lineOffset += int64(len(line)) + 1
+	}
+	f.offset += int64(end) + 1
+	return msgs, nil
+}
@@ -0,0 +1,134 @@
+// filepath: helm-ecr-api/internal/events/file_test.go
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFileSource는 파일에 내용을 쓰거나 자른 뒤 Receive가 반환하는 줄을 단계별로 확인합니다.
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte("{\"old\":1}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := NewFileSource(path, time.Millisecond)
+
+	steps := []struct {
+		name     string
+		append   string
+		truncate bool
+		want     []string
+		wantIDs  []string
+	}{
+		// 서버 시작 전에 있던 줄은 읽지 않습니다.
+		{name: "existing lines are skipped"},
+		{
+			name:    "new lines",
+			append:  "{\"a\":1}\n\n  {\"b\":2}  \n",
+			want:    []string{`{"a":1}`, `{"b":2}`},
+			wantIDs: []string{path + ":10", path + ":19"},
+		},
+		// 개행으로 끝나지 않은 줄은 쓰는 중일 수 있으므로 다음에 읽습니다.
+		{name: "partial line", append: `{"c":`},
+		{
+			name:    "partial line completed",
+			append:  "3}\n{\"d\":4}",
+			want:    []string{`{"c":3}`},
+			wantIDs: []string{path + ":31"},
+		},
+		{
+			name:     "truncated file is read from the start",
+			append:   "{\"e\":5}\n",
+			truncate: true,
+			want:     []string{`{"e":5}`},
+			wantIDs:  []string{path + ":0"},
+		},
+		{name: "no new lines"},
+	}
+
+	for _, s := range steps {
+		if s.truncate {
+			if err := os.WriteFile(path, []byte(s.append), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		} else if s.append != "" {
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.WriteString(s.append)
+			f.Close()
+		}
+
+		msgs, err := src.Receive(context.Background())
+		if err != nil {
+			t.Fatalf("%s: Receive() error = %v", s.name, err)
+		}
+		var bodies, ids []string
+		for _, m := range msgs {
+			bodies = append(bodies, string(m.Body))
+			ids = append(ids, m.ID)
+		}
+		if !reflect.DeepEqual(bodies, s.want) {
+			t.Errorf("%s: bodies = %q, want %q", s.name, bodies, s.want)
+		}
+		if !reflect.DeepEqual(ids, s.wantIDs) {
+			t.Errorf("%s: ids = %q, want %q", s.name, ids, s.wantIDs)
+		}
+	}
+}
+
+// TestFileSourceCreatedLater는 서버 시작 후에 만들어진 파일은 처음부터 읽는지 확인합니다.
+func TestFileSourceCreatedLater(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	src := NewFileSource(path, time.Millisecond)
+	if msgs, err := src.Receive(context.Background()); err != nil || len(msgs) != 0 {
+		t.Fatalf("Receive() = %v, %v before the file exists", msgs, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{\"a\":1}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := src.Receive(context.Background())
+	if err != nil || len(msgs) != 1 || string(msgs[0].Body) != `{"a":1}` {
+		t.Fatalf("Receive() = %v, %v", msgs, err)
+	}
+}
+
+func TestFileSourceLongLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	src := NewFileSource(path, time.Millisecond)
+	src.Receive(context.Background())
+
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", maxFileRead+10)+"\n{\"a\":1}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// 개행 없이 maxFileRead를 넘는 부분은 에러와 함께 건너뛰고, 그 뒤의 줄은 계속 읽습니다.
+	if _, err := src.Receive(context.Background()); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("Receive() error = %v, want a long line error", err)
+	}
+	msgs, err := src.Receive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bodies []string
+	for _, m := range msgs {
+		bodies = append(bodies, string(m.Body))
+	}
+	want := []string{strings.Repeat("x", 10), `{"a":1}`}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("bodies = %.40q, want %.40q", bodies, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := src.Receive(ctx); err != context.Canceled {
+		t.Errorf("Receive() with a canceled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,96 @@
+// filepath: helm-ecr-api/internal/events/sqs.go
+package events
+
+import (
+	"context"
+	"fmt"
+	"helm-ecr-api/internal/metrics"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// SQS 수신 제한 (ReceiveMessage API의 최댓값)
+const (
+	sqsMaxMessages = 10
+	sqsMaxWaitTime = 20 * time.Second
+)
+
+// SQSSource는 EventBridge 규칙이 ECR 이미지 이벤트를 전달하는 SQS 큐에서 메시지를 받습니다.
+// 로컬 테스트에서는 AWS_ENDPOINT_URL_SQS 환경 변수로 ElasticMQ 등 SQS 호환 서버를 지정할 수 있습니다.
+type SQSSource struct {
+	client            *sqs.Client
+	queueURL          string
+	waitTime          time.Duration
+	visibilityTimeout time.Duration
+}
+
+// NewSQSSource는 queueURL 큐를 롱 폴링(waitTime, 최대 20초)으로 읽는 SQSSource를 생성합니다.
+// visibilityTimeout이 0이면 큐에 설정된 값을 사용합니다. 처리에 실패한 메시지는 이 시간이 지나면 다시 전달됩니다.
+func NewSQSSource(cfg aws.Config, queueURL string, waitTime, visibilityTimeout time.Duration) *SQSSource {
+	return &SQSSource{
+		client:            sqs.NewFromConfig(cfg),
+		queueURL:          queueURL,
+		waitTime:          min(waitTime, sqsMaxWaitTime),
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+// Receive는 큐에서 최대 10개의 메시지를 받습니다.
+func (s *SQSSource) Receive(ctx context.Context) ([]Message, error) {
+	start := time.Now()
+	out, err := s.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(s.queueURL),
+		MaxNumberOfMessages: sqsMaxMessages,
+		WaitTimeSeconds:     int32(s.waitTime / time.Second),
+		VisibilityTimeout:   int32(s.visibilityTimeout / time.Second),
+	})
+	metrics.ObserveUpstream("sqs.ReceiveMessage", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive messages from %s: %w", s.queueURL, err)
+	}
+
+	msgs := make([]Message, 0, len(out.Messages))
+	for _, m := range out.Messages {
+		msgs = append(msgs, Message{
+			ID:     aws.ToString(m.MessageId),
+			Body:   []byte(aws.ToString(m.Body)),
+			handle: aws.ToString(m.ReceiptHandle),
+		})
+	}
+	return msgs, nil
+}
+
+// Ack는 처리를 마친 메시지를 DeleteMessageBatch로 삭제합니다.
+func (s *SQSSource) Ack(ctx context.Context, msgs []Message) error {
+	for len(msgs) > 0 {
+		batch := msgs[:min(len(msgs), sqsMaxMessages)]
+		msgs = msgs[len(batch):]
+
+		entries := make([]types.DeleteMessageBatchRequestEntry, len(batch))
+		for i, m := range batch {
+			entries[i] = types.DeleteMessageBatchRequestEntry{
+				Id:            aws.String(strconv.Itoa(i)),
+				ReceiptHandle: aws.String(m.handle),
+			}
+		}
+
+		start := time.Now()
+		out, err := s.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+			QueueUrl: aws.String(s.queueURL),
+			Entries:  entries,
+		})
+		metrics.ObserveUpstream("sqs.DeleteMessageBatch", start, err)
+		if err != nil {
+			return fmt.Errorf("failed to delete messages from %s: %w", s.queueURL, err)
+		}
+		if len(out.Failed) > 0 {
+			f := out.Failed[0]
+			return fmt.Errorf("failed to delete %d messages from %s: %s: %s", len(out.Failed), s.queueURL, aws.ToString(f.Code), aws.ToString(f.Message))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// filepath: helm-ecr-api/internal/handler/chart_images.go
+package handler
+
+import (
+	"fmt"
+	"helm-ecr-api/internal/service"
+	"net/http"
+	"strconv"
+
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+// GetChartImages는 차트 버전이 참조하는 컨테이너 이미지 목록을 조회하는 핸들러입니다.
+// 차트를 기본 values로 렌더링하며, set 쿼리 파라미터로 helm --set과 같은 형식의 값을 덮어쓸 수 있습니다.
+// verify=true이면 각 이미지가 ECR에 있는지 확인하고, scan=true이면 이미지 스캔 결과(심각도별 취약점 수)도 함께 반환합니다.
+// 예: GET /v1/helm-charts/my-repo/my-app/images?tag=1.2.3&set=image.tag=1.25,worker.enabled=true
+func (h *HelmHandler) GetChartImages(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := r.Context().Value(chartNameKey).(string)
+	if !ok || repoName == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "missing repository name in URL path")
+		return
+	}
+	tag := r.URL.Query().Get("tag")
+	digest := r.URL.Query().Get("digest")
+
+	if tag == "" && digest == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "tag or digest is required")
+		return
+	}
+
+	if tag != "" && digest != "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "tag and digest cannot be specified simultaneously")
+		return
+	}
+
+	values, err := parseSetValues(r)
+	if err != nil {
+		h.respondError(w, r, service.CodeInvalidArgument, err.Error())
+		return
+	}
+
+	verify, err := parseBoolQuery(r, "verify")
+	if err != nil {
+		h.respondError(w, r, service.CodeInvalidArgument, err.Error())
+		return
+	}
+	scan, err := parseBoolQuery(r, "scan")
+	if err != nil {
+		h.respondError(w, r, service.CodeInvalidArgument, err.Error())
+		return
+	}
+
+	h.log(r).Info("request to list chart images", "repo", repoName, "tag", tag, "digest", digest, "verify", verify || scan)
+
+	// tag가 렌더링 도중 다른 버전으로 옮겨지더라도 서명을 확인한 버전을 렌더링하도록 다이제스트로 고정합니다.
+	manifestDigest, err := h.chartService.ResolveDigest(r.Context(), repoName, tag, digest)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to resolve chart digest", err)
+		return
+	}
+	sig, ok := h.verifySignature(w, r, repoName, manifestDigest)
+	if !ok {
+		return
+	}
+
+	images, err := h.chartService.ListChartImages(r.Context(), repoName, "", manifestDigest, values)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to list chart images", err)
+		return
+	}
+	images.Signature = sig
+	// 스캔 결과는 ECR에서 찾은 이미지에 대해서만 조회할 수 있으므로 scan=true는 verify=true를 포함합니다.
+	if verify || scan {
+		if err := h.chartService.VerifyChartImages(r.Context(), images, scan); err != nil {
+			h.respondServiceError(w, r, "failed to verify chart images", err)
+			return
+		}
+	}
+
+	// 같은 digest와 values로 렌더링한 결과는 항상 같으므로 digest로 요청한 경우 변경 불가능한 응답으로 캐시합니다.
+	// ECR 확인 결과는 이미지 푸시나 스캔에 따라, 서명 검증 결과는 서명 추가나 신뢰 정책 변경에 따라 바뀌므로 매번 재검증하도록 합니다.
+	h.writeCacheableJSON(w, r, images, digest != "" && !verify && !scan && sig == nil)
+}
+
+// parseSetValues는 set 쿼리 파라미터를 helm --set과 같은 방식으로 values에 병합합니다. 뒤에 지정한 값이 앞의 값을 덮어씁니다.
+func parseSetValues(r *http.Request) (map[string]any, error) {
+	values := map[string]any{}
+	for _, set := range r.URL.Query()["set"] {
+		if err := strvals.ParseInto(set, values); err != nil {
+			return nil, fmt.Errorf("invalid set parameter: %w", err)
+		}
+	}
+	return values, nil
+}
+
+// parseBoolQuery는 true/false 값을 가지는 쿼리 파라미터를 읽습니다. 값이 없으면 false입니다.
+func parseBoolQuery(r *http.Request, key string) (bool, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s parameter: %q", key, v)
+	}
+	return b, nil
+}
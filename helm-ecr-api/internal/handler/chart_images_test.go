@@ -0,0 +1,54 @@
+// filepath: helm-ecr-api/internal/handler/chart_images_test.go
+package handler
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseSetValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    map[string]any
+		wantErr bool
+	}{
+		{name: "none", query: "", want: map[string]any{}},
+		{name: "nested key", query: "set=image.tag=1.26", want: map[string]any{"image": map[string]any{"tag": "1.26"}}},
+		{
+			name:  "comma separated",
+			query: "set=image.tag=1.26,worker.enabled=true",
+			want:  map[string]any{"image": map[string]any{"tag": "1.26"}, "worker": map[string]any{"enabled": true}},
+		},
+		{
+			name:  "repeated parameters are merged",
+			query: "set=image.repository=nginx&set=image.tag=1.26&set=replicas=3",
+			want:  map[string]any{"image": map[string]any{"repository": "nginx", "tag": "1.26"}, "replicas": int64(3)},
+		},
+		{name: "later value wins", query: "set=image.tag=1.25&set=image.tag=1.26", want: map[string]any{"image": map[string]any{"tag": "1.26"}}},
+		{name: "list index", query: "set=hosts[1]=b.example.com", want: map[string]any{"hosts": []any{nil, "b.example.com"}}},
+		{name: "escaped comma", query: `set=annotation=a\,b`, want: map[string]any{"annotation": "a,b"}},
+		{name: "missing value", query: "set=image.tag", wantErr: true},
+		{name: "unterminated list index", query: "set=hosts[1=a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/helm-charts/app/images?"+tt.query, nil)
+			got, err := parseSetValues(r)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseSetValues() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSetValues() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSetValues() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// filepath: helm-ecr-api/internal/handler/chart_lint.go
+package handler
+
+import (
+	"helm-ecr-api/internal/service"
+	"net/http"
+)
+
+// GetChartLint는 차트 버전의 구조를 검사한 결과를 반환하는 핸들러입니다.
+// 차트에 문제가 있어도 200으로 응답하며, passed와 findings의 severity로 승격 여부를 판단할 수 있습니다.
+// kubeVersion 쿼리 파라미터로 대상 클러스터 버전을 지정하면 해당 버전에서 제거되거나 지원 중단된 API와 차트의 kubeVersion 제약 조건을 확인합니다.
+// 예: GET /v1/helm-charts/my-repo/my-app/lint?tag=1.2.3&kubeVersion=1.30
+func (h *HelmHandler) GetChartLint(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := r.Context().Value(chartNameKey).(string)
+	if !ok || repoName == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "missing repository name in URL path")
+		return
+	}
+	tag := r.URL.Query().Get("tag")
+	digest := r.URL.Query().Get("digest")
+	kubeVersion := r.URL.Query().Get("kubeVersion")
+
+	if tag == "" && digest == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "tag or digest is required")
+		return
+	}
+
+	if tag != "" && digest != "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "tag and digest cannot be specified simultaneously")
+		return
+	}
+
+	h.log(r).Info("request to lint chart", "repo", repoName, "tag", tag, "digest", digest, "kube_version", kubeVersion)
+
+	manifestDigest, err := h.chartService.ResolveDigest(r.Context(), repoName, tag, digest)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to resolve chart digest", err)
+		return
+	}
+	if _, ok := h.verifySignature(w, r, repoName, manifestDigest); !ok {
+		return
+	}
+
+	report, err := h.chartService.LintChart(r.Context(), repoName, "", manifestDigest, kubeVersion)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to lint chart", err)
+		return
+	}
+
+	// 같은 digest의 린트 결과는 항상 같으므로 digest로 요청한 경우 변경 불가능한 응답으로 캐시합니다.
+	h.writeCacheableJSON(w, r, report, digest != "")
+}
@@ -0,0 +1,318 @@
+// filepath: helm-ecr-api/internal/handler/content.go
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"helm-ecr-api/internal/selector"
+	"helm-ecr-api/internal/service"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	yamlv2 "go.yaml.in/yaml/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// fileFormat은 차트 파일을 응답할 표현 형식입니다.
+type fileFormat string
+
+const (
+	formatRaw  fileFormat = "raw"  // 아카이브에 저장된 그대로
+	formatJSON fileFormat = "json" // YAML 파일을 JSON으로 변환
+	formatYAML fileFormat = "yaml" // JSON 파일을 YAML로 변환
+)
+
+const (
+	jsonContentType = "application/json"
+	// YAML의 공식 IANA MIME 타입은 RFC 9512에서 application/yaml로 등록되었지만, 기존 클라이언트와의 호환을 위해 관례적인 타입을 유지합니다.
+	yamlContentType = "application/x-yaml; charset=utf-8"
+)
+
+// 형식별로 Accept 헤더에서 인정하는 미디어 타입 목록
+var (
+	jsonMediaTypes = []string{"application/json"}
+	yamlMediaTypes = []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"}
+)
+
+// negotiationError는 요청한 형식으로 응답할 수 없을 때의 에러 응답 내용입니다.
+type negotiationError struct {
+	status int
+	code   string
+	detail string
+}
+
+// sourceFormat은 파일 확장자로 변환 가능한 원본 형식(json, yaml)을 판별합니다. 그 외 파일은 formatRaw를 반환합니다.
+func sourceFormat(fileName string) fileFormat {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".json":
+		return formatJSON
+	case ".yaml", ".yml":
+		return formatYAML
+	default:
+		return formatRaw
+	}
+}
+
+// negotiateFormat은 ?format= 쿼리 파라미터 또는 Accept 헤더로 응답 형식을 결정합니다.
+//   - ?format=json|yaml|raw가 있으면 Accept 헤더보다 우선합니다.
+//   - Accept 헤더는 YAML/JSON 파일에만 적용하며, 원본 형식과 변환 형식 중 q 값이 높은 쪽을 선택합니다. (같으면 원본)
+//   - 어느 형식도 허용되지 않으면 406으로 응답합니다.
+func negotiateFormat(r *http.Request, fileName string) (fileFormat, *negotiationError) {
+	src := sourceFormat(fileName)
+
+	if requested := r.URL.Query().Get("format"); requested != "" {
+		format := fileFormat(requested)
+		switch format {
+		case formatRaw:
+			return formatRaw, nil
+		case formatJSON, formatYAML:
+			if src == formatRaw {
+				return "", &negotiationError{http.StatusBadRequest, string(service.CodeInvalidArgument),
+					"format conversion is only supported for YAML and JSON files"}
+			}
+			if format == src {
+				return formatRaw, nil
+			}
+			return format, nil
+		default:
+			return "", &negotiationError{http.StatusBadRequest, string(service.CodeInvalidArgument),
+				fmt.Sprintf("unsupported format %q, must be one of raw, json, yaml", requested)}
+		}
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" || src == formatRaw {
+		return formatRaw, nil
+	}
+
+	ranges := parseAccept(accept)
+	srcQ, convQ := ranges.quality(mediaTypesFor(src)), ranges.quality(mediaTypesFor(convertedFormat(src)))
+	switch {
+	case srcQ == 0 && convQ == 0:
+		return "", &negotiationError{http.StatusNotAcceptable, "not_acceptable",
+			"file can only be returned as application/json or application/yaml"}
+	case convQ > srcQ:
+		return convertedFormat(src), nil
+	default:
+		return formatRaw, nil
+	}
+}
+
+// convertedFormat은 원본 형식을 변환할 대상 형식을 반환합니다.
+func convertedFormat(src fileFormat) fileFormat {
+	if src == formatJSON {
+		return formatYAML
+	}
+	return formatJSON
+}
+
+func mediaTypesFor(format fileFormat) []string {
+	if format == formatJSON {
+		return jsonMediaTypes
+	}
+	return yamlMediaTypes
+}
+
+// acceptRange는 Accept 헤더의 미디어 범위 하나입니다. (예: "application/*;q=0.5")
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+type acceptRanges []acceptRange
+
+// parseAccept는 Accept 헤더를 파싱합니다. 파싱할 수 없는 항목은 무시합니다.
+func parseAccept(header string) acceptRanges {
+	var ranges acceptRanges
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if _, err := fmt.Sscanf(v, "%g", &q); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// quality는 mediaTypes 중 하나에 대해 가장 구체적으로 일치하는 범위의 q 값 중 최댓값을 반환합니다.
+// RFC 9110 12.5.1에 따라 "type/subtype"이 "type/*"보다, "type/*"이 "*/*"보다 우선합니다.
+func (ranges acceptRanges) quality(mediaTypes []string) float64 {
+	best := 0.0
+	for _, mediaType := range mediaTypes {
+		typ, _, _ := strings.Cut(mediaType, "/")
+		q, specificity := 0.0, -1
+		for _, rng := range ranges {
+			var s int
+			switch rng.mediaType {
+			case mediaType:
+				s = 2
+			case typ + "/*":
+				s = 1
+			case "*/*":
+				s = 0
+			default:
+				continue
+			}
+			if s > specificity {
+				q, specificity = rng.q, s
+			}
+		}
+		best = max(best, q)
+	}
+	return best
+}
+
+// rawContentType은 원본 그대로 응답할 파일의 Content-Type을 확장자로 결정합니다.
+// 확장자로 알 수 없는 파일은 빈 문자열을 반환하며, 이때는 내용으로 판별해야 합니다.
+func rawContentType(fileName string) string {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".json":
+		return jsonContentType
+	case ".yaml", ".yml":
+		return yamlContentType
+	case ".tpl", ".txt":
+		// .tpl은 Go 템플릿 헬퍼(_helpers.tpl), .txt는 NOTES.txt 등입니다.
+		return "text/plain; charset=utf-8"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	default:
+		return ""
+	}
+}
+
+// fileRepresentation은 차트 파일을 요청한 형식의 응답 본문으로 바꿉니다.
+//   - sel이 있으면 선택한 값만 format(원본이면 파일과 같은 형식)으로 직렬화합니다.
+//   - formatRaw는 원본을 스트리밍하며, 확장자로 타입을 알 수 없으면 앞부분 512바이트로 판별합니다.
+//   - formatJSON/formatYAML은 변환을 위해 파일 전체를 읽습니다. 파일 크기는 archive.maxFileSize로 이미 제한되어 있습니다.
+func fileRepresentation(file *service.ChartFile, fileName string, format fileFormat, sel *selector.Path) (body io.Reader, size int64, contentType string, err error) {
+	if sel != nil {
+		return selectValue(file.Body, fileName, format, sel)
+	}
+
+	switch format {
+	case formatJSON, formatYAML:
+		data, err := io.ReadAll(file.Body)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		var converted []byte
+		if format == formatJSON {
+			converted, err = yamlToJSON(data)
+			contentType = jsonContentType
+		} else {
+			converted, err = yaml.JSONToYAML(data)
+			contentType = yamlContentType
+		}
+		if err != nil {
+			return nil, 0, "", &conversionError{err: err}
+		}
+		return bytes.NewReader(converted), int64(len(converted)), contentType, nil
+	default:
+		if contentType = rawContentType(fileName); contentType != "" {
+			return file.Body, file.Size, contentType, nil
+		}
+		// http.DetectContentType은 최대 512바이트만 사용하므로 그만큼만 미리 읽습니다.
+		buffered := bufio.NewReaderSize(file.Body, 512)
+		head, err := buffered.Peek(512)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, 0, "", err
+		}
+		return buffered, file.Size, http.DetectContentType(head), nil
+	}
+}
+
+// selectValue는 YAML/JSON 파일에서 sel이 가리키는 값을 찾아 직렬화합니다.
+// 값이 없으면 selector.ErrNotFound를 감싼 에러를 반환합니다.
+func selectValue(r io.Reader, fileName string, format fileFormat, sel *selector.Path) (io.Reader, int64, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	// YAML은 JSON의 상위 집합이므로 JSON 파일도 같은 방법으로 읽습니다.
+	// 큰 정수가 float64로 바뀌어 정밀도를 잃지 않도록 UseNumber를 사용합니다.
+	jsonData, err := yamlToJSON(data)
+	if err != nil {
+		return nil, 0, "", &conversionError{err: err}
+	}
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, 0, "", &conversionError{err: err}
+	}
+
+	value, err := sel.Select(doc)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	if format == formatRaw {
+		format = sourceFormat(fileName)
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return nil, 0, "", &conversionError{err: err}
+	}
+	contentType := jsonContentType
+	if format == formatYAML {
+		if out, err = yaml.JSONToYAML(out); err != nil {
+			return nil, 0, "", &conversionError{err: err}
+		}
+		contentType = yamlContentType
+	} else {
+		out = append(out, '\n')
+	}
+	return bytes.NewReader(out), int64(len(out)), contentType, nil
+}
+
+// errMultiDocument는 YAML 파일에 문서가 여러 개 있어 JSON 값 하나로 변환할 수 없음을 나타냅니다.
+var errMultiDocument = errors.New("file contains multiple YAML documents")
+
+// yamlToJSON은 YAML 문서 하나를 JSON으로 변환합니다. 앵커와 병합 키(<<)는 펼쳐서 변환합니다.
+// yaml.YAMLToJSON은 첫 번째 문서만 변환하고 나머지를 조용히 버리므로, 먼저 문서 수를 확인하여
+// 내용이 있는 문서가 둘 이상이면 errMultiDocument를 반환합니다. (앞뒤의 빈 문서는 허용)
+func yamlToJSON(data []byte) ([]byte, error) {
+	decoder := yamlv2.NewDecoder(bytes.NewReader(data))
+	for docs := 0; ; {
+		var doc any
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			if docs++; docs > 1 {
+				return nil, errMultiDocument
+			}
+		}
+	}
+	return yaml.YAMLToJSON(data)
+}
+
+// conversionError는 파일 내용이 올바른 YAML/JSON이 아니어서 변환할 수 없음을 나타냅니다.
+type conversionError struct {
+	err error
+}
+
+func (e *conversionError) Error() string {
+	return "failed to convert file: " + e.err.Error()
+}
+
+func (e *conversionError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,291 @@
+// filepath: helm-ecr-api/internal/handler/content_test.go
+package handler
+
+import (
+	"errors"
+	"helm-ecr-api/internal/selector"
+	"helm-ecr-api/internal/service"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		query      string
+		accept     string
+		want       fileFormat
+		wantStatus int // 0이면 에러 없이 want 형식이어야 함
+	}{
+		{name: "no accept", file: "values.yaml", want: formatRaw},
+		{name: "same type", file: "values.yaml", accept: "application/yaml", want: formatRaw},
+		{name: "alias type", file: "values.yaml", accept: "text/x-yaml", want: formatRaw},
+		{name: "converted type", file: "values.yaml", accept: "application/json", want: formatJSON},
+		{name: "json to yaml", file: "schema.json", accept: "application/yaml", want: formatYAML},
+		{name: "yml extension", file: "ci/test.yml", accept: "application/json", want: formatJSON},
+
+		// q 값이 높은 형식을 선택하고, 같으면 원본 형식을 선택합니다.
+		{name: "higher q wins", file: "values.yaml", accept: "application/json, application/yaml;q=0.5", want: formatJSON},
+		{name: "lower q loses", file: "values.yaml", accept: "application/json;q=0.5, application/yaml", want: formatRaw},
+		{name: "equal q keeps source", file: "values.yaml", accept: "application/json;q=0.8, text/yaml;q=0.8", want: formatRaw},
+		{name: "best alias counts", file: "schema.json", accept: "application/json;q=0.4, application/yaml;q=0.1, text/yaml;q=0.9", want: formatYAML},
+		{name: "tiny q", file: "values.yaml", accept: "application/json;q=0.001", want: formatJSON},
+		{name: "q with spaces", file: "values.yaml", accept: "application/yaml ; q=0.2 , application/json ; q=0.3", want: formatJSON},
+
+		// 구체적인 미디어 범위가 와일드카드보다 우선합니다.
+		{name: "any type", file: "values.yaml", accept: "*/*", want: formatRaw},
+		{name: "type wildcard", file: "schema.json", accept: "application/*", want: formatRaw},
+		{name: "specific over type wildcard", file: "values.yaml", accept: "application/*;q=0.2, application/json", want: formatJSON},
+		{name: "specific over any", file: "values.yaml", accept: "*/*;q=0.1, application/json;q=0.5", want: formatJSON},
+		{name: "type wildcard over any", file: "schema.json", accept: "*/*;q=0.9, text/*;q=0.1, application/*;q=0.5", want: formatRaw},
+		{name: "wildcard excluded by specific q=0", file: "values.yaml", accept: "application/*, application/yaml;q=0, application/x-yaml;q=0, text/*;q=0", want: formatJSON},
+
+		// 원본과 변환 형식이 모두 허용되지 않으면 406으로 응답합니다.
+		{name: "unsupported type", file: "values.yaml", accept: "text/html", wantStatus: http.StatusNotAcceptable},
+		{name: "all refused", file: "values.yaml", accept: "application/json;q=0, application/yaml;q=0, text/yaml;q=0, application/x-yaml;q=0, text/x-yaml;q=0", wantStatus: http.StatusNotAcceptable},
+		{name: "wildcard refused", file: "schema.json", accept: "text/html, application/*;q=0, text/*;q=0", wantStatus: http.StatusNotAcceptable},
+		{name: "invalid q ignored", file: "values.yaml", accept: "application/json;q=abc, application/yaml;q=2", wantStatus: http.StatusNotAcceptable},
+		{name: "malformed ranges ignored", file: "values.yaml", accept: "json, ;q=1", wantStatus: http.StatusNotAcceptable},
+
+		// YAML/JSON이 아닌 파일은 Accept 헤더와 관계없이 원본으로 응답합니다.
+		{name: "non convertible file", file: "templates/_helpers.tpl", accept: "application/json", want: formatRaw},
+		{name: "non convertible file with unsupported type", file: "README.md", accept: "text/html", want: formatRaw},
+
+		// ?format=은 Accept 헤더보다 우선합니다.
+		{name: "format over accept", file: "values.yaml", query: "json", accept: "application/yaml", want: formatJSON},
+		{name: "format same as source", file: "values.yaml", query: "yaml", accept: "application/json", want: formatRaw},
+		{name: "format raw over unsupported accept", file: "values.yaml", query: "raw", accept: "text/html", want: formatRaw},
+		{name: "format on non convertible file", file: "README.md", query: "json", wantStatus: http.StatusBadRequest},
+		{name: "unknown format", file: "values.yaml", query: "xml", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/v1/helm-charts/app/files/" + tt.file
+			if tt.query != "" {
+				target += "?format=" + tt.query
+			}
+			r := httptest.NewRequest("GET", target, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			got, negErr := negotiateFormat(r, tt.file)
+			if tt.wantStatus != 0 {
+				if negErr == nil || negErr.status != tt.wantStatus {
+					t.Fatalf("negotiateFormat() = %q, %+v, want status %d", got, negErr, tt.wantStatus)
+				}
+				return
+			}
+			if negErr != nil || got != tt.want {
+				t.Errorf("negotiateFormat() = %q, %+v, want %q", got, negErr, tt.want)
+			}
+		})
+	}
+}
+
+// chartFile은 content를 본문으로 하는 테스트용 차트 파일을 만듭니다.
+func chartFile(content string) *service.ChartFile {
+	return &service.ChartFile{Body: io.NopCloser(strings.NewReader(content)), Size: int64(len(content))}
+}
+
+func TestFileRepresentation(t *testing.T) {
+	const anchored = `defaults: &defaults
+  replicas: 2
+  image:
+    repository: app
+resources: &resources
+  limits:
+    cpu: 500m
+web:
+  <<: *defaults
+  replicas: 3
+  resources: *resources
+`
+
+	tests := []struct {
+		name            string
+		file            string
+		content         string
+		format          fileFormat
+		want            string
+		wantContentType string
+		wantErr         error // conversionError로 감싼 원인 에러 (nil이면 원인과 관계없이 conversionError인지만 확인)
+		wantConvErr     bool
+	}{
+		{
+			name:            "yaml to json",
+			file:            "values.yaml",
+			content:         "replicaCount: 1\nimage:\n  tag: \"1.0\"\n  pullPolicy: IfNotPresent\n",
+			format:          formatJSON,
+			want:            `{"image":{"pullPolicy":"IfNotPresent","tag":"1.0"},"replicaCount":1}`,
+			wantContentType: jsonContentType,
+		},
+		{
+			name:            "json to yaml",
+			file:            "values.schema.json",
+			content:         `{"type":"object","required":["image"],"properties":{"image":{"type":"string"}}}`,
+			format:          formatYAML,
+			want:            "properties:\n  image:\n    type: string\nrequired:\n- image\ntype: object\n",
+			wantContentType: yamlContentType,
+		},
+		{
+			// 앵커, 별칭, 병합 키(<<)는 펼쳐서 변환하며, 병합한 키보다 직접 지정한 키가 우선합니다.
+			name:            "anchors and merge keys",
+			file:            "values.yaml",
+			content:         anchored,
+			format:          formatJSON,
+			want:            `{"defaults":{"image":{"repository":"app"},"replicas":2},"resources":{"limits":{"cpu":"500m"}},"web":{"image":{"repository":"app"},"replicas":3,"resources":{"limits":{"cpu":"500m"}}}}`,
+			wantContentType: jsonContentType,
+		},
+		{
+			name:            "leading document marker",
+			file:            "values.yaml",
+			content:         "---\nname: app\n",
+			format:          formatJSON,
+			want:            `{"name":"app"}`,
+			wantContentType: jsonContentType,
+		},
+		{
+			name:            "trailing empty document",
+			file:            "values.yaml",
+			content:         "name: app\n---\n# 주석만 있는 문서\n",
+			format:          formatJSON,
+			want:            `{"name":"app"}`,
+			wantContentType: jsonContentType,
+		},
+		{
+			name:            "document marker in block scalar",
+			file:            "values.yaml",
+			content:         "notes: |\n  ---\n  done\n",
+			format:          formatJSON,
+			want:            `{"notes":"---\ndone\n"}`,
+			wantContentType: jsonContentType,
+		},
+		{
+			// 여러 문서를 JSON 값 하나로 바꾸면 첫 문서 외의 내용이 사라지므로 변환하지 않습니다.
+			name:        "multiple documents",
+			file:        "ci/values.yaml",
+			content:     "name: first\n---\nname: second\n",
+			format:      formatJSON,
+			wantErr:     errMultiDocument,
+			wantConvErr: true,
+		},
+		{
+			name:        "invalid later document",
+			file:        "values.yaml",
+			content:     "name: app\n---\nlist: [\n",
+			format:      formatJSON,
+			wantConvErr: true,
+		},
+		{
+			name:        "invalid yaml",
+			file:        "values.yaml",
+			content:     "image: [unclosed\n",
+			format:      formatJSON,
+			wantConvErr: true,
+		},
+		{
+			name:        "invalid json",
+			file:        "values.schema.json",
+			content:     `{"type": "object",`,
+			format:      formatYAML,
+			wantConvErr: true,
+		},
+		{
+			// 원본 그대로 응답할 때는 여러 문서도 변환하지 않고 그대로 반환합니다.
+			name:            "raw multiple documents",
+			file:            "values.yaml",
+			content:         "name: first\n---\nname: second\n",
+			format:          formatRaw,
+			want:            "name: first\n---\nname: second\n",
+			wantContentType: yamlContentType,
+		},
+		{
+			name:            "raw detected content type",
+			file:            "LICENSE",
+			content:         "Apache License\n",
+			format:          formatRaw,
+			want:            "Apache License\n",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, size, contentType, err := fileRepresentation(chartFile(tt.content), tt.file, tt.format, nil)
+			if tt.wantConvErr {
+				var convErr *conversionError
+				if !errors.As(err, &convErr) {
+					t.Fatalf("error = %v, want conversionError", err)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fileRepresentation() error = %v", err)
+			}
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want || size != int64(len(tt.want)) {
+				t.Errorf("body = %q (size %d), want %q", got, size, tt.want)
+			}
+			if contentType != tt.wantContentType {
+				t.Errorf("content type = %q, want %q", contentType, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestFileRepresentationSelect(t *testing.T) {
+	const values = "defaults: &defaults\n  tag: \"1.0\"\nweb:\n  <<: *defaults\n  port: 8080\n"
+
+	tests := []struct {
+		name            string
+		content         string
+		path            string
+		format          fileFormat
+		want            string
+		wantContentType string
+		wantErr         error
+	}{
+		{name: "merged value", content: values, path: ".web.tag", format: formatRaw, want: "\"1.0\"\n", wantContentType: yamlContentType},
+		{name: "merged map as json", content: values, path: ".web", format: formatJSON, want: `{"port":8080,"tag":"1.0"}` + "\n", wantContentType: jsonContentType},
+		{name: "missing value", content: values, path: ".web.image", format: formatRaw, wantErr: selector.ErrNotFound},
+		{name: "multiple documents", content: "a: 1\n---\na: 2\n", path: ".a", format: formatRaw, wantErr: errMultiDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, err := selector.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _, contentType, err := fileRepresentation(chartFile(tt.content), "values.yaml", tt.format, sel)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fileRepresentation() error = %v", err)
+			}
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want || contentType != tt.wantContentType {
+				t.Errorf("fileRepresentation() = %q (%s), want %q (%s)", got, contentType, tt.want, tt.wantContentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// filepath: helm-ecr-api/internal/handler/etag.go
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"helm-ecr-api/internal/service"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// chartFileETag는 차트 매니페스트 다이제스트와 파일 경로로 강한(strong) ETag를 생성합니다.
+// 다이제스트가 같은 차트의 파일 내용은 바뀌지 않으므로 파일을 내려받기 전에 ETag를 결정할 수 있습니다.
+func chartFileETag(manifestDigest, filePath string) string {
+	sum := sha256.Sum256([]byte(manifestDigest + "\x00" + filePath))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// contentETag는 응답 본문의 해시로 강한 ETag를 생성합니다.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches는 If-None-Match 헤더에 etag가 포함되어 있거나 값이 "*"인지 확인합니다.
+// "*"는 리소스가 있기만 하면 일치하므로 응답할 리소스를 찾은 뒤에 사용해야 합니다.
+func etagMatches(r *http.Request, etag string) bool {
+	return ifNoneMatch(r, etag, true)
+}
+
+// etagListed는 If-None-Match 헤더에 etag가 나열되어 있는지 확인합니다. "*"는 일치하지 않는 것으로 처리합니다.
+// 리소스가 있는지 확인하기 전에 304로 응답할 때 사용하여, 없는 파일에 대한 "*" 요청이 404 대신 304를 받지 않도록 합니다.
+func etagListed(r *http.Request, etag string) bool {
+	return ifNoneMatch(r, etag, false)
+}
+
+// ifNoneMatch는 If-None-Match 헤더를 etag와 비교합니다.
+// RFC 9110 13.1.2에 따라 약한 비교를 사용하므로 "W/" 접두사는 무시합니다.
+func ifNoneMatch(r *http.Request, etag string, wildcard bool) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if (wildcard && candidate == "*") || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl은 응답의 Cache-Control 값을 반환합니다.
+//   - immutable이 true이면(digest로 요청한 파일) 내용이 바뀌지 않으므로 1년 동안 재검증 없이 캐시합니다.
+//   - 그 외에는 tag가 다른 버전으로 옮겨질 수 있으므로 캐시하더라도 매번 ETag로 재검증하도록 합니다.
+func cacheControl(immutable bool) string {
+	if immutable {
+		return "public, max-age=31536000, immutable"
+	}
+	return "public, no-cache"
+}
+
+// writeCacheableJSON은 v를 JSON으로 인코딩하여 본문 해시를 ETag로 하는 응답을 씁니다.
+// If-None-Match가 ETag와 일치하면 본문 없이 304로 응답합니다. immutable의 의미는 cacheControl과 같습니다.
+// 응답에 함께 보낼 다른 헤더는 호출하기 전에 설정해야 합니다.
+func (h *HelmHandler) writeCacheableJSON(w http.ResponseWriter, r *http.Request, v any, immutable bool) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to encode response", err)
+		return
+	}
+	etag := contentETag(body)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl(immutable))
+	if etagMatches(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
+
+// setFileCacheHeaders는 차트 파일 응답(200 또는 304)의 캐시 관련 헤더를 설정합니다.
+func setFileCacheHeaders(w http.ResponseWriter, r *http.Request, etag string, byDigest bool) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl(byDigest))
+}
+
+// setFreshnessHeaders는 응답 데이터의 출처(live 또는 catalog)와 ECR에서 조회한 시각을 헤더로 알립니다.
+// 클라이언트는 X-Data-Synced-At으로 카탈로그 응답이 얼마나 오래된 정보인지 판단할 수 있습니다.
+func setFreshnessHeaders(w http.ResponseWriter, f service.Freshness) {
+	if f.Source == "" {
+		return
+	}
+	w.Header().Set("X-Data-Source", string(f.Source))
+	w.Header().Set("X-Data-Synced-At", f.SyncedAt.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,83 @@
+// filepath: helm-ecr-api/internal/handler/etag_test.go
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestETagMatches(t *testing.T) {
+	const etag = `"abc"`
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantMatches bool
+		wantListed  bool
+	}{
+		{name: "no header"},
+		{name: "same etag", ifNoneMatch: `"abc"`, wantMatches: true, wantListed: true},
+		{name: "weak etag", ifNoneMatch: `W/"abc"`, wantMatches: true, wantListed: true},
+		{name: "one of several", ifNoneMatch: `"old", "abc"`, wantMatches: true, wantListed: true},
+		{name: "without spaces", ifNoneMatch: `"old","abc"`, wantMatches: true, wantListed: true},
+		{name: "different etag", ifNoneMatch: `"old"`},
+		{name: "unquoted", ifNoneMatch: `abc`},
+		// "*"는 리소스가 있기만 하면 일치하므로 etagListed는 일치하지 않는 것으로 처리합니다.
+		{name: "wildcard", ifNoneMatch: `*`, wantMatches: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			if got := etagMatches(r, etag); got != tt.wantMatches {
+				t.Errorf("etagMatches() = %v, want %v", got, tt.wantMatches)
+			}
+			if got := etagListed(r, etag); got != tt.wantListed {
+				t.Errorf("etagListed() = %v, want %v", got, tt.wantListed)
+			}
+		})
+	}
+}
+
+func TestWriteCacheableJSON(t *testing.T) {
+	h := &HelmHandler{}
+	payload := map[string]string{"name": "app"}
+
+	w := httptest.NewRecorder()
+	h.writeCacheableJSON(w, httptest.NewRequest(http.MethodGet, "/", nil), payload, true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Body.String(); got != "{\"name\":\"app\"}\n" {
+		t.Errorf("body = %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+
+	// 같은 내용을 ETag와 함께 다시 요청하면 본문 없이 304로 응답합니다.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.writeCacheableJSON(w, r, payload, false)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("status = %d, body = %q, want empty 304", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("ETag"); got != etag {
+		t.Errorf("ETag = %q, want %q", got, etag)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "public, no-cache" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+// filepath: helm-ecr-api/internal/handler/health_handler.go
+package handler
+
+import (
+	"helm-ecr-api/internal/service"
+	"log/slog"
+	"net/http"
+)
+
+// HealthHandler는 Kubernetes liveness/readiness 프로브 요청을 처리합니다.
+type HealthHandler struct {
+	checker service.ReadinessChecker
+	logger  *slog.Logger
+}
+
+// NewHealthHandler는 HealthHandler의 새 인스턴스를 생성합니다.
+func NewHealthHandler(checker service.ReadinessChecker, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// Livez는 프로세스가 살아 있는지만 확인하는 핸들러입니다.
+// 외부 의존성(AWS)을 점검하지 않으므로, AWS 장애로 인해 Pod가 불필요하게 재시작되지 않습니다.
+// 예: GET /livez
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.logger, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readyz는 ECR에서 차트를 제공할 준비가 되었는지 점검하는 핸들러입니다.
+// 점검에 실패하면 503을 반환하여 Kubernetes가 해당 Pod로 트래픽을 보내지 않도록 합니다.
+// 예: GET /readyz
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.CheckReadiness(r.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, h.logger, status, report)
+}
@@ -5,13 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"helm-ecr-api/internal/logging"
+	"helm-ecr-api/internal/middleware"
+	"helm-ecr-api/internal/problem"
+	"helm-ecr-api/internal/selector"
 	"helm-ecr-api/internal/service"
 	"log/slog"
+	"math"
 	"net/http"
-	"regexp"
+	"strconv"
 	"strings"
-
-	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
 )
 
 // contextKey는 컨텍스트 값의 키로 사용되어 충돌을 방지합니다.
@@ -26,7 +30,6 @@ const (
 type HelmHandler struct {
 	chartService service.ChartService
 	logger       *slog.Logger
-	filePathPattern  *regexp.Regexp
 }
 
 // NewHelmHandler는 HelmHandler의 새 인스턴스를 생성합니다.
@@ -34,8 +37,6 @@ func NewHelmHandler(chartService service.ChartService, logger *slog.Logger) *Hel
 	return &HelmHandler{
 		chartService: chartService,
 		logger:       logger,
-		// 정규식을 한 번만 컴파일하여 재사용합니다.
-		filePathPattern: regexp.MustCompile(`^(.+)/files/([^/]+)$`),
 	}
 }
 
@@ -46,39 +47,35 @@ func (h *HelmHandler) GetHelmChart(w http.ResponseWriter, r *http.Request) {
 	// URL 경로에서 리포지토리 이름을 추출합니다.
 	repoName, ok := r.Context().Value(chartNameKey).(string)
 	if !ok || repoName == "" {
-		h.respondError(w, http.StatusBadRequest, "missing repository name in URL path")
+		h.respondError(w, r, service.CodeInvalidArgument, "missing repository name in URL path")
 		return
 	}
 	tag := r.URL.Query().Get("tag")
 	digest := r.URL.Query().Get("digest")
 
 	if tag != "" && digest != "" {
-		h.respondError(w, http.StatusBadRequest, "tag and digest cannot be specified simultaneously")
+		h.respondError(w, r, service.CodeInvalidArgument, "tag and digest cannot be specified simultaneously")
 		return
 	}
 
 	// 저장소에 있는 이미지 정보 조회 (tag 또는 digest 유무에 따라 서비스에서 다르게 처리)
-	h.logger.Info("request to get helm chart info", "repo", repoName, "tag", tag, "digest", digest)
+	h.log(r).Info("request to get helm chart info", "repo", repoName, "tag", tag, "digest", digest)
 
-	chart, err := h.chartService.DescribeHelmChart(r.Context(), repoName, tag, digest)
+	chart, freshness, err := h.chartService.DescribeHelmChart(r.Context(), repoName, tag, digest)
 	if err != nil {
-		h.logger.Error("failed to describe helm chart", "error", err)
-
-		// 에러 유형에 따라 적절한 HTTP 상태 코드 반환
-		var notFoundErr *types.ImageNotFoundException
-		var repoNotFoundErr *types.RepositoryNotFoundException
-
-		if errors.Is(err, service.ErrRepositoryNotAllowed) {
-			h.respondError(w, http.StatusForbidden, err.Error())
-		} else if errors.As(err, &notFoundErr) || errors.As(err, &repoNotFoundErr) {
-			h.respondError(w, http.StatusNotFound, err.Error())
-		} else {
-			h.respondError(w, http.StatusInternalServerError, "internal server error")
-		}
+		h.respondServiceError(w, r, "failed to describe helm chart", err)
 		return
 	}
+	// 특정 버전을 조회한 경우 해당 매니페스트의 서명을 확인합니다.
+	if (tag != "" || digest != "") && len(chart) > 0 && chart[0].ImageDigest != nil {
+		if _, ok := h.verifySignature(w, r, repoName, *chart[0].ImageDigest); !ok {
+			return
+		}
+	}
 
-	h.respondJSON(w, http.StatusOK, chart)
+	// 조회 결과에는 마지막 pull 시각처럼 digest가 같아도 바뀌는 값이 있으므로 응답 본문으로 ETag를 계산하고 매번 재검증하도록 합니다.
+	setFreshnessHeaders(w, freshness)
+	h.writeCacheableJSON(w, r, chart, false)
 }
 
 // GetChartFile은 차트 아카이브 내의 특정 파일을 조회하는 핸들러입니다.
@@ -86,161 +83,317 @@ func (h *HelmHandler) GetHelmChart(w http.ResponseWriter, r *http.Request) {
 func (h *HelmHandler) GetChartFile(w http.ResponseWriter, r *http.Request) {
 	repoName, ok := r.Context().Value(chartNameKey).(string)
 	if !ok || repoName == "" {
-		h.respondError(w, http.StatusBadRequest, "missing repository name in URL path")
+		h.respondError(w, r, service.CodeInvalidArgument, "missing repository name in URL path")
 		return
 	}
 	fileName, ok := r.Context().Value(fileNameKey).(string)
 	if !ok || fileName == "" {
-		h.respondError(w, http.StatusBadRequest, "missing file name in URL path")
+		h.respondError(w, r, service.CodeInvalidArgument, "missing file name in URL path")
 		return
 	}
 	tag := r.URL.Query().Get("tag")
 	digest := r.URL.Query().Get("digest")
 
 	if tag == "" && digest == "" {
-		h.respondError(w, http.StatusBadRequest, "tag or digest is required")
+		h.respondError(w, r, service.CodeInvalidArgument, "tag or digest is required")
 		return
 	}
 
 	if tag != "" && digest != "" {
-		h.respondError(w, http.StatusBadRequest, "tag and digest cannot be specified simultaneously")
+		h.respondError(w, r, service.CodeInvalidArgument, "tag and digest cannot be specified simultaneously")
 		return
 	}
 
-	h.logger.Info("request to get chart file", "repo", repoName, "tag", tag, "digest", digest, "file", fileName)
+	h.log(r).Info("request to get chart file", "repo", repoName, "tag", tag, "digest", digest, "file", fileName)
 
-	// 파일 확장자에 따라 적절한 Content-Type을 설정합니다.
-	// YAML의 공식 IANA MIME 타입은 없지만, 'application/x-yaml'이 널리 사용되는 관례입니다.
-	var contentType string
-	if strings.HasSuffix(fileName, ".json") {
-		contentType = "application/json"
-	} else if strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml") {
-		contentType = "application/x-yaml; charset=utf-8"
-	} else {
-		contentType = "text/plain; charset=utf-8"
+	// 응답 형식은 차트를 조회하기 전에 결정하여, 지원하지 않는 형식 요청에 불필요한 업스트림 호출을 하지 않습니다.
+	format, nerr := negotiateFormat(r, fileName)
+	if nerr != nil {
+		problem.Write(w, r, nerr.status, nerr.code, nerr.detail)
+		return
 	}
 
-	fileBytes, err := h.chartService.GetChartFile(r.Context(), repoName, tag, digest, fileName)
+	// ?path=가 있으면 YAML/JSON 파일에서 해당 값만 반환합니다. (예: values.yaml?path=.image.repository)
+	var sel *selector.Path
+	if expr := r.URL.Query().Get("path"); expr != "" {
+		if sourceFormat(fileName) == formatRaw {
+			h.respondError(w, r, service.CodeInvalidArgument, "path is only supported for YAML and JSON files")
+			return
+		}
+		var err error
+		if sel, err = selector.Parse(expr); err != nil {
+			h.respondError(w, r, service.CodeInvalidArgument, err.Error())
+			return
+		}
+	}
+
+	// tag를 먼저 매니페스트 다이제스트로 변환하여, 클라이언트가 가진 ETag가 최신이면 차트를 내려받지 않고 304로 응답합니다.
+	manifestDigest, err := h.chartService.ResolveDigest(r.Context(), repoName, tag, digest)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to resolve chart digest", err)
+		return
+	}
+	// 서명 강제 모드에서는 304 응답도 하지 않도록 캐시 검증보다 먼저 확인합니다.
+	if _, ok := h.verifySignature(w, r, repoName, manifestDigest); !ok {
+		return
+	}
+	// 같은 파일이라도 표현 형식마다 내용이 다르므로 형식을 ETag에 포함하고, Accept에 따라 응답이 달라짐을 알립니다.
+	representation := fileName + "#" + string(format)
+	if sel != nil {
+		representation += "#" + sel.String()
+	}
+	etag := chartFileETag(manifestDigest, representation)
+	w.Header().Set("Vary", "Accept")
+	if etagListed(r, etag) {
+		setFileCacheHeaders(w, r, etag, digest != "")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 
+	// 조회 중에 tag가 다른 버전으로 옮겨져도 ETag와 내용이 일치하도록 변환한 다이제스트로 조회합니다.
+	file, err := h.chartService.GetChartFile(r.Context(), repoName, "", manifestDigest, fileName)
 	if err != nil {
-		h.logger.Error("failed to get chart file", "error", err, "file", fileName)
-
-		// 서비스 계층에서 정의한 커스텀 에러를 확인하여 적절한 상태 코드를 반환합니다.
-		if errors.Is(err, service.ErrChartNotFound) {
-			h.respondError(w, http.StatusNotFound, err.Error())
-		} else if errors.Is(err, service.ErrRepositoryNotAllowed) {
-			h.respondError(w, http.StatusForbidden, err.Error())
-		} else {
-			h.respondError(w, http.StatusInternalServerError, "internal server error")
+		h.respondServiceError(w, r, "failed to get chart file", err)
+		return
+	}
+	defer file.Body.Close()
+
+	body, size, contentType, err := fileRepresentation(file, fileName, format, sel)
+	var convErr *conversionError
+	if errors.Is(err, selector.ErrNotFound) {
+		problem.Write(w, r, http.StatusNotFound, "path_not_found", err.Error())
+		return
+	}
+	if errors.As(err, &convErr) {
+		h.log(r).Warn("failed to convert chart file", "error", err, "file", file.Name, "format", format)
+		detail := fmt.Sprintf("file %s cannot be converted to %s", fileName, format)
+		if errors.Is(err, errMultiDocument) {
+			detail = fmt.Sprintf("file %s contains multiple YAML documents and cannot be converted to a single value", fileName)
 		}
+		problem.Write(w, r, http.StatusUnprocessableEntity, "conversion_failed", detail)
+		return
+	}
+	if err != nil {
+		h.respondServiceError(w, r, "failed to read chart file", err)
 		return
 	}
 
+	setFileCacheHeaders(w, r, etag, digest != "")
+	// If-None-Match: *는 파일이 있는 것을 확인한 뒤에만 304로 응답합니다.
+	if etagMatches(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	w.Header().Set("Content-Type", contentType)
-	w.Write(fileBytes)
+	// 차트에 포함된 HTML 등을 브라우저가 다른 타입으로 해석하여 실행하지 않도록 합니다.
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if err := serveChartFile(w, r, body, size, etag); err != nil {
+		// 헤더를 이미 보냈으므로 에러 응답을 작성할 수 없습니다. 클라이언트는 Content-Length로 잘린 응답을 알 수 있습니다.
+		h.log(r).Error("failed to stream chart file", "error", err, "file", file.Name)
+	}
 }
 
-// RouteHelmCharts는 모든 /v1/helm-charts 경로에 대한 요청을 분석하여
-// 적절한 핸들러로 분기하는 통합 라우터 역할을 합니다.
-func (h *HelmHandler) RouteHelmCharts(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/v1/helm-charts")
+// 라우트 템플릿은 메트릭 라벨로 사용되며 RouteHelmCharts가 핸들러를 고르는 기준이기도 합니다.
+const (
+	routeList            = "/v1/helm-charts"
+	routeSearch          = "/v1/helm-charts/search"
+	routeChart           = "/v1/helm-charts/{repo}"
+	routeFile            = "/v1/helm-charts/{repo}/files/{file}"
+	routeImages          = "/v1/helm-charts/{repo}/images"
+	routeLint            = "/v1/helm-charts/{repo}/lint"
+	routeReferrers       = "/v1/helm-charts/{repo}/referrers"
+	routeReferrerContent = "/v1/helm-charts/{repo}/referrers/{digest}"
+)
 
-	// 요청 로깅 (모든 helm-charts 요청을 한 곳에서 추적)
-	h.logger.Debug("routing helm-charts request", "method", r.Method, "path", path)
+// chartRoute는 /v1/helm-charts 아래 경로를 분석한 결과입니다.
+type chartRoute struct {
+	template string
+	repo     string
+	file     string
+	digest   string
+}
 
-	matches := h.filePathPattern.FindStringSubmatch(strings.TrimPrefix(path, "/"))
-	switch {
-	case path == "":
-		// 리포지토리 목록 조회: GET /v1/helm-charts
-		h.ListHelmCharts(w, r)
-	case 
+// parseChartPath는 /v1/helm-charts 뒤의 경로를 라우트 템플릿과 파라미터로 나눕니다.
+// 리포지토리 이름에도 '/'가 들어갈 수 있으므로 두 번째 세그먼트부터 앞에서부터 살펴보고,
+// 예약된 세그먼트(files, images, lint, referrers)가 자기 위치에 처음 나타나는 곳에서 나눕니다.
+// 따라서 files/ 뒤의 파일 경로에 images나 lint 같은 세그먼트가 있어도 파일 조회로 처리합니다.
+func parseChartPath(path string) chartRoute {
+	switch path {
+	case "":
+		return chartRoute{template: routeList}
+	case "/search":
+		// 이름이 "search"인 최상위 리포지토리의 차트 정보는 이 경로로 조회할 수 없습니다.
+		return chartRoute{template: routeSearch}
 	}
 
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	last := len(segments) - 1
+	for i := 1; i <= last; i++ {
+		repo := strings.Join(segments[:i], "/")
+		switch {
+		case segments[i] == "files" && i < last:
+			return chartRoute{template: routeFile, repo: repo, file: strings.Join(segments[i+1:], "/")}
+		case segments[i] == "images" && i == last:
+			return chartRoute{template: routeImages, repo: repo}
+		case segments[i] == "lint" && i == last:
+			return chartRoute{template: routeLint, repo: repo}
+		case segments[i] == "referrers" && i == last:
+			return chartRoute{template: routeReferrers, repo: repo}
+		case segments[i] == "referrers" && i == last-1:
+			return chartRoute{template: routeReferrerContent, repo: repo, digest: segments[last]}
+		}
+	}
+	return chartRoute{template: routeChart, repo: strings.Join(segments, "/")}
+}
 
+// RouteHelmCharts는 모든 /v1/helm-charts 경로에 대한 요청을 분석하여
+// 적절한 핸들러로 분기하는 통합 라우터 역할을 합니다.
+func (h *HelmHandler) RouteHelmCharts(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/v1/helm-charts")
 
 	// 요청 로깅 (모든 helm-charts 요청을 한 곳에서 추적)
-	h.logger.Debug("routing helm-charts request", "method", r.Method, "path", path)
+	h.log(r).Debug("routing helm-charts request", "method", r.Method, "path", path)
+
+	route := parseChartPath(path)
+	middleware.SetRoute(r.Context(), route.template)
 
-	// 경로별 라우팅 처리
-	switch {
-	case path == "":
+	if route.template == routeList {
 		// 리포지토리 목록 조회: GET /v1/helm-charts
 		h.ListHelmCharts(w, r)
-
-	case strings.Contains(path, "/files/"):
-		// 파일 조회 요청: GET /v1/helm-charts/{chart-name}/files/{file-name}
-		h.routeFileRequest(w, r, path)
-
-	default:
-		// 차트 정보 조회 요청: GET /v1/helm-charts/{chart-name}
-		h.routeChartRequest(w, r, path)
+		return
 	}
-}
-
-// routeFileRequest는 파일 조회 요청을 처리합니다.
-func (h *HelmHandler) routeFileRequest(w http.ResponseWriter, r *http.Request, path string) {
-	// 미리 컴파일된 정규식을 사용하여 .../files/... 패턴을 찾습니다.
-	matches := h.filePathPattern.FindStringSubmatch(strings.TrimPrefix(path, "/"))
-
-	if len(matches) != 3 {
-		h.respondError(w, http.StatusBadRequest, "invalid file path format")
+	if route.template == routeSearch {
+		// 차트 검색: GET /v1/helm-charts/search?q=...
+		h.SearchCharts(w, r)
 		return
 	}
 
-	chartName := matches[1]
-	fileName := matches[2]
-
-	// 컨텍스트에 파라미터를 추가하여 핸들러에 전달합니다.
-	ctx := context.WithValue(r.Context(), chartNameKey, chartName)
-	ctx = context.WithValue(ctx, fileNameKey, fileName)
-	h.GetChartFile(w, r.WithContext(ctx))
-}
-
-// routeChartRequest는 차트 정보 조회 요청을 처리합니다.
-func (h *HelmHandler) routeChartRequest(w http.ResponseWriter, r *http.Request, path string) {
-	chartName := strings.TrimPrefix(path, "/")
-
-	if chartName == "" {
-		h.respondError(w, http.StatusBadRequest, "missing chart name in URL path")
+	if route.repo == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "missing chart name in URL path")
 		return
 	}
+	// 컨텍스트에 파라미터를 추가하여 핸들러에 전달합니다.
+	ctx := context.WithValue(r.Context(), chartNameKey, route.repo)
+	r = r.WithContext(ctx)
 
-	ctx := context.WithValue(r.Context(), chartNameKey, chartName)
-	h.GetHelmChart(w, r.WithContext(ctx))
+	switch route.template {
+	case routeFile:
+		// 파일 조회 요청: GET /v1/helm-charts/{chart-name}/files/{file-name}
+		h.GetChartFile(w, r.WithContext(context.WithValue(ctx, fileNameKey, route.file)))
+	case routeImages:
+		// 이미지 목록 조회: GET /v1/helm-charts/{chart-name}/images
+		h.GetChartImages(w, r)
+	case routeLint:
+		// 차트 린트: GET /v1/helm-charts/{chart-name}/lint
+		h.GetChartLint(w, r)
+	case routeReferrers:
+		// 첨부 아티팩트 목록 조회: GET /v1/helm-charts/{chart-name}/referrers
+		h.ListReferrers(w, r)
+	case routeReferrerContent:
+		// 첨부 아티팩트 내용 조회: GET /v1/helm-charts/{chart-name}/referrers/{digest}
+		h.GetReferrerContent(w, r.WithContext(context.WithValue(ctx, referrerDigestKey, route.digest)))
+	default:
+		// 차트 정보 조회 요청: GET /v1/helm-charts/{chart-name}
+		h.GetHelmChart(w, r)
+	}
 }
 
 // ListHelmCharts는 ECR의 모든 Helm 차트 리포지토리를 조회하는 핸들러입니다.
 // 예: GET /v1/helm-charts
 func (h *HelmHandler) ListHelmCharts(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("request to list all helm chart repositories")
+	h.log(r).Info("request to list all helm chart repositories")
 
-	charts, err := h.chartService.ListHelmCharts(r.Context())
+	charts, freshness, err := h.chartService.ListHelmCharts(r.Context())
 	if err != nil {
-		h.logger.Error("failed to list helm charts", "error", err)
-		h.respondError(w, http.StatusInternalServerError, "internal server error")
+		h.respondServiceError(w, r, "failed to list helm charts", err)
 		return
 	}
 
+	setFreshnessHeaders(w, freshness)
 	h.respondJSON(w, http.StatusOK, charts)
 }
 
 // HealthCheck는 서비스의 상태를 확인하는 간단한 핸들러입니다.
 // 200 OK 응답을 반환하여 서비스가 살아있음을 알립니다.
+// 기존 호환성을 위해 유지하며, Kubernetes 프로브에는 /livez와 /readyz를 사용하세요.
 func (h *HelmHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// log는 요청 ID 등이 포함된 요청 범위 로거를 반환합니다.
+func (h *HelmHandler) log(r *http.Request) *slog.Logger {
+	return logging.FromContext(r.Context())
+}
+
 // respondJSON은 JSON 응답을 작성하는 헬퍼 함수입니다.
 func (h *HelmHandler) respondJSON(w http.ResponseWriter, status int, payload interface{}) {
+	writeJSON(w, h.logger, status, payload)
+}
+
+// respondError는 RFC 7807 Problem Details 형식의 에러 응답을 작성하는 헬퍼 함수입니다.
+// HTTP 상태 코드는 에러 코드로부터 결정됩니다.
+func (h *HelmHandler) respondError(w http.ResponseWriter, r *http.Request, code service.ErrorCode, message string) {
+	problem.Write(w, r, statusForCode(code), string(code), message)
+}
+
+// respondServiceError는 서비스 계층 에러를 로그에 기록하고 에러 코드에 맞는 응답을 작성합니다.
+// 원인 에러(AWS 에러 메시지 등)는 로그에만 기록하고, 응답에는 노출해도 안전한 메시지만 포함합니다.
+func (h *HelmHandler) respondServiceError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	code := service.ErrorCodeOf(err)
+	status := statusForCode(code)
+
+	if status >= http.StatusInternalServerError {
+		h.log(r).Error(msg, "error", err, "code", code)
+	} else {
+		h.log(r).Warn(msg, "error", err, "code", code)
+	}
+
+	// 일시적인 업스트림 장애는 클라이언트가 언제 다시 시도할지 알 수 있도록 Retry-After 헤더를 추가합니다.
+	if retryAfter := service.RetryAfterOf(err); retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+
+	problem.Write(w, r, status, string(code), service.PublicMessage(err))
+}
+
+// statusClientClosedRequest는 클라이언트가 응답을 받기 전에 요청을 취소했음을 나타내는 비표준 상태 코드(nginx 499)입니다.
+// 클라이언트는 대부분 응답을 받지 못하지만, 접근 로그와 메트릭에서 서버 에러(5xx)와 구분하기 위해 사용합니다.
+const statusClientClosedRequest = 499
+
+// statusForCode는 서비스 에러 코드를 HTTP 상태 코드로 변환합니다.
+// service.ErrorCode에 코드를 추가하면 이 함수에도 반드시 추가해야 합니다.
+func statusForCode(code service.ErrorCode) int {
+	switch code {
+	case service.CodeInvalidArgument:
+		return http.StatusBadRequest
+	case service.CodeRepoNotAllowed, service.CodeSignatureRequired, service.CodeSignatureInvalid:
+		return http.StatusForbidden
+	case service.CodeRepoNotFound, service.CodeVersionNotFound, service.CodeFileNotFound:
+		return http.StatusNotFound
+	case service.CodeFileTooLarge, service.CodeInvalidArchive:
+		return http.StatusUnprocessableEntity
+	case service.CodeUpstreamThrottled, service.CodeRateLimited:
+		return http.StatusTooManyRequests
+	case service.CodeUpstreamAuth:
+		return http.StatusBadGateway
+	case service.CodeUpstreamUnavailable, service.CodeSearchUnavailable, service.CodeRenderTimeout, service.CodeRenderBusy:
+		return http.StatusServiceUnavailable
+	case service.CodeCanceled:
+		return statusClientClosedRequest
+	case service.CodeInternal:
+		return http.StatusInternalServerError
+	default:
+		// 매핑되지 않은 코드는 버그이므로 500으로 응답하고 로그로 확인할 수 있도록 합니다.
+		slog.Default().Error("unmapped service error code", "code", code)
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON은 payload를 JSON으로 인코딩하여 응답을 작성합니다.
+func writeJSON(w http.ResponseWriter, logger *slog.Logger, status int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(payload); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
+		logger.Error("failed to encode response", "error", err)
 	}
 }
-
-// respondError는 JSON 형식의 에러 응답을 작성하는 헬퍼 함수입니다.
-func (h *HelmHandler) respondError(w http.ResponseWriter, status int, message string) {
-	h.respondJSON(w, status, map[string]string{"error": message})
-}
@@ -0,0 +1,94 @@
+// filepath: helm-ecr-api/internal/handler/helm_handler_test.go
+package handler
+
+import (
+	"context"
+	"fmt"
+	"helm-ecr-api/internal/service"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseChartPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want chartRoute
+	}{
+		{path: "", want: chartRoute{template: routeList}},
+		{path: "/search", want: chartRoute{template: routeSearch}},
+		{path: "/app", want: chartRoute{template: routeChart, repo: "app"}},
+		{path: "/team/app", want: chartRoute{template: routeChart, repo: "team/app"}},
+		{path: "/team/search", want: chartRoute{template: routeChart, repo: "team/search"}},
+
+		{path: "/team/app/files/values.yaml", want: chartRoute{template: routeFile, repo: "team/app", file: "values.yaml"}},
+		{path: "/team/app/files/charts/redis/values.yaml", want: chartRoute{template: routeFile, repo: "team/app", file: "charts/redis/values.yaml"}},
+		// files/ 뒤의 파일 경로는 예약된 세그먼트로 끝나거나 포함해도 파일 조회입니다.
+		{path: "/team/app/files/templates/lint", want: chartRoute{template: routeFile, repo: "team/app", file: "templates/lint"}},
+		{path: "/team/app/files/docs/images", want: chartRoute{template: routeFile, repo: "team/app", file: "docs/images"}},
+		{path: "/team/app/files/docs/referrers", want: chartRoute{template: routeFile, repo: "team/app", file: "docs/referrers"}},
+		{path: "/team/app/files/referrers/sha256:abc", want: chartRoute{template: routeFile, repo: "team/app", file: "referrers/sha256:abc"}},
+		{path: "/team/app/files/files/x", want: chartRoute{template: routeFile, repo: "team/app", file: "files/x"}},
+		{path: "/team/app/files/", want: chartRoute{template: routeFile, repo: "team/app"}},
+
+		{path: "/team/app/images", want: chartRoute{template: routeImages, repo: "team/app"}},
+		{path: "/team/app/lint", want: chartRoute{template: routeLint, repo: "team/app"}},
+		{path: "/team/app/referrers", want: chartRoute{template: routeReferrers, repo: "team/app"}},
+		{path: "/team/app/referrers/sha256:abc", want: chartRoute{template: routeReferrerContent, repo: "team/app", digest: "sha256:abc"}},
+
+		// 자기 위치가 아닌 예약된 세그먼트는 리포지토리 이름의 일부입니다.
+		{path: "/team/images/app", want: chartRoute{template: routeChart, repo: "team/images/app"}},
+		{path: "/team/lint/app/lint", want: chartRoute{template: routeLint, repo: "team/lint/app"}},
+		{path: "/team/referrers/app/referrers", want: chartRoute{template: routeReferrers, repo: "team/referrers/app"}},
+		{path: "/team/app/files", want: chartRoute{template: routeChart, repo: "team/app/files"}},
+		{path: "/files/app", want: chartRoute{template: routeChart, repo: "files/app"}},
+		{path: "/images", want: chartRoute{template: routeChart, repo: "images"}},
+
+		{path: "/", want: chartRoute{template: routeChart}},
+		{path: "//files/values.yaml", want: chartRoute{template: routeFile, file: "values.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := parseChartPath(tt.path); got != tt.want {
+				t.Errorf("parseChartPath(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRespondServiceErrorRender는 렌더링 시간 초과와 동시 렌더링 제한이 재시도할 수 있는 503으로 응답하는지 확인합니다.
+func TestRespondServiceErrorRender(t *testing.T) {
+	h := NewHelmHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, code := range []service.ErrorCode{service.CodeRenderTimeout, service.CodeRenderBusy} {
+		t.Run(string(code), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/v1/helm-charts/app/images?tag=1.0.0", nil)
+			h.respondServiceError(w, r, "failed to list chart images", &service.Error{Code: code, Message: "render failed", RetryAfter: 5 * time.Second})
+
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+			}
+			if got := w.Header().Get("Retry-After"); got != "5" {
+				t.Errorf("Retry-After = %q, want %q", got, "5")
+			}
+		})
+	}
+}
+
+// TestRespondServiceErrorCanceled는 취소된 요청을 서버 에러(5xx)가 아닌 499로 응답하는지 확인합니다.
+func TestRespondServiceErrorCanceled(t *testing.T) {
+	h := NewHelmHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/v1/helm-charts/app?tag=1.0.0", nil)
+	h.respondServiceError(w, r, "failed to describe helm chart", fmt.Errorf("describe images: %w", context.Canceled))
+
+	if w.Code != statusClientClosedRequest {
+		t.Errorf("status = %d, want %d", w.Code, statusClientClosedRequest)
+	}
+}
@@ -0,0 +1,118 @@
+// filepath: helm-ecr-api/internal/handler/range.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"helm-ecr-api/internal/problem"
+	"helm-ecr-api/internal/service"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errRangeNotSatisfiable은 요청한 범위가 파일 크기를 벗어났음을 나타냅니다.
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// byteRange는 응답할 바이트 범위 [start, start+length)입니다.
+type byteRange struct {
+	start, length int64
+}
+
+// parseRange는 "bytes=0-499", "bytes=500-", "bytes=-500" 형태의 단일 범위를 파싱합니다.
+// 여러 범위(multipart/byteranges)는 지원하지 않으므로 ok=false를 반환하여 전체 파일로 응답하게 합니다.
+// 문법이 잘못된 Range 헤더도 RFC 9110 14.2에 따라 무시합니다.
+func parseRange(header string, size int64) (_ byteRange, ok bool, err error) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return byteRange{}, false, nil
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return byteRange{}, false, nil
+	}
+
+	// 접미사 범위: 마지막 N 바이트
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return byteRange{}, false, nil
+		}
+		if n == 0 || size == 0 {
+			return byteRange{}, false, errRangeNotSatisfiable
+		}
+		n = min(n, size)
+		return byteRange{start: size - n, length: n}, true, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return byteRange{}, false, nil
+	}
+	if start >= size {
+		return byteRange{}, false, errRangeNotSatisfiable
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return byteRange{}, false, nil
+		}
+		end = min(end, size-1)
+	}
+	return byteRange{start: start, length: end - start + 1}, true, nil
+}
+
+// serveChartFile은 body를 응답으로 스트리밍합니다. Range 헤더가 있으면 해당 범위만 206으로 응답합니다.
+// body는 탐색(seek)할 수 없는 스트림이므로 범위 앞부분은 읽어서 버립니다.
+// If-Range가 현재 ETag와 다르면 파일이 바뀐 것이므로 Range를 무시하고 전체 파일로 응답합니다.
+func serveChartFile(w http.ResponseWriter, r *http.Request, body io.Reader, size int64, etag string) error {
+	w.Header().Set("Accept-Ranges", "bytes")
+
+	status := http.StatusOK
+	rng := byteRange{start: 0, length: size}
+	if header := r.Header.Get("Range"); header != "" && ifRangeMatches(r, etag) {
+		parsed, ok, err := parseRange(header, size)
+		switch {
+		case errors.Is(err, errRangeNotSatisfiable):
+			// 에러 응답이 파일 내용의 ETag와 캐시 헤더로 캐시되지 않도록 지웁니다.
+			w.Header().Del("ETag")
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			problem.Write(w, r, http.StatusRequestedRangeNotSatisfiable, "range_not_satisfiable",
+				fmt.Sprintf("requested range is outside the file size of %d bytes", size))
+			return nil
+		case ok:
+			status = http.StatusPartialContent
+			rng = parsed
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.start, rng.start+rng.length-1, size))
+		}
+	}
+
+	if rng.start > 0 {
+		if _, err := io.CopyN(io.Discard, body, rng.start); err != nil {
+			// 아직 응답을 쓰지 않았으므로 범위와 캐시 헤더를 지우고 에러로 응답합니다.
+			w.Header().Del("Content-Range")
+			w.Header().Del("ETag")
+			w.Header().Set("Cache-Control", "no-store")
+			problem.Write(w, r, http.StatusInternalServerError, string(service.CodeInternal), "failed to read the requested range")
+			return fmt.Errorf("failed to skip to range start: %w", err)
+		}
+	}
+
+	w.Header().Set("Content-Length", strconv.FormatInt(rng.length, 10))
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	_, err := io.CopyN(w, body, rng.length)
+	return err
+}
+
+// ifRangeMatches는 If-Range 헤더가 없거나 현재 ETag와 일치하는지 확인합니다.
+// If-Range는 강한 비교를 사용하므로 약한 ETag("W/...")나 날짜 형식은 일치하지 않는 것으로 처리합니다.
+func ifRangeMatches(r *http.Request, etag string) bool {
+	ifRange := r.Header.Get("If-Range")
+	return ifRange == "" || ifRange == etag
+}
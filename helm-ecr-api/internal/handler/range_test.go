@@ -0,0 +1,221 @@
+// filepath: helm-ecr-api/internal/handler/range_test.go
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	const size = 1000
+
+	tests := []struct {
+		header  string
+		size    int64
+		want    byteRange
+		wantOK  bool
+		wantErr error
+	}{
+		{header: "bytes=0-499", size: size, want: byteRange{0, 500}, wantOK: true},
+		{header: "bytes=500-", size: size, want: byteRange{500, 500}, wantOK: true},
+		{header: "bytes=-200", size: size, want: byteRange{800, 200}, wantOK: true},
+		{header: "bytes=900-5000", size: size, want: byteRange{900, 100}, wantOK: true},
+		{header: "bytes=-5000", size: size, want: byteRange{0, 1000}, wantOK: true},
+		{header: "bytes= 10-19", size: size, want: byteRange{10, 10}, wantOK: true},
+		{header: "bytes=999-999", size: size, want: byteRange{999, 1}, wantOK: true},
+
+		// 범위가 파일을 벗어나면 416으로 응답합니다.
+		{header: "bytes=1000-", size: size, wantErr: errRangeNotSatisfiable},
+		{header: "bytes=-0", size: size, wantErr: errRangeNotSatisfiable},
+		{header: "bytes=-10", size: 0, wantErr: errRangeNotSatisfiable},
+		{header: "bytes=0-", size: 0, wantErr: errRangeNotSatisfiable},
+
+		// 지원하지 않거나 문법이 잘못된 Range는 무시하고 전체 파일로 응답합니다.
+		{header: "bytes=0-1,5-6", size: size},
+		{header: "items=0-1", size: size},
+		{header: "bytes=5", size: size},
+		{header: "bytes=10-5", size: size},
+		{header: "bytes=a-b", size: size},
+		{header: "bytes=-1-2", size: size},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok, err := parseRange(tt.header, tt.size)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("range = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// failingReader는 n 바이트를 읽은 뒤 에러를 반환합니다.
+type failingReader struct {
+	n int
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.n == 0 {
+		return 0, errors.New("connection reset")
+	}
+	n := min(len(p), f.n)
+	for i := range p[:n] {
+		p[i] = 'x'
+	}
+	f.n -= n
+	return n, nil
+}
+
+func TestServeChartFile(t *testing.T) {
+	const content = "0123456789"
+	const etag = `"abc"`
+	const immutableCacheControl = "public, max-age=31536000, immutable"
+
+	tests := []struct {
+		name        string
+		method      string
+		headers     map[string]string
+		body        io.Reader
+		wantStatus  int
+		wantBody    string
+		wantRange   string
+		wantLength  string
+		wantErr     bool
+		wantNoETag  bool
+		wantProblem string
+	}{
+		{
+			name:       "full file",
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+			wantLength: "10",
+		},
+		{
+			name:       "range",
+			headers:    map[string]string{"Range": "bytes=2-4"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "234",
+			wantRange:  "bytes 2-4/10",
+			wantLength: "3",
+		},
+		{
+			name:       "suffix range",
+			headers:    map[string]string{"Range": "bytes=-3"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "789",
+			wantRange:  "bytes 7-9/10",
+			wantLength: "3",
+		},
+		{
+			name:       "head request",
+			method:     http.MethodHead,
+			headers:    map[string]string{"Range": "bytes=5-"},
+			wantStatus: http.StatusPartialContent,
+			wantRange:  "bytes 5-9/10",
+			wantLength: "5",
+		},
+		{
+			name:       "matching If-Range",
+			headers:    map[string]string{"Range": "bytes=0-0", "If-Range": etag},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "0",
+			wantRange:  "bytes 0-0/10",
+			wantLength: "1",
+		},
+		{
+			name:       "stale If-Range serves the full file",
+			headers:    map[string]string{"Range": "bytes=0-0", "If-Range": `"old"`},
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+			wantLength: "10",
+		},
+		{
+			name:       "weak If-Range serves the full file",
+			headers:    map[string]string{"Range": "bytes=0-0", "If-Range": "W/" + etag},
+			wantStatus: http.StatusOK,
+			wantBody:   content,
+			wantLength: "10",
+		},
+		{
+			name:        "unsatisfiable range",
+			headers:     map[string]string{"Range": "bytes=10-"},
+			wantStatus:  http.StatusRequestedRangeNotSatisfiable,
+			wantRange:   "bytes */10",
+			wantNoETag:  true,
+			wantProblem: "range_not_satisfiable",
+		},
+		{
+			name:        "skipping to the range start fails",
+			headers:     map[string]string{"Range": "bytes=8-"},
+			body:        &failingReader{n: 4},
+			wantStatus:  http.StatusInternalServerError,
+			wantErr:     true,
+			wantNoETag:  true,
+			wantProblem: "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/v1/helm-charts/app/files/values.yaml?tag=1.0.0", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			body := tt.body
+			if body == nil {
+				body = strings.NewReader(content)
+			}
+			w := httptest.NewRecorder()
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Cache-Control", immutableCacheControl)
+
+			err := serveChartFile(w, r, body, int64(len(content)), etag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Range"); got != tt.wantRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantRange)
+			}
+			if tt.wantProblem != "" {
+				if !strings.Contains(w.Body.String(), `"code":"`+tt.wantProblem+`"`) {
+					t.Errorf("body = %s, want problem code %s", w.Body.String(), tt.wantProblem)
+				}
+			} else {
+				if got := w.Body.String(); got != tt.wantBody {
+					t.Errorf("body = %q, want %q", got, tt.wantBody)
+				}
+				if got := w.Header().Get("Content-Length"); got != tt.wantLength {
+					t.Errorf("Content-Length = %q, want %q", got, tt.wantLength)
+				}
+			}
+			// 에러 응답은 파일 내용의 ETag와 캐시 헤더 없이 캐시하지 않도록 응답해야 합니다.
+			if got := w.Header().Get("ETag"); (got == "") != tt.wantNoETag {
+				t.Errorf("ETag = %q", got)
+			}
+			wantCacheControl := immutableCacheControl
+			if tt.wantNoETag {
+				wantCacheControl = "no-store"
+			}
+			if got := w.Header().Get("Cache-Control"); got != wantCacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, wantCacheControl)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// filepath: helm-ecr-api/internal/handler/referrers.go
+package handler
+
+import (
+	"helm-ecr-api/internal/service"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// referrerDigestKey는 첨부 아티팩트 다이제스트를 전달하는 컨텍스트 키입니다.
+const referrerDigestKey contextKey = "referrer-digest"
+
+// ListReferrers는 차트 버전에 첨부된 아티팩트(SBOM, 서명, 증명 등) 목록을 조회하는 핸들러입니다.
+// artifactType 쿼리 파라미터로 특정 유형만 조회할 수 있습니다.
+// 예: GET /v1/helm-charts/my-repo/my-app/referrers?digest=sha256:...&artifactType=application/spdx%2Bjson
+func (h *HelmHandler) ListReferrers(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := r.Context().Value(chartNameKey).(string)
+	if !ok || repoName == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "missing repository name in URL path")
+		return
+	}
+	tag := r.URL.Query().Get("tag")
+	digest := r.URL.Query().Get("digest")
+	artifactType := r.URL.Query().Get("artifactType")
+
+	if tag == "" && digest == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "tag or digest is required")
+		return
+	}
+
+	if tag != "" && digest != "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "tag and digest cannot be specified simultaneously")
+		return
+	}
+
+	h.log(r).Info("request to list chart referrers", "repo", repoName, "tag", tag, "digest", digest, "artifact_type", artifactType)
+
+	referrers, err := h.chartService.ListReferrers(r.Context(), repoName, tag, digest, artifactType)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to list chart referrers", err)
+		return
+	}
+
+	// 아티팩트는 언제든 새로 첨부될 수 있으므로 digest로 요청해도 매번 재검증하도록 합니다.
+	h.writeCacheableJSON(w, r, referrers, false)
+}
+
+// GetReferrerContent는 첨부된 아티팩트의 문서(레이어)를 내려받는 핸들러입니다.
+// 아티팩트에 레이어가 여러 개이면 layer 쿼리 파라미터로 순서(0부터)를 지정합니다.
+// 예: GET /v1/helm-charts/my-repo/my-app/referrers/sha256:...
+func (h *HelmHandler) GetReferrerContent(w http.ResponseWriter, r *http.Request) {
+	repoName, ok := r.Context().Value(chartNameKey).(string)
+	if !ok || repoName == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "missing repository name in URL path")
+		return
+	}
+	referrerDigest, ok := r.Context().Value(referrerDigestKey).(string)
+	if !ok || referrerDigest == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "missing referrer digest in URL path")
+		return
+	}
+	layer := 0
+	if v := r.URL.Query().Get("layer"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			h.respondError(w, r, service.CodeInvalidArgument, "invalid layer parameter: "+strconv.Quote(v))
+			return
+		}
+		layer = n
+	}
+
+	h.log(r).Info("request to get chart referrer content", "repo", repoName, "referrer", referrerDigest, "layer", layer)
+
+	// 아티팩트 매니페스트는 다이제스트로 고정되어 내용이 바뀌지 않으므로 내려받기 전에 ETag를 결정할 수 있습니다.
+	etag := chartFileETag(referrerDigest, "layer#"+strconv.Itoa(layer))
+	if etagListed(r, etag) {
+		setFileCacheHeaders(w, r, etag, true)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	content, err := h.chartService.GetReferrerContent(r.Context(), repoName, referrerDigest, layer)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to get chart referrer content", err)
+		return
+	}
+	defer content.Body.Close()
+
+	setFileCacheHeaders(w, r, etag, true)
+	if etagMatches(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// 레이어의 미디어 타입은 아티팩트를 푸시한 사람이 정하므로, 브라우저가 API 출처에서 HTML이나 SVG를 실행하지 않도록
+	// 문서 형식만 그대로 전달하고 항상 내려받기(attachment)와 샌드박스로 제공합니다.
+	w.Header().Set("Content-Type", referrerContentType(content.MediaType))
+	w.Header().Set("Content-Disposition", "attachment")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Referrer-Subject", content.Subject)
+
+	if err := serveChartFile(w, r, content.Body, content.Size, etag); err != nil {
+		h.log(r).Error("failed to stream chart referrer content", "error", err, "referrer", referrerDigest)
+	}
+}
+
+// referrerContentType은 아티팩트 레이어의 미디어 타입 중 문서 형식(JSON, in-toto, SPDX, CycloneDX 등)만 그대로 반환하고,
+// 나머지(HTML, SVG, 형식이 잘못된 값 등)는 application/octet-stream으로 바꿉니다.
+func referrerContentType(mediaType string) string {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	switch {
+	case parsed == "application/json", parsed == "text/plain", parsed == "text/spdx":
+	case strings.HasPrefix(parsed, "application/") && strings.HasSuffix(parsed, "+json"):
+		// application/vnd.in-toto+json, application/spdx+json, application/vnd.cyclonedx+json 등
+	default:
+		return "application/octet-stream"
+	}
+	return mediaType
+}
@@ -0,0 +1,44 @@
+// filepath: helm-ecr-api/internal/handler/search.go
+package handler
+
+import (
+	"helm-ecr-api/internal/service"
+	"net/http"
+	"strconv"
+)
+
+// SearchCharts는 허용된 모든 리포지토리의 차트 이름, 설명, 키워드, 관리자, values 키를 검색하는 핸들러입니다.
+// 기본적으로 리포지토리별로 검색어와 일치하는 최신 버전 하나만 반환하며, allVersions=true이면 일치하는 모든 버전을 반환합니다.
+// 예: GET /v1/helm-charts/search?q=podDisruptionBudget&limit=50
+func (h *HelmHandler) SearchCharts(w http.ResponseWriter, r *http.Request) {
+	query := service.SearchQuery{Text: r.URL.Query().Get("q")}
+	if query.Text == "" {
+		h.respondError(w, r, service.CodeInvalidArgument, "q is required")
+		return
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.respondError(w, r, service.CodeInvalidArgument, "limit must be a positive integer")
+			return
+		}
+		query.Limit = n
+	}
+	allVersions, err := parseBoolQuery(r, "allVersions")
+	if err != nil {
+		h.respondError(w, r, service.CodeInvalidArgument, err.Error())
+		return
+	}
+	query.AllVersions = allVersions
+
+	h.log(r).Info("request to search charts", "query", query.Text, "limit", query.Limit, "all_versions", query.AllVersions)
+
+	results, err := h.chartService.SearchCharts(r.Context(), query)
+	if err != nil {
+		h.respondServiceError(w, r, "failed to search charts", err)
+		return
+	}
+
+	// 색인이 갱신되면 결과가 바뀌므로 매번 재검증하도록 합니다.
+	h.writeCacheableJSON(w, r, results, false)
+}
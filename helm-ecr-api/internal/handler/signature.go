@@ -0,0 +1,27 @@
+// filepath: helm-ecr-api/internal/handler/signature.go
+package handler
+
+import (
+	"helm-ecr-api/internal/service"
+	"net/http"
+	"strconv"
+)
+
+// verifySignature는 차트 매니페스트의 서명을 확인하여 결과를 X-Chart-Signed, X-Chart-Verified, X-Chart-Signer 헤더로 알립니다.
+// 서명 검증을 사용하지 않으면 헤더를 설정하지 않습니다.
+// 서명 강제 모드에서 검증되지 않은 차트이면 에러 응답을 작성하고 false를 반환합니다.
+func (h *HelmHandler) verifySignature(w http.ResponseWriter, r *http.Request, repoName, manifestDigest string) (*service.SignatureStatus, bool) {
+	status, err := h.chartService.VerifyChartSignature(r.Context(), repoName, manifestDigest)
+	if err != nil {
+		h.respondServiceError(w, r, "chart signature verification failed", err)
+		return nil, false
+	}
+	if status != nil {
+		w.Header().Set("X-Chart-Signed", strconv.FormatBool(status.Signed))
+		w.Header().Set("X-Chart-Verified", strconv.FormatBool(status.Verified))
+		if status.Signer != "" {
+			w.Header().Set("X-Chart-Signer", status.Signer)
+		}
+	}
+	return status, true
+}
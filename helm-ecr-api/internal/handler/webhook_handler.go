@@ -0,0 +1,66 @@
+// filepath: helm-ecr-api/internal/handler/webhook_handler.go
+package handler
+
+import (
+	"helm-ecr-api/internal/problem"
+	"helm-ecr-api/internal/service"
+	"helm-ecr-api/internal/webhook"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// defaultDeliveryLimit는 limit 파라미터가 없을 때 반환할 전송 기록 수입니다.
+const defaultDeliveryLimit = 50
+
+// DeliveryLog는 최근 웹훅 전송 기록을 최신 순으로 제공합니다.
+type DeliveryLog interface {
+	Deliveries() []webhook.Delivery
+}
+
+// WebhookHandler는 웹훅 전송 기록 조회 요청을 처리합니다.
+type WebhookHandler struct {
+	deliveries DeliveryLog
+	logger     *slog.Logger
+}
+
+// NewWebhookHandler는 WebhookHandler의 새 인스턴스를 생성합니다.
+func NewWebhookHandler(deliveries DeliveryLog, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		deliveries: deliveries,
+		logger:     logger,
+	}
+}
+
+// ListDeliveries는 최근 웹훅 전송 기록을 최신 순으로 반환하는 핸들러입니다.
+// status 파라미터로 상태(pending, delivered, failed, dropped)를 거를 수 있습니다.
+// 예: GET /v1/webhooks/deliveries?status=failed&limit=20
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", webhook.StatusPending, webhook.StatusDelivered, webhook.StatusFailed, webhook.StatusDropped:
+	default:
+		problem.Write(w, r, http.StatusBadRequest, string(service.CodeInvalidArgument), "status must be one of pending, delivered, failed, dropped")
+		return
+	}
+	limit := defaultDeliveryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			problem.Write(w, r, http.StatusBadRequest, string(service.CodeInvalidArgument), "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	deliveries := []webhook.Delivery{}
+	for _, d := range h.deliveries.Deliveries() {
+		if len(deliveries) == limit {
+			break
+		}
+		if status == "" || d.Status == status {
+			deliveries = append(deliveries, d)
+		}
+	}
+	writeJSON(w, h.logger, http.StatusOK, map[string]any{"deliveries": deliveries})
+}
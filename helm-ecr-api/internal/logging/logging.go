@@ -0,0 +1,26 @@
+// filepath: helm-ecr-api/internal/logging/logging.go
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextKey는 컨텍스트 값의 키로 사용되어 충돌을 방지합니다.
+type contextKey string
+
+const loggerKey contextKey = "logger"
+
+// NewContext는 요청 범위 로거(request_id 등이 포함된 로거)를 컨텍스트에 저장합니다.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext는 컨텍스트에 저장된 요청 범위 로거를 반환합니다.
+// 저장된 로거가 없으면(예: 백그라운드 작업) slog.Default()를 반환합니다.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
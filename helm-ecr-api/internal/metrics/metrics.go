@@ -0,0 +1,156 @@
+// filepath: helm-ecr-api/internal/metrics/metrics.go
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace는 모든 메트릭 이름의 접두사입니다. (예: helm_ecr_api_http_requests_total)
+const namespace = "helm_ecr_api"
+
+// registry는 이 애플리케이션의 메트릭만 등록하는 전용 레지스트리입니다.
+// 전역 DefaultRegisterer 대신 사용하여 라이브러리가 등록한 메트릭과 섞이지 않도록 합니다.
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	upstreamCallsTotal = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_calls_total",
+		Help:      "Total number of ECR, STS and registry calls by operation and result.",
+	}, []string{"operation", "result"})
+
+	upstreamCallDuration = promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_call_duration_seconds",
+		Help:      "Latency of ECR, STS and registry calls by operation.",
+		// 레이어 다운로드는 수 초가 걸릴 수 있으므로 기본 버킷보다 넓은 범위를 사용합니다.
+		Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"operation"})
+
+	upstreamRetriesTotal = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_retries_total",
+		Help:      "Total number of retried ECR, STS and registry calls by operation.",
+	}, []string{"operation"})
+
+	rateLimitedTotal = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Total number of requests rejected with 429 by limit (client, downloads).",
+	}, []string{"limit"})
+
+	layerDownloadBytes = promauto.With(registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "layer_download_bytes_total",
+		Help:      "Total number of chart layer bytes downloaded from the registry.",
+	})
+
+	catalogVersions = promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "catalog_versions",
+		Help:      "Number of chart versions with metadata in the catalog.",
+	})
+
+	catalogLastSync = promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "catalog_last_sync_timestamp_seconds",
+		Help:      "Unix time of the last completed catalog sync.",
+	})
+
+	imageEventsTotal = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_events_total",
+		Help:      "Total number of ECR image events received, by processing result.",
+	}, []string{"result"})
+	webhookDeliveriesTotal = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Total number of webhook deliveries, by endpoint and final result.",
+	}, []string{"endpoint", "result"})
+)
+
+func init() {
+	// Go 런타임(GC, 고루틴 등)과 프로세스(CPU, 메모리, FD) 메트릭도 함께 노출합니다.
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler는 /metrics 엔드포인트에서 사용할 Prometheus HTTP 핸들러를 반환합니다.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest는 처리가 끝난 HTTP 요청의 횟수와 지연 시간을 기록합니다.
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	httpRequestsTotal.WithLabelValues(route, method, statusLabel).Inc()
+	httpRequestDuration.WithLabelValues(route, method, statusLabel).Observe(duration.Seconds())
+}
+
+// ObserveUpstream은 ECR/STS/레지스트리 호출의 결과와 지연 시간을 기록합니다.
+// 호출 직전에 start를 기록하고 호출 직후에 에러와 함께 전달합니다.
+//
+//	start := time.Now()
+//	out, err := client.DescribeImages(ctx, input)
+//	metrics.ObserveUpstream("ecr.DescribeImages", start, err)
+func ObserveUpstream(operation string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	upstreamCallsTotal.WithLabelValues(operation, result).Inc()
+	upstreamCallDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// IncUpstreamRetry는 일시적인 에러로 업스트림 호출을 재시도한 횟수를 기록합니다.
+func IncUpstreamRetry(operation string) {
+	upstreamRetriesTotal.WithLabelValues(operation).Inc()
+}
+
+// IncRateLimited는 요청 수 제한(client) 또는 동시 다운로드 제한(downloads)으로 거부된 요청 수를 기록합니다.
+func IncRateLimited(limit string) {
+	rateLimitedTotal.WithLabelValues(limit).Inc()
+}
+
+// AddLayerDownloadBytes는 레지스트리에서 내려받은 레이어 바이트 수를 누적합니다.
+func AddLayerDownloadBytes(n int) {
+	layerDownloadBytes.Add(float64(n))
+}
+
+// SetCatalogSync는 카탈로그 동기화를 마쳤을 때(또는 저장소에서 읽었을 때) 차트 버전 수와 동기화 시각을 기록합니다.
+func SetCatalogSync(versions int, syncedAt time.Time) {
+	catalogVersions.Set(float64(versions))
+	catalogLastSync.Set(float64(syncedAt.UnixNano()) / 1e9)
+}
+
+// IncImageEvent는 받은 ECR 이미지 이벤트 수를 처리 결과(applied, ignored, invalid, failed)별로 기록합니다.
+func IncImageEvent(result string) {
+	imageEventsTotal.WithLabelValues(result).Inc()
+}
+
+// IncWebhookDelivery는 끝난 웹훅 전송 수를 엔드포인트와 최종 결과(delivered, failed, dropped)별로 기록합니다.
+func IncWebhookDelivery(endpoint, result string) {
+	webhookDeliveriesTotal.WithLabelValues(endpoint, result).Inc()
+}
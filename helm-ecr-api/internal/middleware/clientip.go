@@ -0,0 +1,90 @@
+// filepath: helm-ecr-api/internal/middleware/clientip.go
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+const clientIPKey contextKey = "client-ip"
+
+// ClientIP는 요청을 보낸 클라이언트의 IP를 결정하여 컨텍스트에 저장하는 미들웨어입니다.
+// 접근 로그와 요청 수 제한이 이 값을 사용하므로 두 미들웨어보다 바깥쪽에 배치해야 합니다.
+//
+// 연결한 주소가 trusted(인그레스, 로드 밸런서 등 신뢰하는 프록시 대역)에 속하면 X-Forwarded-For를 오른쪽부터 읽어
+// 신뢰하지 않는 첫 주소를 클라이언트 IP로 사용합니다. 클라이언트가 보낸 X-Forwarded-For 값은 왼쪽에 남으므로 위조할 수 없습니다.
+// trusted가 비어 있으면 X-Forwarded-For를 사용하지 않고 연결한 주소를 사용합니다.
+func ClientIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey, ip)))
+		})
+	}
+}
+
+// clientIP는 ClientIP 미들웨어가 결정한 클라이언트 IP를 반환합니다.
+// 미들웨어를 거치지 않은 요청이면 연결한 주소를 사용합니다.
+func clientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// resolveClientIP는 trusted 프록시를 건너뛰고 X-Forwarded-For에서 클라이언트 IP를 찾습니다.
+// X-Forwarded-For의 값이 잘못되었으면 그 값을 추가한 마지막 신뢰하는 프록시의 주소를 사용합니다.
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote := remoteIP(r)
+	if !isTrusted(remote, trusted) {
+		return remote
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	ip := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(hop)
+		if err != nil {
+			break
+		}
+		ip = addr.Unmap().String()
+		if !isTrusted(ip, trusted) {
+			break
+		}
+	}
+	return ip
+}
+
+// isTrusted는 ip가 trusted 대역에 속하는지 확인합니다.
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	if len(trusted) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP는 연결한 주소(RemoteAddr)의 IP를 반환합니다.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
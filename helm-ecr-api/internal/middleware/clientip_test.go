@@ -0,0 +1,78 @@
+// filepath: helm-ecr-api/internal/middleware/clientip_test.go
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("fd00::/8"),
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        []string // 헤더 줄마다 하나씩
+		trusted    []netip.Prefix
+		want       string
+	}{
+		{name: "direct connection", remoteAddr: "203.0.113.7:5000", trusted: trusted, want: "203.0.113.7"},
+		{name: "no trusted proxies ignores the header", remoteAddr: "10.0.0.1:5000", xff: []string{"203.0.113.7"}, want: "10.0.0.1"},
+		{name: "untrusted peer cannot spoof the header", remoteAddr: "198.51.100.1:5000", xff: []string{"203.0.113.7"}, trusted: trusted, want: "198.51.100.1"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:5000", xff: []string{"203.0.113.7"}, trusted: trusted, want: "203.0.113.7"},
+		{name: "trusted proxy without the header", remoteAddr: "10.0.0.1:5000", trusted: trusted, want: "10.0.0.1"},
+		{name: "chain of trusted proxies", remoteAddr: "10.0.0.1:5000", xff: []string{"203.0.113.7, 10.0.0.3, 10.0.0.2"}, trusted: trusted, want: "203.0.113.7"},
+		// 신뢰하지 않는 첫 주소에서 멈추므로 클라이언트가 앞에 넣은 값은 사용하지 않습니다.
+		{name: "spoofed entries left of the client", remoteAddr: "10.0.0.1:5000", xff: []string{"1.1.1.1, 203.0.113.7"}, trusted: trusted, want: "203.0.113.7"},
+		{name: "spoofed trusted entry left of the client", remoteAddr: "10.0.0.1:5000", xff: []string{"10.9.9.9, 203.0.113.7, 10.0.0.2"}, trusted: trusted, want: "203.0.113.7"},
+		{name: "multiple header lines", remoteAddr: "10.0.0.1:5000", xff: []string{"1.1.1.1, 203.0.113.7", "10.0.0.2"}, trusted: trusted, want: "203.0.113.7"},
+		{name: "only trusted hops", remoteAddr: "10.0.0.1:5000", xff: []string{"10.0.0.3, 10.0.0.2"}, trusted: trusted, want: "10.0.0.3"},
+		{name: "empty entries are skipped", remoteAddr: "10.0.0.1:5000", xff: []string{"203.0.113.7, , 10.0.0.2,"}, trusted: trusted, want: "203.0.113.7"},
+		// 잘못된 값을 만나면 그 값을 추가한 마지막 신뢰하는 프록시의 주소를 사용합니다.
+		{name: "invalid entry", remoteAddr: "10.0.0.1:5000", xff: []string{"203.0.113.7, unknown"}, trusted: trusted, want: "10.0.0.1"},
+		{name: "invalid entry behind a trusted hop", remoteAddr: "10.0.0.1:5000", xff: []string{"garbage, 10.0.0.2"}, trusted: trusted, want: "10.0.0.2"},
+		{name: "entry with a port is invalid", remoteAddr: "10.0.0.1:5000", xff: []string{"203.0.113.7:1234"}, trusted: trusted, want: "10.0.0.1"},
+		{name: "IPv6", remoteAddr: "[fd00::1]:5000", xff: []string{"2001:db8::7, fd00::2"}, trusted: trusted, want: "2001:db8::7"},
+		{name: "IPv4-mapped IPv6", remoteAddr: "[::ffff:10.0.0.1]:5000", xff: []string{"::ffff:203.0.113.7"}, trusted: trusted, want: "203.0.113.7"},
+		{name: "remote address without a port", remoteAddr: "203.0.113.7", trusted: trusted, want: "203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := resolveClientIP(r, tt.trusted); got != tt.want {
+				t.Errorf("resolveClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestClientIPMiddleware는 미들웨어가 결정한 IP를 뒤의 핸들러가 clientIP로 읽을 수 있는지 확인합니다.
+func TestClientIPMiddleware(t *testing.T) {
+	var got string
+	h := ClientIP([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = clientIP(r)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:5000"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if got != "203.0.113.7" {
+		t.Errorf("clientIP() = %q, want %q", got, "203.0.113.7")
+	}
+
+	// 미들웨어를 거치지 않은 요청은 연결한 주소를 사용합니다.
+	if ip := clientIP(r); ip != "10.0.0.1" {
+		t.Errorf("clientIP() without the middleware = %q, want %q", ip, "10.0.0.1")
+	}
+}
@@ -0,0 +1,90 @@
+// filepath: helm-ecr-api/internal/middleware/logging.go
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"helm-ecr-api/internal/logging"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader는 요청 ID를 주고받는 HTTP 헤더입니다.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength는 클라이언트가 전달한 요청 ID를 그대로 사용할 최대 길이입니다.
+const maxRequestIDLength = 128
+
+// RequestID는 요청마다 요청 ID를 부여하고, 요청 ID가 포함된 로거를 컨텍스트에 저장하는 미들웨어입니다.
+// 클라이언트(또는 앞단의 프록시)가 X-Request-ID를 보냈으면 그대로 사용하고, 없으면 새로 생성합니다.
+// 요청 ID는 응답 헤더에도 포함되어 클라이언트가 문제를 보고할 때 사용할 수 있습니다.
+func RequestID(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			attrs := []any{"request_id", id}
+			// 트레이싱이 활성화되어 있으면 로그와 트레이스를 연결할 수 있도록 trace_id도 추가합니다.
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				attrs = append(attrs, "trace_id", sc.TraceID().String())
+			}
+
+			ctx := logging.NewContext(r.Context(), logger.With(attrs...))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AccessLog는 요청마다 한 줄의 구조화된 접근 로그를 남기는 미들웨어입니다.
+// RequestID 미들웨어 안쪽에 적용해야 접근 로그에 request_id가 포함됩니다.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r, info := withRequestInfo(r)
+		rec := newResponseRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		logging.FromContext(r.Context()).Info("access",
+			"method", r.Method,
+			"route", routeName(r, info),
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"caller", clientIP(r),
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
+
+// validRequestID는 클라이언트가 전달한 요청 ID를 로그에 그대로 기록해도 안전한지 확인합니다.
+// 로그 위조를 방지하기 위해 영문자, 숫자와 일부 기호만 허용합니다.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID는 128비트 난수로 새 요청 ID를 생성합니다.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b) // crypto/rand.Read는 에러를 반환하지 않습니다. (Go 1.24+)
+	return hex.EncodeToString(b)
+}
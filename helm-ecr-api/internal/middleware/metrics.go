@@ -0,0 +1,22 @@
+// filepath: helm-ecr-api/internal/middleware/metrics.go
+package middleware
+
+import (
+	"helm-ecr-api/internal/metrics"
+	"net/http"
+	"time"
+)
+
+// Metrics는 모든 요청의 횟수와 지연 시간을 라우트, 메서드, 상태 코드별로 기록하는 미들웨어입니다.
+// ServeMux 바깥에 적용해야 인증 실패(401)나 매칭되지 않은 요청(404)도 함께 기록됩니다.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r, info := withRequestInfo(r)
+		rec := newResponseRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		metrics.ObserveHTTPRequest(routeName(r, info), r.Method, rec.status, time.Since(start))
+	})
+}
@@ -0,0 +1,117 @@
+// filepath: helm-ecr-api/internal/middleware/ratelimit.go
+package middleware
+
+import (
+	"helm-ecr-api/internal/logging"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/problem"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limiterIdleTTL 동안 요청이 없는 클라이언트의 limiter는 메모리에서 제거합니다.
+	limiterIdleTTL = 10 * time.Minute
+	// limiterSweepInterval은 유휴 limiter를 정리하는 최소 간격입니다.
+	limiterSweepInterval = time.Minute
+)
+
+// RateLimiter는 클라이언트 IP별로 요청 수를 제한하는 미들웨어입니다.
+// 한 클라이언트가 반복 요청으로 ECR API 요청 제한을 소진하여 다른 클라이언트까지 영향을 받는 것을 방지합니다.
+// 토큰 버킷 방식이므로 burst만큼의 순간적인 요청은 허용하고, 이후에는 초당 limit개로 제한합니다.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+	now       func() time.Time // 테스트에서 시간을 제어하기 위한 시계
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter는 클라이언트별로 초당 requestsPerSecond개, 최대 burst개의 요청을 허용하는 RateLimiter를 생성합니다.
+// requestsPerSecond가 0 이하이면 요청 수를 제한하지 않습니다.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	l := &RateLimiter{now: time.Now}
+	l.SetLimit(requestsPerSecond, burst)
+	return l
+}
+
+// SetLimit은 제한 값을 교체합니다. 기존 클라이언트별 상태는 초기화됩니다.
+func (l *RateLimiter) SetLimit(requestsPerSecond float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = rate.Limit(requestsPerSecond)
+	l.burst = burst
+	l.clients = make(map[string]*clientLimiter)
+}
+
+// Handler는 next 핸들러 앞에서 클라이언트별 요청 수를 확인합니다.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := clientIP(r)
+
+		if wait, ok := l.reserve(key); !ok {
+			logging.FromContext(r.Context()).Warn("client rate limit exceeded", "client", key, "retry_after", wait)
+			metrics.IncRateLimited("client")
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			problem.Write(w, r, http.StatusTooManyRequests, "rate_limited", "rate limit exceeded, retry later")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reserve는 key에 해당하는 클라이언트의 토큰을 하나 소비합니다.
+// 토큰이 없으면 false와 함께 다음 토큰이 생길 때까지의 대기 시간을 반환합니다.
+func (l *RateLimiter) reserve(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		return 0, true
+	}
+
+	now := l.now()
+	l.sweep(now)
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+
+	// Reserve는 대기 시간을 알려주므로 Allow 대신 사용하고, 기다리지 않을 것이므로 바로 취소합니다.
+	res := c.limiter.ReserveN(now, 1)
+	if wait := res.DelayFrom(now); wait > 0 {
+		res.CancelAt(now)
+		return wait, false
+	}
+	return 0, true
+}
+
+// sweep은 오랫동안 요청이 없는 클라이언트의 limiter를 제거하여 메모리 사용량을 제한합니다.
+// 호출자가 l.mu를 잡고 있어야 합니다.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) > limiterIdleTTL {
+			delete(l.clients, key)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// filepath: helm-ecr-api/internal/middleware/ratelimit_test.go
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeClock은 테스트에서 RateLimiter의 시간을 직접 진행시키는 시계입니다.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// newTestLimiter는 fakeClock을 사용하는 RateLimiter를 생성합니다.
+func newTestLimiter(requestsPerSecond float64, burst int) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewRateLimiter(requestsPerSecond, burst)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	l, clock := newTestLimiter(2, 3)
+
+	// burst만큼은 바로 허용합니다.
+	for i := 0; i < 3; i++ {
+		if _, ok := l.reserve("a"); !ok {
+			t.Fatalf("request %d was rejected within the burst", i+1)
+		}
+	}
+	wait, ok := l.reserve("a")
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("reserve() after the burst = %s, %v, want 500ms, false", wait, ok)
+	}
+	// 거절된 요청은 토큰을 소비하지 않으므로 대기 시간이 늘어나지 않습니다.
+	if wait, _ := l.reserve("a"); wait != 500*time.Millisecond {
+		t.Errorf("reserve() again = %s, want 500ms", wait)
+	}
+
+	// 다른 클라이언트는 별도의 버킷을 사용합니다.
+	if _, ok := l.reserve("b"); !ok {
+		t.Error("another client was rejected")
+	}
+
+	// 초당 2개씩 다시 채워집니다.
+	clock.advance(500 * time.Millisecond)
+	if _, ok := l.reserve("a"); !ok {
+		t.Error("request was rejected after a token was refilled")
+	}
+	if _, ok := l.reserve("a"); ok {
+		t.Error("request was allowed before the next token was refilled")
+	}
+
+	// 오래 기다려도 burst보다 많이 쌓이지 않습니다.
+	clock.advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		if _, ok := l.reserve("a"); !ok {
+			t.Fatalf("request %d was rejected after the bucket was refilled", i+1)
+		}
+	}
+	if _, ok := l.reserve("a"); ok {
+		t.Error("bucket held more than burst tokens")
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l, _ := newTestLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		if _, ok := l.reserve("a"); !ok {
+			t.Fatalf("request %d was rejected without a limit", i+1)
+		}
+	}
+	if len(l.clients) != 0 {
+		t.Errorf("clients = %d, want no state without a limit", len(l.clients))
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	l, clock := newTestLimiter(1, 1)
+	l.reserve("idle")
+	l.reserve("active")
+
+	// 정리 간격이 지나지 않으면 정리하지 않습니다.
+	clock.advance(limiterIdleTTL + time.Second)
+	l.lastSweep = clock.now()
+	l.reserve("active")
+	if _, ok := l.clients["idle"]; !ok {
+		t.Fatal("idle client was removed before the sweep interval")
+	}
+
+	clock.advance(limiterSweepInterval)
+	l.reserve("active")
+	if _, ok := l.clients["idle"]; ok {
+		t.Error("idle client was not removed")
+	}
+	if _, ok := l.clients["active"]; !ok {
+		t.Error("active client was removed")
+	}
+
+	// 제거된 클라이언트는 새 버킷으로 다시 시작합니다.
+	if _, ok := l.reserve("idle"); !ok {
+		t.Error("removed client did not get a new bucket")
+	}
+}
+
+func TestRateLimiterSetLimit(t *testing.T) {
+	l, _ := newTestLimiter(1, 1)
+	l.reserve("a")
+	if _, ok := l.reserve("a"); ok {
+		t.Fatal("request was allowed beyond the burst")
+	}
+
+	l.SetLimit(1, 2)
+	for i := 0; i < 2; i++ {
+		if _, ok := l.reserve("a"); !ok {
+			t.Fatalf("request %d was rejected after the limit was raised", i+1)
+		}
+	}
+}
+
+func TestRateLimiterHandler(t *testing.T) {
+	l, _ := newTestLimiter(1, 1)
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/v1/helm-charts", nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := request("203.0.113.7:5000"); w.Code != http.StatusNoContent {
+		t.Fatalf("first request status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	// 포트가 달라도 같은 클라이언트 IP이므로 같은 버킷을 사용합니다.
+	w := request("203.0.113.7:6000")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want %q", got, "1")
+	}
+	if w := request("203.0.113.8:5000"); w.Code != http.StatusNoContent {
+		t.Errorf("other client status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,79 @@
+// filepath: helm-ecr-api/internal/middleware/response.go
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// contextKey는 컨텍스트 값의 키로 사용되어 충돌을 방지합니다.
+type contextKey string
+
+const requestInfoKey contextKey = "request-info"
+
+// requestInfo는 하위 핸들러가 결정한 요청 정보(라우트)를 상위 미들웨어에 전달하기 위한 값입니다.
+// 컨텍스트는 하위 핸들러에서 상위로 값을 돌려줄 수 없으므로 포인터를 저장해 두고 하위에서 채웁니다.
+type requestInfo struct {
+	route string
+}
+
+// SetRoute는 현재 요청의 라우트 템플릿(예: "/v1/helm-charts/{repo}")을 지정합니다.
+// 메트릭과 접근 로그의 라벨로 사용되므로, 실제 경로가 아닌 템플릿을 지정해야 카디널리티가 제한됩니다.
+func SetRoute(ctx context.Context, route string) {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		info.route = route
+	}
+}
+
+// withRequestInfo는 SetRoute 등으로 채울 수 있는 requestInfo를 요청 컨텍스트에 추가합니다.
+// 이미 추가되어 있으면 기존 값을 그대로 사용합니다.
+func withRequestInfo(r *http.Request) (*http.Request, *requestInfo) {
+	if info, ok := r.Context().Value(requestInfoKey).(*requestInfo); ok {
+		return r, info
+	}
+	info := &requestInfo{}
+	return r.WithContext(context.WithValue(r.Context(), requestInfoKey, info)), info
+}
+
+// routeName은 요청이 처리된 라우트 이름을 반환합니다.
+// SetRoute로 지정된 값이 없으면 ServeMux가 매칭한 패턴(메서드 제외)을 사용합니다.
+func routeName(r *http.Request, info *requestInfo) string {
+	if info.route != "" {
+		return info.route
+	}
+	if r.Pattern == "" {
+		return "unmatched"
+	}
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		return path
+	}
+	return r.Pattern
+}
+
+// responseRecorder는 핸들러가 작성한 상태 코드와 바이트 수를 기록하는 ResponseWriter입니다.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap은 http.ResponseController가 원래 ResponseWriter의 기능(Flush 등)을 사용할 수 있도록 합니다.
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,38 @@
+// filepath: helm-ecr-api/internal/middleware/tracing.go
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing은 요청 헤더의 W3C Trace Context(traceparent)를 이어받아 서버 스팬을 생성하는 미들웨어입니다.
+// 스팬 이름은 처리가 끝난 뒤 라우트 템플릿을 사용해 "GET /v1/helm-charts/{repo}"와 같이 지정됩니다.
+// Kubernetes 프로브와 메트릭 스크랩 요청은 트레이스를 남기지 않습니다.
+func Tracing(next http.Handler) http.Handler {
+	named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, info := withRequestInfo(r)
+		next.ServeHTTP(w, r)
+
+		route := routeName(r, info)
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	})
+
+	return otelhttp.NewHandler(named, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			switch r.URL.Path {
+			case "/metrics", "/health", "/livez", "/readyz":
+				return false
+			}
+			return true
+		}),
+	)
+}
@@ -0,0 +1,47 @@
+// filepath: helm-ecr-api/internal/problem/problem.go
+package problem
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// ContentType은 RFC 7807 Problem Details 응답의 미디어 타입입니다.
+const ContentType = "application/problem+json"
+
+// typePrefix는 type 필드에 사용할 URI 접두사입니다. 에러 코드를 붙여 문제 유형을 식별합니다.
+const typePrefix = "urn:helm-ecr-api:problem:"
+
+// Details는 RFC 7807 Problem Details 응답 본문입니다.
+// 표준 필드(type, title, status, detail, instance)에 확장 필드(code, requestId)를 추가합니다.
+// 참조: https://www.rfc-editor.org/rfc/rfc7807
+type Details struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`                // 클라이언트가 분기에 사용할 안정적인 에러 코드 (예: "repo_not_found")
+	RequestID string `json:"requestId,omitempty"` // 문제 보고 시 로그를 찾기 위한 요청 ID
+}
+
+// Write는 Problem Details 응답을 작성합니다.
+// detail에는 클라이언트에 노출해도 안전한 메시지만 전달해야 합니다.
+func Write(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	body := Details{
+		Type:      typePrefix + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: w.Header().Get("X-Request-ID"),
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Default().Error("failed to encode problem response", "error", err)
+	}
+}
@@ -0,0 +1,160 @@
+// filepath: helm-ecr-api/internal/selector/selector.go
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound는 경로에 해당하는 값이 문서에 없을 때 반환됩니다.
+var ErrNotFound = errors.New("path not found")
+
+// Path는 YAML/JSON 문서에서 값 하나를 선택하는 yq 스타일 경로 식입니다.
+//
+//	.                                   문서 전체
+//	.image.repository                   map 키
+//	.containers[0].name                 배열 인덱스 (음수는 끝에서부터: [-1]은 마지막 요소)
+//	.podAnnotations["app.kubernetes.io/name"]  '.'이나 '/'가 포함된 키
+type Path struct {
+	expr  string
+	steps []step
+}
+
+// step은 경로의 한 단계로, map 키 또는 배열 인덱스 중 하나입니다.
+type step struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Parse는 경로 식을 파싱합니다. 식은 반드시 '.'으로 시작해야 합니다.
+func Parse(expr string) (*Path, error) {
+	if !strings.HasPrefix(expr, ".") {
+		return nil, fmt.Errorf("path must start with '.': %q", expr)
+	}
+
+	p := &Path{expr: expr}
+	rest := expr
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			// ".[0]"이나 '.' 하나만 있는 경우처럼 키 없이 다음 단계가 올 수 있습니다.
+			if rest == "" || rest[0] == '[' {
+				if len(p.steps) > 0 && rest == "" {
+					return nil, fmt.Errorf("path must not end with '.': %q", expr)
+				}
+				continue
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in path: %q", expr)
+			}
+			p.steps = append(p.steps, step{key: rest[:end]})
+			rest = rest[end:]
+
+		case '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in path: %q", expr)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			if strings.HasPrefix(inner, `"`) {
+				key, err := strconv.Unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid quoted key %s in path: %q", inner, expr)
+				}
+				p.steps = append(p.steps, step{key: key})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index [%s] in path: %q", inner, expr)
+			}
+			p.steps = append(p.steps, step{index: index, isIndex: true})
+
+		default:
+			return nil, fmt.Errorf("unexpected %q in path: %q", rest[0], expr)
+		}
+	}
+	return p, nil
+}
+
+// closingBracket은 s[0]의 '['에 대응하는 ']'의 위치를 반환합니다. 따옴표 안의 ']'는 무시합니다.
+func closingBracket(s string) int {
+	inQuote := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case inQuote && s[i] == '\\':
+			i++ // 이스케이프된 문자 건너뛰기
+		case s[i] == '"':
+			inQuote = !inQuote
+		case !inQuote && s[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// String은 원래 경로 식을 반환합니다.
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Select는 encoding/json으로 디코딩한 문서(map[string]any, []any, 스칼라)에서 경로에 해당하는 값을 반환합니다.
+// 값이 없거나 중간 값의 타입이 맞지 않으면 ErrNotFound를 감싼 에러를 반환합니다.
+func (p *Path) Select(doc any) (any, error) {
+	current := doc
+	for i, s := range p.steps {
+		var ok bool
+		if s.isIndex {
+			current, ok = selectIndex(current, s.index)
+		} else {
+			var m map[string]any
+			if m, ok = current.(map[string]any); ok {
+				current, ok = m[s.key]
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, p.prefix(i+1))
+		}
+	}
+	return current, nil
+}
+
+func selectIndex(v any, index int) (any, bool) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	if index < 0 {
+		index += len(list)
+	}
+	if index < 0 || index >= len(list) {
+		return nil, false
+	}
+	return list[index], true
+}
+
+// prefix는 처음 n개 단계까지의 경로 식을 에러 메시지용으로 다시 구성합니다.
+func (p *Path) prefix(n int) string {
+	var b strings.Builder
+	for _, s := range p.steps[:n] {
+		switch {
+		case s.isIndex:
+			fmt.Fprintf(&b, "[%d]", s.index)
+		case strings.ContainsAny(s.key, `.[]"`):
+			fmt.Fprintf(&b, "[%q]", s.key)
+		default:
+			b.WriteString("." + s.key)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+// filepath: helm-ecr-api/internal/selector/selector_test.go
+package selector
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"replicaCount": 2,
+	"image": {"repository": "nginx", "tag": ""},
+	"podAnnotations": {"app.kubernetes.io/name": "web", "say \"hi\"": "yes", "a]b": "bracket"},
+	"containers": [{"name": "app"}, {"name": "sidecar"}],
+	"enabled": null
+}`
+
+func TestSelect(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(testDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{expr: ".replicaCount", want: float64(2)},
+		{expr: ".image.repository", want: "nginx"},
+		{expr: ".image.tag", want: ""},
+		{expr: ".image", want: map[string]any{"repository": "nginx", "tag": ""}},
+		{expr: `.podAnnotations["app.kubernetes.io/name"]`, want: "web"},
+		{expr: `.podAnnotations["say \"hi\""]`, want: "yes"},
+		{expr: `.podAnnotations["a]b"]`, want: "bracket"},
+		{expr: `.["image"].repository`, want: "nginx"},
+		{expr: ".containers[0].name", want: "app"},
+		{expr: ".containers[-1].name", want: "sidecar"},
+		{expr: ".containers.[1].name", want: "sidecar"},
+		{expr: ".enabled", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if p.String() != tt.expr {
+				t.Errorf("String() = %q, want %q", p.String(), tt.expr)
+			}
+			got, err := p.Select(doc)
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	// "."은 문서 전체를 선택합니다.
+	p, err := Parse(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.Select(doc); !reflect.DeepEqual(got, doc) {
+		t.Errorf(`Select(".") = %v, want the whole document`, got)
+	}
+}
+
+func TestSelectNotFound(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(testDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr     string
+		wantPath string // 에러 메시지에 표시할 찾지 못한 경로
+	}{
+		{expr: ".missing", wantPath: ".missing"},
+		{expr: ".image.digest", wantPath: ".image.digest"},
+		{expr: ".image.repository.name", wantPath: ".image.repository.name"},
+		{expr: ".containers[2]", wantPath: ".containers[2]"},
+		{expr: ".containers[-3]", wantPath: ".containers[-3]"},
+		{expr: ".image[0]", wantPath: ".image[0]"},
+		{expr: ".containers.name", wantPath: ".containers.name"},
+		{expr: `.podAnnotations["app.kubernetes.io/part-of"].x`, wantPath: `.podAnnotations["app.kubernetes.io/part-of"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			_, err = p.Select(doc)
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Select() error = %v, want ErrNotFound", err)
+			}
+			if !strings.HasSuffix(err.Error(), ": "+tt.wantPath) {
+				t.Errorf("error = %q, want it to end with %q", err, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "", wantErr: "must start with '.'"},
+		{expr: "image.tag", wantErr: "must start with '.'"},
+		{expr: ".image.", wantErr: "must not end with '.'"},
+		{expr: "..image", wantErr: "empty key"},
+		{expr: ".containers[0", wantErr: "unclosed '['"},
+		{expr: `.podAnnotations["a]`, wantErr: "unclosed '['"},
+		{expr: ".containers[x]", wantErr: "invalid index"},
+		{expr: ".containers[]", wantErr: "invalid index"},
+		{expr: `.podAnnotations["\q"]`, wantErr: "invalid quoted key"},
+		{expr: ".containers[0]name", wantErr: "unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want it to contain %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// filepath: helm-ecr-api/internal/service/archive.go
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// ArchiveLimits는 레지스트리에서 받은 차트 아카이브를 해제할 때 적용하는 제한입니다.
+// 레지스트리에 올라간 아카이브는 신뢰할 수 없으므로, 압축 폭탄이나 경로 조작이 포함된 아카이브를 거부합니다.
+// 각 값이 0이면 해당 항목은 제한하지 않습니다.
+type ArchiveLimits struct {
+	MaxCompressedSize   int64 // 레지스트리에서 내려받는 압축된 차트 레이어의 최대 크기(바이트)
+	MaxUncompressedSize int64 // 압축 해제한 tar 스트림 전체의 최대 크기(바이트)
+	MaxEntries          int   // 최대 항목(파일, 디렉토리) 수
+	MaxPathLength       int   // 항목 경로의 최대 길이
+}
+
+// defaultArchiveLimits는 Helm이 차트를 읽을 때 사용하는 제한(압축 해제 크기 100MiB)과 비슷한 수준의 기본값입니다.
+var defaultArchiveLimits = ArchiveLimits{
+	MaxCompressedSize:   20 << 20,
+	MaxUncompressedSize: 100 << 20,
+	MaxEntries:          10000,
+	MaxPathLength:       1024,
+}
+
+// archiveReader는 제한과 경로 검증을 적용하면서 차트 아카이브(.tgz)의 항목을 순서대로 읽습니다.
+// 차트 아카이브를 해제하는 모든 곳에서 tar.Reader를 직접 사용하지 말고 archiveReader를 사용해야 합니다.
+//
+//	ar, err := newArchiveReader(archive, limits)
+//	defer ar.Close()
+//	for {
+//		header, err := ar.Next()  // 일반 파일만 반환, 위험한 항목이 있으면 CodeInvalidArchive 에러
+//		...
+//		io.ReadAll(ar)            // 현재 파일 내용
+//	}
+type archiveReader struct {
+	gz     *gzip.Reader
+	tar    *tar.Reader
+	limits ArchiveLimits
+	usage  *archiveUsage
+}
+
+// archiveUsage는 아카이브와 그 안에서 펼친 하위 차트 아카이브가 함께 사용하는 제한 사용량입니다.
+// 하위 차트마다 제한을 새로 적용하면 중첩된 아카이브로 제한을 여러 번 받을 수 있으므로 하나의 사용량을 공유합니다.
+type archiveUsage struct {
+	entries int
+	// declared는 tar 헤더에 기록된 파일 크기의 합입니다. 실제로 읽기 전에 제한 초과를 발견하기 위해 사용합니다.
+	declared int64
+	// remaining은 실제로 더 압축 해제할 수 있는 바이트 수입니다. (MaxUncompressedSize가 0이면 사용하지 않음)
+	remaining int64
+}
+
+// newArchiveReader는 gzip으로 압축된 tar 아카이브를 읽는 archiveReader를 생성합니다.
+func newArchiveReader(archive []byte, limits ArchiveLimits) (*archiveReader, error) {
+	return openArchive(archive, limits, &archiveUsage{remaining: limits.MaxUncompressedSize})
+}
+
+// nested는 현재 아카이브 안의 아카이브(하위 차트 .tgz)를 같은 제한과 사용량으로 읽는 archiveReader를 생성합니다.
+func (a *archiveReader) nested(archive []byte) (*archiveReader, error) {
+	return openArchive(archive, a.limits, a.usage)
+}
+
+func openArchive(archive []byte, limits ArchiveLimits, usage *archiveUsage) (*archiveReader, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, newError(CodeInvalidArchive, err, "chart archive is not a valid gzip stream")
+	}
+
+	// 압축 해제된 바이트 수를 제한하여, 헤더를 속이거나 건너뛰는 항목에 큰 데이터를 숨긴 압축 폭탄도 막습니다.
+	var stream io.Reader = gz
+	if limits.MaxUncompressedSize > 0 {
+		stream = &sizeLimitedReader{r: gz, remaining: &usage.remaining}
+	}
+
+	return &archiveReader{
+		gz:     gz,
+		tar:    tar.NewReader(stream),
+		limits: limits,
+		usage:  usage,
+	}, nil
+}
+
+// Next는 다음 일반 파일의 헤더를 반환합니다. 디렉토리는 검증만 하고 건너뜁니다.
+// 아카이브 끝에 도달하면 io.EOF를 반환합니다.
+func (a *archiveReader) Next() (*tar.Header, error) {
+	for {
+		header, err := a.tar.Next()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, archiveError(err)
+		}
+
+		// PAX 전역 헤더는 파일이 아니라 이후 항목의 메타데이터이므로 항목 수에 포함하지 않습니다.
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		a.usage.entries++
+		if a.limits.MaxEntries > 0 && a.usage.entries > a.limits.MaxEntries {
+			return nil, newError(CodeInvalidArchive, nil, "chart archive has more than %d entries", a.limits.MaxEntries)
+		}
+		if err := a.checkPath(header.Name); err != nil {
+			return nil, err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			// 심볼릭 링크와 하드 링크는 아카이브 밖의 파일을 가리킬 수 있고, 장치 파일 등은 차트에 필요하지 않습니다.
+			return nil, newError(CodeInvalidArchive, nil, "chart archive contains unsupported entry type %q: %s", header.Typeflag, header.Name)
+		}
+
+		if header.Size < 0 {
+			return nil, newError(CodeInvalidArchive, nil, "chart archive entry has a negative size: %s", header.Name)
+		}
+		a.usage.declared += header.Size
+		if a.limits.MaxUncompressedSize > 0 && a.usage.declared > a.limits.MaxUncompressedSize {
+			return nil, newError(CodeInvalidArchive, nil, "chart archive exceeds the uncompressed size limit of %d bytes", a.limits.MaxUncompressedSize)
+		}
+		return header, nil
+	}
+}
+
+// Read는 Next가 반환한 현재 파일의 내용을 읽습니다.
+func (a *archiveReader) Read(p []byte) (int, error) {
+	n, err := a.tar.Read(p)
+	if err != nil && err != io.EOF {
+		return n, archiveError(err)
+	}
+	return n, err
+}
+
+// Close는 gzip 리더를 닫습니다.
+func (a *archiveReader) Close() error {
+	return a.gz.Close()
+}
+
+// checkPath는 항목 경로가 아카이브 밖을 가리키거나 지나치게 길지 않은지 확인합니다.
+func (a *archiveReader) checkPath(name string) error {
+	if a.limits.MaxPathLength > 0 && len(name) > a.limits.MaxPathLength {
+		return newError(CodeInvalidArchive, nil, "chart archive entry path exceeds %d characters", a.limits.MaxPathLength)
+	}
+	if name == "" || strings.ContainsAny(name, "\\\x00") {
+		return newError(CodeInvalidArchive, nil, "chart archive contains an invalid entry path: %q", name)
+	}
+	if path.IsAbs(name) {
+		return newError(CodeInvalidArchive, nil, "chart archive contains an absolute path: %s", name)
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return newError(CodeInvalidArchive, nil, "chart archive contains a path outside the chart: %s", name)
+		}
+	}
+	return nil
+}
+
+// errUncompressedLimit은 압축 해제한 데이터가 MaxUncompressedSize를 넘었음을 나타냅니다.
+var errUncompressedLimit = errors.New("uncompressed size limit exceeded")
+
+// sizeLimitedReader는 remaining 바이트를 넘게 읽으면 errUncompressedLimit을 반환합니다.
+// io.LimitReader와 달리 한도에 도달했을 때 io.EOF가 아닌 에러를 반환하여 잘린 아카이브와 구분합니다.
+// remaining은 하위 차트 아카이브를 읽는 리더와 공유할 수 있도록 포인터입니다.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining *int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if *l.remaining <= 0 {
+		// 정확히 한도에서 끝나는 스트림을 허용하기 위해 1바이트를 더 읽어 봅니다.
+		var probe [1]byte
+		if n, err := l.r.Read(probe[:]); n == 0 && err != nil {
+			return 0, err
+		}
+		return 0, errUncompressedLimit
+	}
+	if int64(len(p)) > *l.remaining {
+		p = p[:*l.remaining]
+	}
+	n, err := l.r.Read(p)
+	*l.remaining -= int64(n)
+	return n, err
+}
+
+// archiveError는 아카이브를 읽는 중 발생한 에러를 CodeInvalidArchive 에러로 변환합니다.
+func archiveError(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if errors.Is(err, errUncompressedLimit) {
+		return newError(CodeInvalidArchive, err, "chart archive exceeds the uncompressed size limit")
+	}
+	return newError(CodeInvalidArchive, err, "chart archive is corrupted")
+}
@@ -0,0 +1,200 @@
+// filepath: helm-ecr-api/internal/service/archive_test.go
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testEntry는 테스트용 아카이브에 넣을 항목입니다. body가 있으면 Size는 body 길이로 채웁니다.
+type testEntry struct {
+	header tar.Header
+	body   string
+}
+
+// regularFile은 일반 파일 항목을 만듭니다.
+func regularFile(name, body string) testEntry {
+	return testEntry{header: tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644}, body: body}
+}
+
+// buildArchive는 entries로 gzip으로 압축된 tar 아카이브를 만듭니다.
+func buildArchive(t *testing.T, entries ...testEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		h := e.header
+		if h.Typeflag == tar.TypeReg {
+			h.Size = int64(len(e.body))
+		}
+		if err := tw.WriteHeader(&h); err != nil {
+			t.Fatalf("write header %s: %v", h.Name, err)
+		}
+		if _, err := io.WriteString(tw, e.body); err != nil {
+			t.Fatalf("write body %s: %v", h.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// readArchive는 archiveReader로 아카이브의 모든 파일을 읽어 이름 목록을 반환합니다.
+func readArchive(archive []byte, limits ArchiveLimits) ([]string, error) {
+	ar, err := newArchiveReader(archive, limits)
+	if err != nil {
+		return nil, err
+	}
+	defer ar.Close()
+
+	var names []string
+	for {
+		header, err := ar.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return names, err
+		}
+		if _, err := io.ReadAll(ar); err != nil {
+			return names, err
+		}
+		names = append(names, header.Name)
+	}
+}
+
+func TestArchiveReader(t *testing.T) {
+	limits := ArchiveLimits{MaxUncompressedSize: 8192, MaxEntries: 4, MaxPathLength: 32}
+
+	tests := []struct {
+		name    string
+		entries []testEntry
+		want    []string
+		wantErr string // 비어 있으면 에러가 없어야 합니다
+	}{
+		{
+			name: "regular chart",
+			entries: []testEntry{
+				{header: tar.Header{Name: "app/", Typeflag: tar.TypeDir, Mode: 0o755}},
+				regularFile("app/Chart.yaml", "name: app\n"),
+				regularFile("app/values.yaml", "replicas: 1\n"),
+			},
+			want: []string{"app/Chart.yaml", "app/values.yaml"},
+		},
+		{
+			name: "pax global header is not an entry",
+			entries: []testEntry{
+				{header: tar.Header{Name: "pax_global_header", Typeflag: tar.TypeXGlobalHeader, PAXRecords: map[string]string{"comment": "x"}}},
+				regularFile("app/Chart.yaml", "name: app\n"),
+			},
+			want: []string{"app/Chart.yaml"},
+		},
+		{
+			name:    "parent directory",
+			entries: []testEntry{regularFile("app/../../etc/passwd", "x")},
+			wantErr: "outside the chart",
+		},
+		{
+			name:    "parent directory at the start",
+			entries: []testEntry{regularFile("../Chart.yaml", "x")},
+			wantErr: "outside the chart",
+		},
+		{
+			name:    "absolute path",
+			entries: []testEntry{regularFile("/etc/passwd", "x")},
+			wantErr: "absolute path",
+		},
+		{
+			name:    "backslash",
+			entries: []testEntry{regularFile(`app\..\Chart.yaml`, "x")},
+			wantErr: "invalid entry path",
+		},
+		{
+			name:    "symlink",
+			entries: []testEntry{{header: tar.Header{Name: "app/link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}}},
+			wantErr: "unsupported entry type",
+		},
+		{
+			name:    "hard link",
+			entries: []testEntry{{header: tar.Header{Name: "app/link", Typeflag: tar.TypeLink, Linkname: "app/Chart.yaml"}}},
+			wantErr: "unsupported entry type",
+		},
+		{
+			name:    "path too long",
+			entries: []testEntry{regularFile("app/"+strings.Repeat("a", 32), "x")},
+			wantErr: "path exceeds 32 characters",
+		},
+		{
+			name: "too many entries",
+			entries: []testEntry{
+				regularFile("app/a", "x"), regularFile("app/b", "x"), regularFile("app/c", "x"), regularFile("app/d", "x"), regularFile("app/e", "x"),
+			},
+			want:    []string{"app/a", "app/b", "app/c", "app/d"},
+			wantErr: "more than 4 entries",
+		},
+		{
+			name:    "declared size over limit",
+			entries: []testEntry{regularFile("app/big", strings.Repeat("x", 8193))},
+			wantErr: "uncompressed size limit",
+		},
+		{
+			name: "total size over limit",
+			entries: []testEntry{
+				regularFile("app/a", strings.Repeat("x", 4100)),
+				regularFile("app/b", strings.Repeat("x", 4100)),
+			},
+			want:    []string{"app/a"},
+			wantErr: "uncompressed size limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := readArchive(buildArchive(t, tt.entries...), limits)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				var e *Error
+				if !errors.As(err, &e) || e.Code != CodeInvalidArchive {
+					t.Fatalf("error = %v, want %s", err, CodeInvalidArchive)
+				}
+				if !strings.Contains(e.Message, tt.wantErr) {
+					t.Fatalf("message = %q, want it to contain %q", e.Message, tt.wantErr)
+				}
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("files = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveReaderInvalidGzip(t *testing.T) {
+	_, err := newArchiveReader([]byte("not a gzip stream"), defaultArchiveLimits)
+	if err == nil || ErrorCodeOf(err) != CodeInvalidArchive {
+		t.Fatalf("error = %v, want %s", err, CodeInvalidArchive)
+	}
+}
+
+// TestArchiveReaderStreamLimit는 tar 헤더에 기록된 크기가 아니라 압축 해제한 스트림 크기로도 제한하는지 확인합니다.
+// 헤더와 블록 패딩, 아카이브 끝 표시도 압축을 해제해야 하므로 크기에 포함됩니다.
+func TestArchiveReaderStreamLimit(t *testing.T) {
+	archive := buildArchive(t, regularFile("app/values.yaml", strings.Repeat("x", 1000)))
+	_, err := readArchive(archive, ArchiveLimits{MaxUncompressedSize: 2048})
+	var e *Error
+	if !errors.As(err, &e) || e.Code != CodeInvalidArchive || !errors.Is(err, errUncompressedLimit) {
+		t.Fatalf("error = %v, want %s caused by the uncompressed size limit", err, CodeInvalidArchive)
+	}
+}
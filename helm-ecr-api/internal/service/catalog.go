@@ -0,0 +1,581 @@
+// filepath: helm-ecr-api/internal/service/catalog.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/store"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"helm.sh/helm/v3/pkg/chart"
+	"sigs.k8s.io/yaml"
+)
+
+// helmConfigMediaType은 Helm 차트 매니페스트의 config 미디어 타입입니다. ECR은 이 값을 artifactMediaType으로 알려 줍니다.
+const helmConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
+
+// 카탈로그에 저장하는 values 키의 제한. 큰 values.yaml(예: 하위 차트 설정이 많은 우산 차트)이 카탈로그를 키우지 않도록 합니다.
+const (
+	maxIndexedValuesKeys  = 2000
+	maxIndexedValuesDepth = 8
+)
+
+// DataSource는 조회 결과를 어디에서 가져왔는지 나타냅니다.
+type DataSource string
+
+const (
+	DataSourceLive    DataSource = "live"    // 요청을 처리하면서 ECR을 직접 조회함
+	DataSourceCatalog DataSource = "catalog" // 백그라운드 동기화로 저장해 둔 카탈로그에서 읽음
+)
+
+// Freshness는 조회 결과의 출처와 기준 시각입니다.
+type Freshness struct {
+	Source   DataSource
+	SyncedAt time.Time // 결과를 ECR에서 조회한 시각 (live이면 요청 처리 시각)
+}
+
+// liveFreshness는 ECR을 직접 조회한 결과의 Freshness를 반환합니다.
+func liveFreshness() Freshness {
+	return Freshness{Source: DataSourceLive, SyncedAt: time.Now()}
+}
+
+// catalog는 허용된 리포지토리, 이미지(차트 버전), 차트 메타데이터의 로컬 사본입니다.
+// 백그라운드 동기화가 리포지토리 단위로 레코드를 교체하므로, 조회는 갱신 중에도 이전 또는 새 스냅숏 중 하나를 일관되게 봅니다.
+// store가 있으면 레코드를 파일에도 기록하여, 재시작 직후에도 첫 동기화를 기다리지 않고 카탈로그로 응답할 수 있습니다.
+type catalog struct {
+	store        *store.Store // nil이면 메모리에만 보관
+	interval     time.Duration
+	concurrency  int
+	maxStaleness time.Duration // 이보다 오래된 레코드는 사용하지 않고 ECR을 직접 조회
+	trigger      chan struct{} // 주기를 기다리지 않고 동기화를 요청 (버퍼 1)
+	searchOnly   bool          // 검색에만 사용하고 목록과 상세 조회는 항상 ECR을 직접 조회
+
+	mu       sync.RWMutex
+	repos    map[string]*store.RepositoryRecord // 리포지토리 이름 → 레코드
+	skipped  map[string]struct{}                // 차트가 아니거나 읽을 수 없는 매니페스트 ("repo@digest"). 다이제스트가 같으면 결과도 같으므로 다시 시도하지 않습니다.
+	syncedAt time.Time                          // 마지막 전체 동기화를 마친 시각 (zero면 아직 동기화하지 않음)
+}
+
+// WithCatalog는 리포지토리와 차트 버전을 로컬 카탈로그에 동기화하여 목록, 상세 조회, 검색에 사용합니다.
+// RunCatalogSync가 interval 주기로 허용된 리포지토리를 동기화하며, 새 다이제스트의 차트는 최대 concurrency개씩 동시에 내려받습니다.
+// maxStaleness보다 오래된 레코드나 카탈로그에 없는 버전은 ECR을 직접 조회합니다.
+// st가 nil이면 카탈로그를 메모리에만 보관합니다.
+func WithCatalog(st *store.Store, interval time.Duration, concurrency int, maxStaleness time.Duration) Option {
+	return func(s *ECRService) {
+		s.catalog = &catalog{
+			store:        st,
+			interval:     interval,
+			concurrency:  concurrency,
+			maxStaleness: maxStaleness,
+			trigger:      make(chan struct{}, 1),
+			repos:        make(map[string]*store.RepositoryRecord),
+			skipped:      make(map[string]struct{}),
+		}
+	}
+}
+
+// WithSearchIndex는 카탈로그를 사용하지 않을 때 차트 검색용 메모리 색인을 동기화합니다.
+// 카탈로그와 같은 방식으로 interval 주기로 동기화하지만, 목록과 상세 조회에는 사용하지 않아 응답은 항상 ECR을 직접 조회한 결과입니다.
+// WithCatalog와 함께 지정하면 나중에 적용한 옵션이 사용됩니다.
+func WithSearchIndex(interval time.Duration, concurrency int) Option {
+	return func(s *ECRService) {
+		s.catalog = &catalog{
+			interval:     interval,
+			concurrency:  concurrency,
+			maxStaleness: interval,
+			trigger:      make(chan struct{}, 1),
+			searchOnly:   true,
+			repos:        make(map[string]*store.RepositoryRecord),
+			skipped:      make(map[string]struct{}),
+		}
+	}
+}
+
+// load는 저장소에 기록된 카탈로그를 메모리로 읽습니다.
+func (c *catalog) load() error {
+	if c.store == nil {
+		return nil
+	}
+	snap, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.repos = snap.Repositories
+	c.skipped = snap.Skipped
+	c.syncedAt = snap.LastSync
+	if !c.syncedAt.IsZero() {
+		metrics.SetCatalogSync(c.countVersions(), c.syncedAt)
+	}
+	return nil
+}
+
+// requestSync는 다음 주기를 기다리지 않고 동기화하도록 요청합니다. 이미 요청이 대기 중이면 아무것도 하지 않습니다.
+func (c *catalog) requestSync() {
+	select {
+	case c.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// fresh는 t에 조회한 레코드를 아직 사용할 수 있는지 반환합니다.
+func (c *catalog) fresh(t time.Time) bool {
+	return !t.IsZero() && time.Since(t) <= c.maxStaleness
+}
+
+// countVersions는 카탈로그에 있는 차트 버전 수를 반환합니다. c.mu를 잠근 상태에서 호출해야 합니다.
+func (c *catalog) countVersions() int {
+	n := 0
+	for _, rec := range c.repos {
+		for _, v := range rec.Versions {
+			if v.Chart != nil {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (c *catalog) isSkipped(repoName, digest string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.skipped[repoName+"@"+digest]
+	return ok
+}
+
+// persistCatalog는 저장소가 설정되어 있으면 fn으로 변경 사항을 기록합니다.
+// 저장에 실패해도 메모리의 카탈로그는 최신 상태이므로 경고만 남기며, 다음 동기화에서 다시 기록됩니다.
+func (s *ECRService) persistCatalog(op string, fn func(st *store.Store) error) {
+	if s.catalog.store == nil {
+		return
+	}
+	if err := fn(s.catalog.store); err != nil {
+		s.logger.Warn("failed to write catalog store", "op", op, "error", err)
+	}
+}
+
+// catalogRepositories는 카탈로그가 최신 상태이면 허용된 리포지토리 목록(이름순)과 마지막 전체 동기화 시각을 반환합니다.
+func (s *ECRService) catalogRepositories() ([]types.Repository, time.Time, bool) {
+	c := s.catalog
+	if c == nil || c.searchOnly {
+		return nil, time.Time{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.fresh(c.syncedAt) {
+		return nil, time.Time{}, false
+	}
+
+	repos := []types.Repository{}
+	for name, rec := range c.repos {
+		// 설정 리로드로 허용 목록에서 빠진 리포지토리는 다음 동기화 전이라도 반환하지 않습니다.
+		if s.isRepoAllowed(name) {
+			repos = append(repos, rec.Repository)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return aws.ToString(repos[i].RepositoryName) < aws.ToString(repos[j].RepositoryName)
+	})
+	return repos, c.syncedAt, true
+}
+
+// catalogImages는 카탈로그에서 리포지토리의 이미지를 찾습니다.
+// tag 또는 digest가 있으면 해당 버전만, 없으면 모든 버전을 반환합니다.
+// 리포지토리 레코드가 없거나 오래되었거나 일치하는 버전이 없으면 ok가 false입니다. (호출자는 ECR을 직접 조회합니다)
+func (s *ECRService) catalogImages(repoName, tag, digest string) (_ []types.ImageDetail, syncedAt time.Time, ok bool) {
+	c := s.catalog
+	if c == nil || c.searchOnly {
+		return nil, time.Time{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rec, found := c.repos[repoName]
+	if !found || !c.fresh(rec.SyncedAt) {
+		return nil, time.Time{}, false
+	}
+
+	var images []types.ImageDetail
+	for _, v := range rec.Versions {
+		switch {
+		case tag != "" && !slices.Contains(v.Image.ImageTags, tag):
+			continue
+		case digest != "" && aws.ToString(v.Image.ImageDigest) != digest:
+			continue
+		}
+		images = append(images, v.Image)
+	}
+	if len(images) == 0 {
+		return nil, time.Time{}, false
+	}
+	return images, rec.SyncedAt, true
+}
+
+// RunCatalogSync는 카탈로그를 주기적으로 동기화합니다.
+// ctx가 취소될 때까지 블로킹되므로 고루틴으로 실행해야 합니다.
+// 카탈로그가 설정되지 않은 경우 즉시 반환합니다.
+func (s *ECRService) RunCatalogSync(ctx context.Context) {
+	c := s.catalog
+	if c == nil {
+		return
+	}
+
+	// 저장소에서 읽은 카탈로그가 있어도 시작 직후 한 번 동기화한 뒤 주기적으로 갱신합니다.
+	s.syncCatalog(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.trigger:
+		}
+		s.syncCatalog(ctx)
+	}
+}
+
+// syncCatalog는 허용된 모든 리포지토리의 이미지를 조회하여 카탈로그를 갱신합니다.
+// 이미 읽은 다이제스트는 ECR 정보(태그, pull 시각 등)만 갱신하고, 새 다이제스트의 차트만 내려받습니다.
+// 리포지토리 조회에 실패하면 해당 리포지토리의 기존 레코드를 유지하여 일시적인 AWS 오류로 결과가 사라지지 않도록 합니다.
+// (레코드가 maxStaleness보다 오래되면 조회 시 ECR을 직접 호출합니다)
+func (s *ECRService) syncCatalog(ctx context.Context) {
+	start := time.Now()
+	repos, err := s.listRepositories(ctx)
+	if err != nil {
+		s.logger.Error("failed to list repositories for catalog sync", "error", err)
+		return
+	}
+
+	listed := make(map[string]struct{}, len(repos))
+	failed := 0
+	for _, repo := range repos {
+		if ctx.Err() != nil {
+			return
+		}
+		repoName := aws.ToString(repo.RepositoryName)
+		listed[repoName] = struct{}{}
+		if err := s.syncRepository(ctx, repo); err != nil {
+			s.logger.Warn("failed to sync repository", "repo", repoName, "error", err)
+			failed++
+		}
+	}
+
+	c := s.catalog
+	c.mu.Lock()
+	// 허용 목록에서 빠졌거나 삭제된 리포지토리는 카탈로그에서도 제거합니다.
+	var removed []string
+	for repoName := range c.repos {
+		if _, ok := listed[repoName]; !ok {
+			delete(c.repos, repoName)
+			removed = append(removed, repoName)
+		}
+	}
+	for key := range c.skipped {
+		repoName, _, _ := strings.Cut(key, "@")
+		if _, ok := listed[repoName]; !ok {
+			delete(c.skipped, key)
+		}
+	}
+	c.syncedAt = time.Now()
+	syncedAt := c.syncedAt
+	versions := c.countVersions()
+	c.mu.Unlock()
+
+	for _, repoName := range removed {
+		s.persistCatalog("delete", func(st *store.Store) error { return st.DeleteRepository(repoName) })
+	}
+	s.persistCatalog("last_sync", func(st *store.Store) error { return st.SetLastSync(syncedAt) })
+
+	metrics.SetCatalogSync(versions, syncedAt)
+	s.logger.Info("synced catalog", "repositories", len(listed), "versions", versions, "removed", len(removed), "failed", failed, "duration", time.Since(start).String())
+}
+
+// syncRepository는 리포지토리 하나의 이미지 목록을 조회하여 카탈로그의 해당 리포지토리 레코드를 교체합니다.
+// Helm 차트가 아닌 이미지도 상세 조회에 사용하도록 저장하지만, 차트 메타데이터는 Helm 차트에 대해서만 읽습니다.
+func (s *ECRService) syncRepository(ctx context.Context, repo types.Repository) error {
+	repoName := aws.ToString(repo.RepositoryName)
+	details, err := s.listImageDetails(ctx, repoName)
+	if err != nil {
+		return err
+	}
+
+	c := s.catalog
+	c.mu.RLock()
+	known := make(map[string]*store.ChartMetadata)
+	if prev, ok := c.repos[repoName]; ok {
+		for _, v := range prev.Versions {
+			if v.Chart != nil {
+				known[aws.ToString(v.Image.ImageDigest)] = v.Chart
+			}
+		}
+	}
+	c.mu.RUnlock()
+
+	rec := &store.RepositoryRecord{
+		Repository: repo,
+		Versions:   make([]store.VersionRecord, len(details)),
+		SyncedAt:   time.Now(),
+	}
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, max(c.concurrency, 1))
+		mu      sync.Mutex
+		fetched int
+	)
+	for i, detail := range details {
+		// 태그와 pull 시각은 다이제스트가 같아도 바뀌므로 매번 ECR 조회 결과로 교체합니다.
+		rec.Versions[i].Image = detail
+		if !isChartImage(detail) {
+			continue
+		}
+		digest := aws.ToString(detail.ImageDigest)
+		if md, ok := known[digest]; ok {
+			rec.Versions[i].Chart = md
+			continue
+		}
+		if c.isSkipped(repoName, digest) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			md := s.loadChartMetadata(ctx, repoName, digest)
+			if md == nil {
+				return
+			}
+			rec.Versions[i].Chart = md
+
+			mu.Lock()
+			fetched++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	prev := c.repos[repoName]
+	// 처음 동기화할 때는 모든 태그가 새 태그이므로 알리지 않습니다. 마지막 전체 동기화 이후에 만든 리포지토리는 알립니다.
+	notify := prev != nil || (!c.syncedAt.IsZero() && aws.ToTime(repo.CreatedAt).After(c.syncedAt))
+	c.repos[repoName] = rec
+	c.mu.Unlock()
+	s.persistCatalog("put", func(st *store.Store) error { return st.PutRepository(repoName, rec) })
+	if notify {
+		s.notifyNewTags(repoName, prev, rec)
+	}
+
+	if fetched > 0 {
+		s.logger.Debug("synced chart versions", "repo", repoName, "new", fetched, "total", len(rec.Versions))
+	}
+	return nil
+}
+
+// isChartImage는 이미지가 차트 메타데이터를 읽을 대상인지 반환합니다.
+// 오래된 ECR 이미지는 artifactMediaType이 비어 있으므로 Helm 차트일 수 있는 것으로 봅니다.
+func isChartImage(detail types.ImageDetail) bool {
+	mt := aws.ToString(detail.ArtifactMediaType)
+	return aws.ToString(detail.ImageDigest) != "" && (mt == "" || mt == helmConfigMediaType)
+}
+
+// loadChartMetadata는 새 다이제스트의 차트 메타데이터를 내려받습니다. 읽지 못하면 nil을 반환합니다.
+// 차트가 아니거나 손상된 아카이브는 다시 내려받지 않도록 기록하며, 일시적인 에러는 다음 동기화에서 다시 시도합니다.
+func (s *ECRService) loadChartMetadata(ctx context.Context, repoName, digest string) *store.ChartMetadata {
+	md, err := s.fetchChartMetadata(ctx, repoName, digest)
+	if err != nil {
+		switch ErrorCodeOf(err) {
+		case CodeVersionNotFound, CodeInvalidArchive:
+			s.skipCatalogVersion(repoName, digest)
+		}
+		s.logger.Debug("skipping chart metadata in catalog", "repo", repoName, "digest", digest, "error", err)
+		return nil
+	}
+	return md
+}
+
+// skipCatalogVersion은 차트 메타데이터를 읽을 수 없는 버전을 기록하여 다음 동기화에서 다시 내려받지 않도록 합니다.
+func (s *ECRService) skipCatalogVersion(repoName, digest string) {
+	key := repoName + "@" + digest
+	c := s.catalog
+	c.mu.Lock()
+	c.skipped[key] = struct{}{}
+	c.mu.Unlock()
+	s.persistCatalog("skip", func(st *store.Store) error { return st.PutSkipped(key) })
+}
+
+// listImageDetails는 DescribeImages로 리포지토리의 모든 이미지(차트 버전)를 조회합니다.
+func (s *ECRService) listImageDetails(ctx context.Context, repoName string) ([]types.ImageDetail, error) {
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	var details []types.ImageDetail
+	paginator := ecr.NewDescribeImagesPaginator(s.client, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repoName),
+	})
+	for paginator.HasMorePages() {
+		var page *ecr.DescribeImagesOutput
+		err := s.retry(ctx, "ecr.DescribeImages", func(ctx context.Context) (err error) {
+			start := time.Now()
+			page, err = paginator.NextPage(ctx)
+			metrics.ObserveUpstream("ecr.DescribeImages", start, err)
+			if err != nil {
+				return classifyAWSError(err, repoName)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, page.ImageDetails...)
+	}
+	return details, nil
+}
+
+// fetchChartMetadata는 차트 아카이브를 내려받아 Chart.yaml과 values.yaml에서 카탈로그에 저장할 메타데이터를 읽습니다.
+func (s *ECRService) fetchChartMetadata(ctx context.Context, repoName, digest string) (*store.ChartMetadata, error) {
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	ref, err := s.chartReference(ctx, repoName, "", digest)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := s.registryAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	layer, err := s.fetchChartLayer(ctx, ref.Context().Digest(digest), auth)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := s.downloadLayer(ctx, ref.Context().Digest(layer.Digest.String()), layer.Size, s.archiveLimits.MaxCompressedSize, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	chartYAML, valuesYAML, err := readIndexFiles(archive, s.archiveLimits)
+	if err != nil {
+		return nil, err
+	}
+	if chartYAML == nil {
+		return nil, newError(CodeInvalidArchive, nil, "chart archive has no Chart.yaml")
+	}
+	var md chart.Metadata
+	if err := yaml.Unmarshal(chartYAML, &md); err != nil {
+		return nil, newError(CodeInvalidArchive, err, "Chart.yaml is not valid YAML")
+	}
+
+	v := &store.ChartMetadata{
+		Name:        md.Name,
+		Version:     md.Version,
+		AppVersion:  md.AppVersion,
+		Description: md.Description,
+		Keywords:    md.Keywords,
+	}
+	for _, m := range md.Maintainers {
+		if m == nil {
+			continue
+		}
+		if m.Email != "" {
+			v.Maintainers = append(v.Maintainers, fmt.Sprintf("%s <%s>", m.Name, m.Email))
+		} else {
+			v.Maintainers = append(v.Maintainers, m.Name)
+		}
+	}
+	// values.yaml을 읽지 못해도 Chart.yaml 정보로 검색할 수 있도록 저장합니다.
+	if valuesYAML != nil {
+		var values map[string]any
+		if err := yaml.Unmarshal(valuesYAML, &values); err == nil {
+			v.ValuesKeys = valuesKeys(values)
+		}
+	}
+	return v, nil
+}
+
+// readIndexFiles는 차트 아카이브에서 최상위 차트의 Chart.yaml과 values.yaml만 읽습니다.
+func readIndexFiles(archive []byte, limits ArchiveLimits) (chartYAML, valuesYAML []byte, err error) {
+	ar, err := newArchiveReader(archive, limits)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer ar.Close()
+
+	for chartYAML == nil || valuesYAML == nil {
+		header, err := ar.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// 하위 차트(<차트 이름>/charts/...)의 파일은 제외합니다.
+		_, name, _ := strings.Cut(header.Name, "/")
+		switch name {
+		case "Chart.yaml":
+			chartYAML, err = io.ReadAll(ar)
+		case "values.yaml":
+			valuesYAML, err = io.ReadAll(ar)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return chartYAML, valuesYAML, nil
+}
+
+// valuesKeys는 values의 모든 map 키 경로를 "a.b.c" 형태로 반환합니다. 중간 경로("a", "a.b")도 포함합니다.
+// 목록 안의 map은 키 경로에 "[]"를 붙여 저장합니다. (예: "extraVolumes[].name")
+func valuesKeys(values map[string]any) []string {
+	var keys []string
+	var walk func(prefix string, v any, depth int)
+	walk = func(prefix string, v any, depth int) {
+		if depth > maxIndexedValuesDepth || len(keys) >= maxIndexedValuesKeys {
+			return
+		}
+		switch t := v.(type) {
+		case map[string]any:
+			names := make([]string, 0, len(t))
+			for k := range t {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			for _, k := range names {
+				if len(keys) >= maxIndexedValuesKeys {
+					return
+				}
+				path := k
+				if prefix != "" {
+					path = prefix + "." + k
+				}
+				keys = append(keys, path)
+				walk(path, t[k], depth+1)
+			}
+		case []any:
+			// 첫 번째 map 항목만 살펴봅니다. 목록의 항목은 보통 같은 구조입니다.
+			for _, item := range t {
+				if _, ok := item.(map[string]any); ok {
+					walk(prefix+"[]", item, depth+1)
+					break
+				}
+			}
+		}
+	}
+	walk("", values, 0)
+	return keys
+}
@@ -0,0 +1,82 @@
+// filepath: helm-ecr-api/internal/service/catalog_test.go
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValuesKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   []string
+	}{
+		{
+			name:   "empty",
+			values: map[string]any{},
+			want:   nil,
+		},
+		{
+			name: "nested maps are sorted with intermediate paths",
+			values: map[string]any{
+				"service": map[string]any{"type": "ClusterIP", "port": 80},
+				"image":   map[string]any{"repository": "nginx", "tag": ""},
+			},
+			want: []string{"image", "image.repository", "image.tag", "service", "service.port", "service.type"},
+		},
+		{
+			name: "first map in a list",
+			values: map[string]any{
+				"extraVolumes": []any{
+					"skipped",
+					map[string]any{"name": "data", "emptyDir": map[string]any{}},
+					map[string]any{"hostPath": "/ignored"},
+				},
+			},
+			want: []string{"extraVolumes", "extraVolumes[].emptyDir", "extraVolumes[].name"},
+		},
+		{
+			name:   "list without maps",
+			values: map[string]any{"args": []any{"--verbose", 1}},
+			want:   []string{"args"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := valuesKeys(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("valuesKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValuesKeysLimits(t *testing.T) {
+	// maxIndexedValuesDepth보다 깊은 키는 색인하지 않습니다.
+	deep := map[string]any{}
+	v := deep
+	for i := 0; i < maxIndexedValuesDepth+3; i++ {
+		next := map[string]any{}
+		v[fmt.Sprintf("k%d", i)] = next
+		v = next
+	}
+	keys := valuesKeys(deep)
+	if len(keys) != maxIndexedValuesDepth+1 {
+		t.Errorf("deep values: got %d keys, want %d", len(keys), maxIndexedValuesDepth+1)
+	}
+	if last := keys[len(keys)-1]; strings.Count(last, ".") != maxIndexedValuesDepth {
+		t.Errorf("deepest key = %q", last)
+	}
+
+	// 키 수는 maxIndexedValuesKeys에서 멈춥니다.
+	wide := map[string]any{}
+	for i := 0; i < maxIndexedValuesKeys+10; i++ {
+		wide[fmt.Sprintf("k%05d", i)] = i
+	}
+	if got := len(valuesKeys(wide)); got != maxIndexedValuesKeys {
+		t.Errorf("wide values: got %d keys, want %d", got, maxIndexedValuesKeys)
+	}
+}
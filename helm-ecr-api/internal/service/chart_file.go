@@ -0,0 +1,65 @@
+// filepath: helm-ecr-api/internal/service/chart_file.go
+package service
+
+import (
+	"context"
+	"helm-ecr-api/internal/tracing"
+	"io"
+	"io/fs"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ChartFile은 차트 아카이브 안의 파일 하나입니다.
+// Body는 압축 아카이브에서 읽는 동안 압축을 해제하므로 파일 전체를 메모리에 올리지 않습니다.
+type ChartFile struct {
+	Body   io.ReadCloser
+	Name   string      // 아카이브 안의 전체 경로 (예: "my-app/values.yaml")
+	Size   int64       // 압축 해제 후 크기(바이트)
+	Mode   fs.FileMode // tar 헤더에 기록된 파일 권한
+	Digest string      // 파일이 포함된 차트의 매니페스트 다이제스트
+}
+
+// openArchiveFile은 차트 아카이브(.tgz)에서 차트 디렉토리 기준 경로가 fileName인 일반 파일을 찾아 엽니다.
+// 아카이브 항목은 'chart-name/values.yaml' 형태이므로 readChartFiles와 같이 첫 경로 세그먼트를 제거한 뒤 비교합니다.
+// (따라서 "values.yaml"은 하위 차트의 charts/redis/values.yaml과 일치하지 않습니다)
+// 찾는 파일 앞에 있는 항목도 limits에 따라 검증하므로, 위험한 항목이 있는 아카이브는 CodeInvalidArchive 에러를 반환합니다.
+func openArchiveFile(ctx context.Context, archive []byte, fileName string, limits ArchiveLimits) (_ *ChartFile, err error) {
+	_, span := tracer.Start(ctx, "chart.scan_archive", trace.WithAttributes(
+		attribute.Int("chart.archive_bytes", len(archive)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	ar, err := newArchiveReader(archive, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		header, err := ar.Next()
+		if err == io.EOF {
+			break // 파일 끝
+		}
+		if err != nil {
+			ar.Close()
+			return nil, err
+		}
+
+		if _, name, ok := strings.Cut(header.Name, "/"); !ok || name != fileName {
+			continue
+		}
+
+		span.SetAttributes(attribute.Int64("chart.file_bytes", header.Size))
+		return &ChartFile{
+			Body: ar,
+			Name: header.Name,
+			Size: header.Size,
+			Mode: header.FileInfo().Mode().Perm(),
+		}, nil
+	}
+
+	ar.Close()
+	return nil, newError(CodeFileNotFound, nil, "file not found in chart archive: %s", fileName)
+}
@@ -0,0 +1,58 @@
+// filepath: helm-ecr-api/internal/service/chart_file_test.go
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestOpenArchiveFile(t *testing.T) {
+	// 찾는 파일과 이름이 같거나 이름으로 끝나는 파일을 먼저 넣어 첫 번째로 일치하는 항목을 반환하지 않는지 확인합니다.
+	archive := buildArchive(t,
+		regularFile("app/charts/redis/values.yaml", "redis"),
+		regularFile("app/ci/values.yaml", "ci"),
+		regularFile("app/myvalues.yaml", "my"),
+		regularFile("app/values.yaml", "app"),
+		regularFile("app/templates/deployment.yaml", "deployment"),
+		regularFile("README.md", "outside the chart directory"),
+	)
+
+	tests := []struct {
+		fileName string
+		wantName string // 비어 있으면 찾지 못해야 함
+		wantBody string
+	}{
+		{fileName: "values.yaml", wantName: "app/values.yaml", wantBody: "app"},
+		{fileName: "charts/redis/values.yaml", wantName: "app/charts/redis/values.yaml", wantBody: "redis"},
+		{fileName: "templates/deployment.yaml", wantName: "app/templates/deployment.yaml", wantBody: "deployment"},
+		{fileName: "deployment.yaml"},
+		{fileName: "redis/values.yaml"},
+		{fileName: "app/values.yaml"},
+		{fileName: "README.md"},
+		{fileName: "alues.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			f, err := openArchiveFile(context.Background(), archive, tt.fileName, defaultArchiveLimits)
+			if tt.wantName == "" {
+				if ErrorCodeOf(err) != CodeFileNotFound {
+					t.Errorf("openArchiveFile() error = %v, want %s", err, CodeFileNotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("openArchiveFile() error = %v", err)
+			}
+			defer f.Body.Close()
+			body, err := io.ReadAll(f.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.Name != tt.wantName || string(body) != tt.wantBody || f.Size != int64(len(tt.wantBody)) {
+				t.Errorf("openArchiveFile() = %s (%d bytes) %q, want %s %q", f.Name, f.Size, body, tt.wantName, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// filepath: helm-ecr-api/internal/service/chart_images.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"helm-ecr-api/internal/tracing"
+	"path"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+// ChartImages는 차트 버전 하나가 참조하는 컨테이너 이미지 목록(이미지 BOM)입니다.
+type ChartImages struct {
+	Repository string     `json:"repository"`
+	Digest     string     `json:"digest"`
+	Chart      string     `json:"chart"`
+	Version    string     `json:"version"`
+	AppVersion string     `json:"appVersion,omitempty"`
+	Images     []ImageRef `json:"images"`
+	// Signature는 서명 검증을 사용하는 경우 차트 매니페스트의 서명 검증 결과입니다.
+	Signature *SignatureStatus `json:"signature,omitempty"`
+	// Verification은 VerifyChartImages로 ECR에서 이미지를 확인한 경우의 결과 요약입니다.
+	Verification *ImageVerification `json:"verification,omitempty"`
+	// Warnings는 렌더링 실패 등으로 목록이 완전하지 않을 수 있는 이유입니다.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ImageRef는 이미지 참조 하나와 그 참조를 발견한 위치입니다.
+type ImageRef struct {
+	Image string `json:"image"`
+	// Sources는 "templates/deployment.yaml" (렌더링된 매니페스트) 또는 "values.yaml:.image" (values의 image 블록) 형태입니다.
+	Sources []string `json:"sources"`
+	// ECR은 VerifyChartImages로 확인한 결과입니다.
+	ECR *ImageCheck `json:"ecr,omitempty"`
+}
+
+// ListChartImages는 차트를 기본 values(또는 values로 덮어쓴 값)로 렌더링하여 매니페스트의 컨테이너 이미지와
+// values.yaml의 image 블록에서 이미지 참조를 수집합니다.
+// 렌더링에 실패해도(예: required 값 누락) values.yaml에서 찾은 이미지와 함께 Warnings에 이유를 담아 반환합니다.
+func (s *ECRService) ListChartImages(ctx context.Context, repoName, tag, digest string, values map[string]any) (_ *ChartImages, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.ListChartImages", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("chart.tag", tag),
+		attribute.String("chart.digest", digest),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !s.isRepoAllowed(repoName) {
+		return nil, newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	archive, manifestDigest, err := s.fetchChartArchive(ctx, repoName, tag, digest)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := loadChart(ctx, archive, s.archiveLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ChartImages{
+		Repository: repoName,
+		Digest:     manifestDigest,
+		Chart:      ch.Name(),
+		Version:    ch.Metadata.Version,
+		AppVersion: ch.Metadata.AppVersion,
+	}
+	images := imageSet{}
+
+	collectValuesImages(images, ch, "")
+
+	manifests, err := s.renderChart(ctx, ch, values, renderOptions{})
+	if renderAborted(err) {
+		return nil, err
+	}
+	if err != nil {
+		report.Warnings = append(report.Warnings, "chart could not be rendered, only images from values files are listed: "+err.Error())
+	} else {
+		for name, content := range manifests {
+			collectManifestImages(images, name, content)
+		}
+	}
+
+	report.Images = images.sorted()
+	span.SetAttributes(attribute.Int("chart.image_count", len(report.Images)))
+	return report, nil
+}
+
+// imageSet은 이미지 참조별 발견 위치를 모읍니다.
+type imageSet map[string]map[string]struct{}
+
+func (set imageSet) add(image, source string) {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return
+	}
+	if set[image] == nil {
+		set[image] = map[string]struct{}{}
+	}
+	set[image][source] = struct{}{}
+}
+
+// sorted는 이미지 이름순으로 정렬된 목록을 반환합니다.
+func (set imageSet) sorted() []ImageRef {
+	refs := make([]ImageRef, 0, len(set))
+	for image, sources := range set {
+		ref := ImageRef{Image: image, Sources: make([]string, 0, len(sources))}
+		for source := range sources {
+			ref.Sources = append(ref.Sources, source)
+		}
+		sort.Strings(ref.Sources)
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Image < refs[j].Image })
+	return refs
+}
+
+// containerListKeys는 Pod 스펙에서 컨테이너 목록을 담는 필드입니다.
+var containerListKeys = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// collectManifestImages는 렌더링된 템플릿 파일 하나에서 컨테이너 이미지를 수집합니다.
+// Deployment, CronJob 등 리소스 종류와 관계없이 어디에 있든 컨테이너 목록을 찾습니다.
+func collectManifestImages(images imageSet, name, content string) {
+	// NOTES.txt와 헬퍼 템플릿(_helpers.tpl)은 매니페스트가 아닙니다.
+	if path.Ext(name) == ".txt" || strings.HasPrefix(path.Base(name), "_") {
+		return
+	}
+	// 렌더링 결과의 키에서 차트 이름을 제거하여 "templates/deployment.yaml" 형태로 표시합니다.
+	source := name
+	if _, rest, ok := strings.Cut(name, "/"); ok {
+		source = rest
+	}
+
+	for _, doc := range releaseutil.SplitManifests(content) {
+		var manifest any
+		if err := yaml.Unmarshal([]byte(doc), &manifest); err != nil {
+			continue // 매니페스트가 아닌 출력은 건너뜁니다.
+		}
+		walkContainers(manifest, func(image string) { images.add(image, source) })
+	}
+}
+
+func walkContainers(v any, found func(image string)) {
+	switch node := v.(type) {
+	case map[string]any:
+		for _, key := range containerListKeys {
+			containers, _ := node[key].([]any)
+			for _, c := range containers {
+				if container, ok := c.(map[string]any); ok {
+					if image, ok := container["image"].(string); ok {
+						found(image)
+					}
+				}
+			}
+		}
+		for _, child := range node {
+			walkContainers(child, found)
+		}
+	case []any:
+		for _, child := range node {
+			walkContainers(child, found)
+		}
+	}
+}
+
+// collectValuesImages는 차트와 하위 차트의 values.yaml에서 image 블록을 수집합니다.
+// 흔히 사용하는 두 가지 형태를 인식합니다.
+//
+//	image: nginx:1.25
+//	image: {registry: docker.io, repository: nginx, tag: "1.25", digest: sha256:...}
+//
+// tag가 비어 있으면 Helm 차트의 관례(tag | default .Chart.AppVersion)에 따라 appVersion을 사용합니다.
+func collectValuesImages(images imageSet, ch *chart.Chart, prefix string) {
+	source := prefix + "values.yaml"
+	walkValues(ch.Values, "", func(path string, image string) {
+		images.add(image, source+":"+path)
+	}, ch.Metadata.AppVersion)
+
+	for _, dep := range ch.Dependencies() {
+		collectValuesImages(images, dep, prefix+"charts/"+dep.Name()+"/")
+	}
+}
+
+func walkValues(v any, path string, found func(path, image string), appVersion string) {
+	switch node := v.(type) {
+	case map[string]any:
+		for key, child := range node {
+			childPath := path + "." + key
+			if key == "image" || strings.HasSuffix(key, "Image") {
+				if image := imageFromValues(child, appVersion); image != "" {
+					found(childPath, image)
+					continue
+				}
+			}
+			walkValues(child, childPath, found, appVersion)
+		}
+	case chartutil.Values:
+		walkValues(map[string]any(node), path, found, appVersion)
+	case []any:
+		for i, child := range node {
+			walkValues(child, fmt.Sprintf("%s[%d]", path, i), found, appVersion)
+		}
+	}
+}
+
+// imageFromValues는 values의 image 값을 이미지 참조 문자열로 만듭니다. 이미지가 아니면 빈 문자열을 반환합니다.
+func imageFromValues(v any, appVersion string) string {
+	switch image := v.(type) {
+	case string:
+		return image
+	case map[string]any:
+		repository, _ := image["repository"].(string)
+		if repository == "" {
+			return ""
+		}
+		ref := repository
+		if registry, _ := image["registry"].(string); registry != "" {
+			ref = registry + "/" + repository
+		}
+		tag := scalarString(image["tag"])
+		if tag == "" {
+			tag = appVersion
+		}
+		if tag != "" {
+			ref += ":" + tag
+		}
+		if digest, _ := image["digest"].(string); digest != "" {
+			ref += "@" + digest
+		}
+		return ref
+	default:
+		return ""
+	}
+}
+
+// scalarString은 "1.25"처럼 따옴표 없이 쓰여 숫자로 파싱된 tag도 문자열로 변환합니다.
+func scalarString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(s)
+	}
+}
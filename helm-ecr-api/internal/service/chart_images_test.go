@@ -0,0 +1,287 @@
+// filepath: helm-ecr-api/internal/service/chart_images_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// imageSources는 imageSet을 "이미지 → 발견 위치 목록" 형태로 바꿔 비교하기 쉽게 만듭니다.
+func imageSources(images imageSet) map[string][]string {
+	got := map[string][]string{}
+	for _, ref := range images.sorted() {
+		got[ref.Image] = ref.Sources
+	}
+	return got
+}
+
+// assertRenderAborted는 err가 재시도 힌트가 있는 code 에러이고 renderAborted로 분류되는지 확인합니다.
+func assertRenderAborted(t *testing.T, err error, code ErrorCode) {
+	t.Helper()
+	if ErrorCodeOf(err) != code || !renderAborted(err) {
+		t.Fatalf("renderChart() error = %v, want %s", err, code)
+	}
+	if RetryAfterOf(err) <= 0 {
+		t.Errorf("RetryAfterOf(%v) = %s, want a retry hint", err, RetryAfterOf(err))
+	}
+}
+
+func TestCollectValuesImages(t *testing.T) {
+	sub := chartArchive(t, "redis", regularFile("values.yaml", "image:\n  repository: bitnami/redis\n  tag: 7.2.4\n"))
+	archive := buildArchive(t,
+		regularFile("app/Chart.yaml", "apiVersion: v2\nname: app\nversion: 1.0.0\nappVersion: 2.0.0\n"),
+		regularFile("app/values.yaml", `
+image:
+  registry: 123456789012.dkr.ecr.us-east-1.amazonaws.com
+  repository: team/app
+initImage: busybox:1.36
+sidecar:
+  proxyImage:
+    repository: envoyproxy/envoy
+    tag: 1.25
+    digest: sha256:abc
+workers:
+  - name: a
+    image: worker:1.0
+  - name: b
+    image:
+      repository: worker
+      tag: "2.0"
+notAnImage:
+  image:
+    pullPolicy: Always
+  imagePullSecrets: [regcred]
+emptyImage: ""
+`),
+		regularFile("app/charts/redis.tgz", string(sub)),
+	)
+	ch, err := loadChart(context.Background(), archive, defaultArchiveLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images := imageSet{}
+	collectValuesImages(images, ch, "")
+
+	want := map[string][]string{
+		// tag가 없으면 appVersion을 사용합니다.
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com/team/app:2.0.0": {"values.yaml:.image"},
+		"busybox:1.36": {"values.yaml:.initImage"},
+		// 따옴표 없는 숫자 tag도 문자열로 변환합니다.
+		"envoyproxy/envoy:1.25@sha256:abc": {"values.yaml:.sidecar.proxyImage"},
+		"worker:1.0":                       {"values.yaml:.workers[0].image"},
+		"worker:2.0":                       {"values.yaml:.workers[1].image"},
+		// 하위 차트의 values는 하위 차트 경로로 표시합니다.
+		"bitnami/redis:7.2.4": {"charts/redis/values.yaml:.image"},
+	}
+	if got := imageSources(images); !reflect.DeepEqual(got, want) {
+		t.Errorf("collectValuesImages() = %v, want %v", got, want)
+	}
+}
+
+func TestCollectManifestImages(t *testing.T) {
+	manifests := map[string]string{
+		"app/templates/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox:1.36
+      containers:
+        - name: app
+          image: team/app:1.0
+        - name: sidecar
+          image: envoyproxy/envoy:1.30
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+		"app/templates/cronjob.yaml": `
+apiVersion: batch/v1
+kind: CronJob
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: job
+              image: team/app:1.0
+`,
+		"app/charts/redis/templates/statefulset.yaml": `
+kind: StatefulSet
+spec:
+  template:
+    spec:
+      containers:
+        - image: bitnami/redis:7.2.4
+`,
+		// 매니페스트가 아닌 출력과 헬퍼 템플릿은 건너뜁니다.
+		"app/templates/NOTES.txt":    "containers:\n  - image: notes:1.0\n",
+		"app/templates/_helpers.tpl": "containers:\n  - image: helpers:1.0\n",
+		"app/templates/broken.yaml":  "containers: [\n",
+		// 컨테이너 목록이 아닌 곳의 image 필드는 이미지가 아닙니다.
+		"app/templates/configmap.yaml": "kind: ConfigMap\ndata:\n  image: configmap:1.0\n",
+	}
+
+	images := imageSet{}
+	for name, content := range manifests {
+		collectManifestImages(images, name, content)
+	}
+
+	want := map[string][]string{
+		"bitnami/redis:7.2.4":   {"charts/redis/templates/statefulset.yaml"},
+		"busybox:1.36":          {"templates/deployment.yaml"},
+		"envoyproxy/envoy:1.30": {"templates/deployment.yaml"},
+		"team/app:1.0":          {"templates/cronjob.yaml", "templates/deployment.yaml"},
+	}
+	if got := imageSources(images); !reflect.DeepEqual(got, want) {
+		t.Errorf("collectManifestImages() = %v, want %v", got, want)
+	}
+}
+
+// TestRenderChart는 values를 덮어써서 렌더링하고, 하위 차트 템플릿과 Release 값도 렌더링하는지 확인합니다.
+func TestRenderChart(t *testing.T) {
+	sub := chartArchive(t, "redis",
+		regularFile("values.yaml", "tag: 7.2.4\n"),
+		regularFile("templates/statefulset.yaml", "image: redis:{{ .Values.tag }}\n"),
+	)
+	archive := chartArchive(t, "app",
+		regularFile("values.yaml", "image:\n  repository: team/app\n  tag: \"1.0\"\nworker:\n  enabled: false\nredis:\n  tag: 7.0.0\n"),
+		regularFile("templates/deployment.yaml", "image: {{ .Values.image.repository }}:{{ .Values.image.tag }}\nrelease: {{ .Release.Name }}/{{ .Release.Namespace }}\n"),
+		regularFile("templates/worker.yaml", "{{ if .Values.worker.enabled }}worker: true{{ end }}"),
+		regularFile("charts/redis.tgz", string(sub)),
+	)
+
+	s, err := NewECRService(aws.Config{Region: "us-east-1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   map[string]string
+	}{
+		{
+			name: "default values",
+			want: map[string]string{
+				"app/templates/deployment.yaml":               "image: team/app:1.0\nrelease: " + renderReleaseName + "/" + renderNamespace + "\n",
+				"app/templates/worker.yaml":                   "",
+				"app/charts/redis/templates/statefulset.yaml": "image: redis:7.0.0\n",
+			},
+		},
+		{
+			name:   "overridden values",
+			values: map[string]any{"image": map[string]any{"tag": "2.0"}, "worker": map[string]any{"enabled": true}, "redis": map[string]any{"tag": "7.4"}},
+			want: map[string]string{
+				"app/templates/deployment.yaml":               "image: team/app:2.0\nrelease: " + renderReleaseName + "/" + renderNamespace + "\n",
+				"app/templates/worker.yaml":                   "worker: true",
+				"app/charts/redis/templates/statefulset.yaml": "image: redis:7.4\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 렌더링은 차트의 의존성 값을 바꾸므로 요청마다 차트를 새로 읽습니다.
+			ch, err := loadChart(context.Background(), archive, defaultArchiveLimits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.renderChart(context.Background(), ch, tt.values, renderOptions{})
+			if err != nil {
+				t.Fatalf("renderChart() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderChart() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("template error", func(t *testing.T) {
+		ch, err := loadChart(context.Background(), chartArchive(t, "app",
+			regularFile("templates/deployment.yaml", `{{ required "image.tag is required" .Values.image.tag }}`)), defaultArchiveLimits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = s.renderChart(context.Background(), ch, nil, renderOptions{})
+		if err == nil || renderAborted(err) {
+			t.Errorf("renderChart() error = %v, want a template error", err)
+		}
+	})
+}
+
+// TestRenderChartLimits는 렌더링 시간 초과와 동시 렌더링 제한이 재시도 힌트가 있는 에러를 반환하는지 확인합니다.
+func TestRenderChartLimits(t *testing.T) {
+	// 렌더링에 시간이 걸리도록 반복하는 템플릿
+	slow := chartArchive(t, "app", regularFile("templates/loop.yaml", `{{ range until 200000 }}{{ . }}{{ end }}`))
+	load := func(t *testing.T) *chart.Chart {
+		t.Helper()
+		ch, err := loadChart(context.Background(), slow, defaultArchiveLimits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ch
+	}
+
+	t.Run("render timeout", func(t *testing.T) {
+		s, err := NewECRService(aws.Config{Region: "us-east-1"}, nil, WithRenderLimits(time.Nanosecond, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = s.renderChart(context.Background(), load(t), nil, renderOptions{})
+		assertRenderAborted(t, err, CodeRenderTimeout)
+	})
+
+	t.Run("request deadline", func(t *testing.T) {
+		s, err := NewECRService(aws.Config{Region: "us-east-1"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		_, err = s.renderChart(ctx, load(t), nil, renderOptions{})
+		assertRenderAborted(t, err, CodeRenderTimeout)
+	})
+
+	t.Run("request canceled", func(t *testing.T) {
+		s, err := NewECRService(aws.Config{Region: "us-east-1"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = s.renderChart(ctx, load(t), nil, renderOptions{})
+		if !errors.Is(err, context.Canceled) || !renderAborted(err) {
+			t.Errorf("renderChart() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("too many concurrent renders", func(t *testing.T) {
+		s, err := NewECRService(aws.Config{Region: "us-east-1"}, nil, WithRenderLimits(0, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 다른 렌더링이 슬롯을 차지하고 있는 상태
+		s.renders <- struct{}{}
+		_, err = s.renderChart(context.Background(), load(t), nil, renderOptions{})
+		assertRenderAborted(t, err, CodeRenderBusy)
+
+		<-s.renders
+		if _, err := s.renderChart(context.Background(), load(t), nil, renderOptions{}); err != nil {
+			t.Errorf("renderChart() after the slot is released: error = %v", err)
+		}
+	})
+}
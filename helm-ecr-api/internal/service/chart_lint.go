@@ -0,0 +1,344 @@
+// filepath: helm-ecr-api/internal/service/chart_lint.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"helm-ecr-api/internal/tracing"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+// 린트 결과의 심각도
+const (
+	SeverityError   = "error"   // 차트를 설치할 수 없거나 곧 설치할 수 없게 되는 문제
+	SeverityWarning = "warning" // 설치는 되지만 수정이 필요한 문제
+	SeverityInfo    = "info"    // 권장 사항
+)
+
+// LintReport는 차트 버전 하나의 린트 결과입니다.
+type LintReport struct {
+	Repository string `json:"repository"`
+	Digest     string `json:"digest"`
+	Chart      string `json:"chart,omitempty"`
+	Version    string `json:"version,omitempty"`
+	// KubeVersion은 API 제공 여부와 kubeVersion 제약 조건을 확인한 대상 클러스터 버전입니다. (지정하지 않으면 생략)
+	KubeVersion string        `json:"kubeVersion,omitempty"`
+	Passed      bool          `json:"passed"` // error 심각도의 결과가 없으면 true
+	Summary     LintSummary   `json:"summary"`
+	Findings    []LintFinding `json:"findings"`
+}
+
+// LintSummary는 심각도별 결과 수입니다.
+type LintSummary struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Info     int `json:"info"`
+}
+
+// LintFinding은 린트 검사 결과 하나입니다.
+type LintFinding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`           // 검사 항목 (예: "chart-yaml", "template", "deprecated-api", "removed-api")
+	Path     string `json:"path,omitempty"` // 문제가 있는 파일 (예: "Chart.yaml", "templates/ingress.yaml")
+	Message  string `json:"message"`
+}
+
+// lintResult는 린트 결과를 모읍니다.
+type lintResult struct {
+	findings []LintFinding
+}
+
+func (l *lintResult) add(severity, rule, path, format string, args ...any) {
+	l.findings = append(l.findings, LintFinding{Severity: severity, Rule: rule, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// LintChart는 차트 버전의 구조를 검사합니다.
+// Chart.yaml 필수 항목과 형식, 템플릿 렌더링, values.schema.json, NOTES.txt, 렌더링된 매니페스트의 지원 중단 API를 확인합니다.
+// 차트를 Helm 차트로 읽을 수 없어도 에러를 반환하지 않고 결과(findings)로 알립니다.
+//
+// kubeVersion(예: "1.30")을 지정하면 해당 버전의 클러스터를 대상으로 렌더링하여, 그 버전에서 제거된 API는 error,
+// 지원 중단된 API는 warning으로 알리고 Chart.yaml의 kubeVersion 제약 조건을 만족하는지도 확인합니다.
+func (s *ECRService) LintChart(ctx context.Context, repoName, tag, digest, kubeVersion string) (_ *LintReport, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.LintChart", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("chart.tag", tag),
+		attribute.String("chart.digest", digest),
+		attribute.String("lint.kube_version", kubeVersion),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !s.isRepoAllowed(repoName) {
+		return nil, newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+	var kv *chartutil.KubeVersion
+	if kubeVersion != "" {
+		if kv, err = parseKubeVersion(kubeVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	archive, manifestDigest, err := s.fetchChartArchive(ctx, repoName, tag, digest)
+	if err != nil {
+		return nil, err
+	}
+	// 아카이브 자체가 손상되었거나 위험한 항목을 포함하면 린트 대상이 아니므로 에러를 반환합니다.
+	files, err := readChartFiles(archive, s.archiveLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LintReport{Repository: repoName, Digest: manifestDigest}
+	if kv != nil {
+		report.KubeVersion = kv.String()
+	}
+	lint := &lintResult{}
+	if err := s.lintChart(ctx, lint, files, kv, report); err != nil {
+		return nil, err
+	}
+
+	// 심각도, 파일, 검사 항목 순으로 정렬하여 결과가 항상 같은 순서가 되도록 합니다.
+	severityOrder := map[string]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+	sort.SliceStable(lint.findings, func(i, j int) bool {
+		a, b := lint.findings[i], lint.findings[j]
+		if a.Severity != b.Severity {
+			return severityOrder[a.Severity] < severityOrder[b.Severity]
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Rule < b.Rule
+	})
+	report.Findings = lint.findings
+	if report.Findings == nil {
+		report.Findings = []LintFinding{}
+	}
+	for _, f := range report.Findings {
+		switch f.Severity {
+		case SeverityError:
+			report.Summary.Errors++
+		case SeverityWarning:
+			report.Summary.Warnings++
+		default:
+			report.Summary.Info++
+		}
+	}
+	report.Passed = report.Summary.Errors == 0
+
+	span.SetAttributes(
+		attribute.Bool("lint.passed", report.Passed),
+		attribute.Int("lint.errors", report.Summary.Errors),
+		attribute.Int("lint.warnings", report.Summary.Warnings),
+	)
+	return report, nil
+}
+
+// lintChart는 차트 파일에 대해 모든 검사를 실행합니다. kv가 nil이면 특정 클러스터 버전을 대상으로 하지 않습니다.
+// 검사 결과는 lint에 담으며, 렌더링을 마치지 못해(시간 초과 등) 검사를 끝낼 수 없을 때만 에러를 반환합니다.
+func (s *ECRService) lintChart(ctx context.Context, lint *lintResult, files []*loader.BufferedFile, kv *chartutil.KubeVersion, report *LintReport) error {
+	fileData := make(map[string][]byte, len(files))
+	for _, f := range files {
+		fileData[f.Name] = f.Data
+	}
+
+	// 1. Chart.yaml은 Helm loader보다 먼저 직접 검사합니다. loader는 첫 번째 문제에서 바로 실패하기 때문입니다.
+	if !lintChartYAML(lint, fileData["Chart.yaml"], report) {
+		return nil
+	}
+
+	// 2. Helm 차트로 읽습니다. 의존성 선언 오류 등 Chart.yaml 외의 구조 문제도 여기서 발견됩니다.
+	ch, err := loader.LoadFiles(files)
+	if err != nil {
+		lint.add(SeverityError, "chart-load", "", "chart could not be loaded: %v", err)
+		return nil
+	}
+
+	// 대상 클러스터 버전이 차트의 kubeVersion 제약 조건을 만족하는지 확인합니다.
+	// helm install은 여기서 실패하지만, 다른 문제도 함께 알 수 있도록 렌더링은 제약 조건 없이 계속합니다.
+	// 대상 버전이 없으면 Helm 기본 버전과 비교하게 되어 의미가 없으므로 항상 제약 조건을 지우고 렌더링합니다.
+	if kv != nil && ch.Metadata.KubeVersion != "" && !chartutil.IsCompatibleRange(ch.Metadata.KubeVersion, kv.String()) {
+		lint.add(SeverityError, "kube-version", "Chart.yaml", "chart requires kubeVersion %q, which is incompatible with Kubernetes %s", ch.Metadata.KubeVersion, kv.String())
+	}
+	ch.Metadata.KubeVersion = ""
+
+	if _, ok := fileData["values.yaml"]; !ok {
+		lint.add(SeverityInfo, "values", "values.yaml", "values.yaml does not exist")
+	}
+
+	// 3. values.schema.json이 올바른 JSON이고, 기본 values가 스키마를 만족하는지 확인합니다.
+	if ch.Schema != nil {
+		if !json.Valid(ch.Schema) {
+			lint.add(SeverityError, "values-schema", "values.schema.json", "values.schema.json is not valid JSON")
+		} else if values, err := chartutil.CoalesceValues(ch, map[string]any{}); err != nil {
+			lint.add(SeverityError, "values", "values.yaml", "values could not be merged: %v", err)
+		} else if err := chartutil.ValidateAgainstSchema(ch, values); err != nil {
+			lint.add(SeverityError, "values-schema", "values.schema.json", "default values do not match the schema: %s", strings.TrimSpace(err.Error()))
+		}
+	}
+
+	// 라이브러리 차트는 단독으로 렌더링하거나 설치할 수 없으므로 템플릿 관련 검사를 하지 않습니다.
+	if ch.Metadata.Type == "library" {
+		return nil
+	}
+
+	if _, ok := fileData["templates/NOTES.txt"]; !ok {
+		lint.add(SeverityInfo, "notes", "templates/NOTES.txt", "templates/NOTES.txt does not exist, users get no usage notes after install")
+	}
+
+	// 4. 템플릿을 렌더링합니다. 필수 값이 없어도 나머지 템플릿을 검사할 수 있도록 lint 모드를 사용하고,
+	// 스키마 검증은 위에서 따로 보고했으므로 생략합니다.
+	opts := renderOptions{LintMode: true, SkipSchemaValidation: true}
+	var target *semver.Version
+	if kv != nil {
+		opts.Capabilities = kubeCapabilities(kv)
+		target = targetVersion(kv)
+	}
+	manifests, err := s.renderChart(ctx, ch, nil, opts)
+	if renderAborted(err) {
+		return err
+	}
+	if err != nil {
+		lint.add(SeverityError, "template", templatePathOf(err.Error(), ch.Name()), "templates could not be rendered: %v", err)
+		return nil
+	}
+
+	// 5. 렌더링된 매니페스트가 올바른 YAML인지, 지원 중단된 API를 사용하는지 확인합니다.
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		source := strings.TrimPrefix(name, ch.Name()+"/")
+		if path.Ext(source) == ".txt" || strings.HasPrefix(path.Base(source), "_") {
+			continue
+		}
+		for _, doc := range releaseutil.SplitManifests(manifests[name]) {
+			var meta struct {
+				APIVersion string `json:"apiVersion"`
+				Kind       string `json:"kind"`
+			}
+			if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+				lint.add(SeverityError, "manifest-yaml", source, "rendered manifest is not valid YAML: %v", err)
+				continue
+			}
+			if meta.APIVersion == "" || meta.Kind == "" {
+				lint.add(SeverityError, "manifest-yaml", source, "rendered manifest has no apiVersion or kind")
+				continue
+			}
+			if api, ok := findDeprecatedAPI(meta.APIVersion, meta.Kind); ok {
+				lintDeprecatedAPI(lint, source, api, target)
+			}
+		}
+	}
+	return nil
+}
+
+// lintChartYAML은 Chart.yaml의 필수 항목과 형식을 검사합니다. 이후 검사를 계속할 수 없으면 false를 반환합니다.
+func lintChartYAML(lint *lintResult, data []byte, report *LintReport) bool {
+	const file = "Chart.yaml"
+	if data == nil {
+		lint.add(SeverityError, "chart-yaml", file, "Chart.yaml does not exist")
+		return false
+	}
+	var md chart.Metadata
+	if err := yaml.Unmarshal(data, &md); err != nil {
+		lint.add(SeverityError, "chart-yaml", file, "Chart.yaml is not valid YAML: %v", err)
+		return false
+	}
+	report.Chart = md.Name
+	report.Version = md.Version
+
+	ok := true
+	switch md.APIVersion {
+	case "":
+		lint.add(SeverityError, "chart-yaml", file, "apiVersion is required")
+		ok = false
+	case chart.APIVersionV2:
+	case chart.APIVersionV1:
+		lint.add(SeverityWarning, "chart-yaml", file, "apiVersion v1 is deprecated, use v2 (Helm 3)")
+	default:
+		lint.add(SeverityError, "chart-yaml", file, "apiVersion %q is not supported, must be v2", md.APIVersion)
+		ok = false
+	}
+	if md.Name == "" {
+		lint.add(SeverityError, "chart-yaml", file, "name is required")
+		ok = false
+	} else if md.Name != path.Base(md.Name) {
+		lint.add(SeverityError, "chart-yaml", file, "name %q must not contain path separators", md.Name)
+		ok = false
+	}
+	if md.Version == "" {
+		lint.add(SeverityError, "chart-yaml", file, "version is required")
+		ok = false
+	} else if _, err := semver.StrictNewVersion(md.Version); err != nil {
+		// Helm은 "v1.2"와 같은 느슨한 형식도 허용하지만, OCI 태그와 의존성 해석이 일관되도록 엄격한 semver를 권장합니다.
+		if _, err := semver.NewVersion(md.Version); err != nil {
+			lint.add(SeverityError, "chart-yaml", file, "version %q is not a valid semantic version", md.Version)
+			ok = false
+		} else {
+			lint.add(SeverityWarning, "chart-yaml", file, "version %q is not a strict semantic version (MAJOR.MINOR.PATCH)", md.Version)
+		}
+	}
+	if md.Type != "" && md.Type != "application" && md.Type != "library" {
+		lint.add(SeverityError, "chart-yaml", file, "type must be application or library: %q", md.Type)
+		ok = false
+	}
+	if md.KubeVersion != "" {
+		if _, err := semver.NewConstraint(md.KubeVersion); err != nil {
+			lint.add(SeverityError, "chart-yaml", file, "kubeVersion %q is not a valid version constraint", md.KubeVersion)
+			ok = false
+		}
+	}
+	if md.Description == "" {
+		lint.add(SeverityInfo, "chart-yaml", file, "description is recommended")
+	}
+	if md.Icon == "" {
+		lint.add(SeverityInfo, "chart-yaml", file, "icon is recommended")
+	}
+	return ok
+}
+
+// lintDeprecatedAPI는 지원 중단 API 사용을 알립니다.
+// 대상 버전(target)이 없으면 warning으로, 있으면 그 버전에서 제거되었으면 error, 지원 중단 상태이면 warning,
+// 아직 지원 중단 전이면 이후 업그레이드를 위해 info로 알립니다.
+func lintDeprecatedAPI(lint *lintResult, source string, api deprecatedAPI, target *semver.Version) {
+	replacement := ""
+	if api.Replacement != "" {
+		replacement = ", use " + api.Replacement
+	}
+	switch {
+	case target == nil:
+		lint.add(SeverityWarning, "deprecated-api", source, "%s %s is deprecated since Kubernetes v%s and removed in v%s%s", api.APIVersion, api.Kind, api.DeprecatedIn, api.RemovedIn, replacement)
+	case api.removedIn(target):
+		lint.add(SeverityError, "removed-api", source, "%s %s was removed in Kubernetes v%s and is not served by v%s%s", api.APIVersion, api.Kind, api.RemovedIn, target.Original(), replacement)
+	case api.deprecatedIn(target):
+		lint.add(SeverityWarning, "deprecated-api", source, "%s %s is deprecated in Kubernetes v%s and will be removed in v%s%s", api.APIVersion, api.Kind, target.Original(), api.RemovedIn, replacement)
+	default:
+		lint.add(SeverityInfo, "deprecated-api", source, "%s %s will be deprecated in Kubernetes v%s and removed in v%s%s", api.APIVersion, api.Kind, api.DeprecatedIn, api.RemovedIn, replacement)
+	}
+}
+
+// templatePathOf는 Helm 렌더링 에러 메시지(예: "template: app/templates/x.yaml:3:5: ...")에서 템플릿 경로를 찾습니다.
+func templatePathOf(msg, chartName string) string {
+	_, rest, ok := strings.Cut(msg, "template: "+chartName+"/")
+	if !ok {
+		return ""
+	}
+	file, _, _ := strings.Cut(rest, ":")
+	return file
+}
@@ -0,0 +1,250 @@
+// filepath: helm-ecr-api/internal/service/chart_loader.go
+package service
+
+import (
+	"context"
+	"errors"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/tracing"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+// maxSubchartDepth는 하위 차트 아카이브(charts/*.tgz) 안의 하위 차트 아카이브를 펼치는 최대 중첩 깊이입니다.
+const maxSubchartDepth = 10
+
+// loadChart는 차트 아카이브(.tgz)를 Helm 차트 구조로 읽습니다.
+// Helm의 loader.LoadArchive 대신 archiveReader로 항목을 읽어, 다른 API와 같은 제한과 경로 검증을 적용합니다.
+func loadChart(ctx context.Context, archive []byte, limits ArchiveLimits) (_ *chart.Chart, err error) {
+	_, span := tracer.Start(ctx, "chart.load", trace.WithAttributes(
+		attribute.Int("chart.archive_bytes", len(archive)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	files, err := readChartFiles(archive, limits)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := loader.LoadFiles(files)
+	if err != nil {
+		return nil, newError(CodeInvalidArchive, err, "chart archive is not a valid Helm chart: %v", err)
+	}
+	span.SetAttributes(
+		attribute.String("chart.name", ch.Name()),
+		attribute.String("chart.version", ch.Metadata.Version),
+	)
+	return ch, nil
+}
+
+// readChartFiles는 차트 아카이브의 모든 파일을 차트 디렉토리 기준 경로(예: "templates/deployment.yaml")로 읽습니다.
+//
+// 하위 차트 아카이브(charts/<이름>.tgz)는 Helm loader가 자체 제한으로 해제하지 않도록 같은 제한과 사용량으로 펼쳐서
+// charts/<이름>/ 디렉토리의 파일로 반환합니다. 따라서 중첩된 아카이브를 포함한 전체 압축 해제 크기와 항목 수가
+// ArchiveLimits 하나로 제한됩니다.
+func readChartFiles(archive []byte, limits ArchiveLimits) ([]*loader.BufferedFile, error) {
+	ar, err := newArchiveReader(archive, limits)
+	if err != nil {
+		return nil, err
+	}
+	return readArchiveFiles(ar, 0)
+}
+
+// readArchiveFiles는 ar의 파일을 읽고 ar을 닫습니다. depth는 하위 차트 아카이브의 중첩 깊이입니다.
+func readArchiveFiles(ar *archiveReader, depth int) ([]*loader.BufferedFile, error) {
+	defer ar.Close()
+
+	var files, subcharts []*loader.BufferedFile // 아카이브의 파일, 펼친 하위 차트 아카이브의 파일
+	expanded := make(map[string]struct{})       // 펼친 하위 차트 디렉토리
+	for {
+		header, err := ar.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// 차트 아카이브의 모든 항목은 "<차트 이름>/" 디렉토리 아래에 있으므로 첫 경로 세그먼트를 제거합니다.
+		_, name, ok := strings.Cut(header.Name, "/")
+		if !ok || name == "" {
+			continue
+		}
+		data, err := io.ReadAll(ar)
+		if err != nil {
+			return nil, err
+		}
+
+		dir, ok := subchartArchiveDir(name)
+		if !ok {
+			files = append(files, &loader.BufferedFile{Name: name, Data: data})
+			continue
+		}
+		if depth >= maxSubchartDepth {
+			return nil, newError(CodeInvalidArchive, nil, "chart archive nests subchart archives more than %d levels deep", maxSubchartDepth)
+		}
+		if _, dup := expanded[dir]; dup {
+			return nil, newError(CodeInvalidArchive, nil, "chart archive contains subchart %s more than once", name)
+		}
+		expanded[dir] = struct{}{}
+		nested, err := ar.nested(data)
+		if err != nil {
+			return nil, err
+		}
+		subFiles, err := readArchiveFiles(nested, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range subFiles {
+			f.Name = dir + "/" + f.Name
+			subcharts = append(subcharts, f)
+		}
+	}
+
+	// 펼친 하위 차트와 같은 이름의 디렉토리가 아카이브에 있으면 두 차트의 파일이 섞이므로 거절합니다.
+	for _, f := range files {
+		for dir := range expanded {
+			if strings.HasPrefix(f.Name, dir+"/") {
+				return nil, newError(CodeInvalidArchive, nil, "chart archive contains subchart %s both as an archive and a directory", dir)
+			}
+		}
+	}
+	return append(files, subcharts...), nil
+}
+
+// subchartArchiveDir은 name이 Helm이 하위 차트로 읽는 아카이브(charts/<이름>.tgz 또는 하위 차트 디렉토리 안의 charts/<이름>.tgz)이면
+// 펼칠 디렉토리(charts/<이름>)를 반환합니다. Helm이 무시하는 '_' 또는 '.'으로 시작하는 이름은 펼치지 않습니다.
+func subchartArchiveDir(name string) (string, bool) {
+	prefix := ""
+	rest := name
+	for {
+		sub, ok := strings.CutPrefix(rest, "charts/")
+		if !ok {
+			return "", false
+		}
+		chartName, after, nested := strings.Cut(sub, "/")
+		if !nested {
+			if path.Ext(chartName) != ".tgz" || strings.IndexAny(chartName, "_.") == 0 {
+				return "", false
+			}
+			return prefix + "charts/" + strings.TrimSuffix(chartName, ".tgz"), true
+		}
+		prefix += "charts/" + chartName + "/"
+		rest = after
+	}
+}
+
+// 렌더링에 사용할 릴리스 정보. 매니페스트 분석에는 실제 릴리스가 필요 없으므로 고정 값을 사용합니다.
+const (
+	renderReleaseName = "release-name"
+	renderNamespace   = "default"
+)
+
+// renderOptions는 차트 렌더링 방식을 지정합니다.
+type renderOptions struct {
+	// LintMode이면 required, fail 등 값이 없어서 실패하는 템플릿 함수를 에러로 처리하지 않습니다.
+	LintMode bool
+	// SkipSchemaValidation이면 values.schema.json으로 values를 검증하지 않습니다.
+	SkipSchemaValidation bool
+	// Capabilities는 템플릿의 .Capabilities 값입니다. nil이면 Helm 기본값을 사용합니다.
+	Capabilities *chartutil.Capabilities
+}
+
+// renderChart는 Helm 템플릿 엔진으로 차트를 렌더링합니다. 클러스터에 접속하지 않으므로 lookup 함수는 빈 값을 반환합니다.
+// 반환 값의 키는 "<차트 이름>/templates/<파일>" 형태입니다.
+//
+// 레지스트리의 차트는 신뢰하지 않으므로 재귀 템플릿이나 큰 until 반복으로 CPU와 메모리를 계속 사용할 수 있습니다.
+// Helm 템플릿 엔진은 컨텍스트로 중단할 수 없으므로 렌더링을 고루틴에서 실행하고 renderTimeout이 지나면
+// CodeRenderTimeout 에러를 반환합니다. 시간 초과는 차트보다 서버 부하 때문일 수 있으므로 동시 렌더링 제한과 같이 재시도 힌트를 줍니다. 중단되지 않은 렌더링은 끝날 때까지 동시 렌더링 슬롯을 차지하므로,
+// 오래 걸리는 차트가 반복해서 요청되어도 동시에 실행되는 렌더링은 maxConcurrentRenders개를 넘지 않습니다.
+func (s *ECRService) renderChart(ctx context.Context, ch *chart.Chart, values map[string]any, opts renderOptions) (_ map[string]string, err error) {
+	_, span := tracer.Start(ctx, "chart.render")
+	defer func() { tracing.End(span, err) }()
+
+	if s.renders != nil {
+		select {
+		case s.renders <- struct{}{}:
+		default:
+			metrics.IncRateLimited("renders")
+			e := newError(CodeRenderBusy, nil, "too many concurrent chart renders, retry later")
+			e.RetryAfter = renderRetryAfter
+			return nil, e
+		}
+	}
+
+	type result struct {
+		manifests map[string]string
+		err       error
+	}
+	done := make(chan result, 1)
+	go func() {
+		if s.renders != nil {
+			defer func() { <-s.renders }()
+		}
+		manifests, err := render(ch, values, opts)
+		done <- result{manifests, err}
+	}()
+
+	var timeout <-chan time.Time
+	if s.renderTimeout > 0 {
+		timer := time.NewTimer(s.renderTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case res := <-done:
+		return res.manifests, res.err
+	case <-timeout:
+		e := newError(CodeRenderTimeout, nil, "rendering chart %s did not finish within %s", ch.Name(), s.renderTimeout)
+		e.RetryAfter = renderRetryAfter
+		return nil, e
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			e := newError(CodeRenderTimeout, ctx.Err(), "rendering chart %s did not finish before the request timeout", ch.Name())
+			e.RetryAfter = renderRetryAfter
+			return nil, e
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// renderAborted는 renderChart가 차트 때문이 아니라 시간 초과, 동시 렌더링 제한, 요청 취소로 렌더링을 마치지 못했는지 확인합니다.
+// 이 경우 호출자는 렌더링 에러를 결과에 담지 않고 그대로 반환해야 합니다.
+func renderAborted(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code == CodeRenderTimeout || e.Code == CodeRenderBusy
+	}
+	return errors.Is(err, context.Canceled)
+}
+
+// render는 values를 병합하여 차트 템플릿을 렌더링합니다. ch의 의존성 값을 변경하므로 요청마다 새로 읽은 차트를 사용해야 합니다.
+func render(ch *chart.Chart, values map[string]any, opts renderOptions) (map[string]string, error) {
+	if values == nil {
+		values = map[string]any{}
+	}
+	caps := opts.Capabilities
+	if caps == nil {
+		caps = chartutil.DefaultCapabilities
+	}
+	if err := chartutil.ProcessDependenciesWithMerge(ch, values); err != nil {
+		return nil, err
+	}
+	renderValues, err := chartutil.ToRenderValuesWithSchemaValidation(ch, values, chartutil.ReleaseOptions{
+		Name:      renderReleaseName,
+		Namespace: renderNamespace,
+		IsInstall: true,
+	}, caps, opts.SkipSchemaValidation)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Engine{LintMode: opts.LintMode}.Render(ch, renderValues)
+}
@@ -0,0 +1,210 @@
+// filepath: helm-ecr-api/internal/service/chart_loader_test.go
+package service
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// chartYAML은 이름이 name인 최소 Chart.yaml입니다.
+func chartYAML(name string) string {
+	return fmt.Sprintf("apiVersion: v2\nname: %s\nversion: 1.0.0\n", name)
+}
+
+// chartArchive는 "<name>/" 디렉토리 아래에 Chart.yaml과 extra 항목을 넣은 차트 아카이브를 만듭니다.
+// extra 항목의 이름은 차트 디렉토리 기준 경로입니다.
+func chartArchive(t *testing.T, name string, extra ...testEntry) []byte {
+	t.Helper()
+	entries := []testEntry{regularFile(name+"/Chart.yaml", chartYAML(name))}
+	for _, e := range extra {
+		e.header.Name = name + "/" + e.header.Name
+		entries = append(entries, e)
+	}
+	return buildArchive(t, entries...)
+}
+
+func TestSubchartArchiveDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantDir string
+		wantOK  bool
+	}{
+		{name: "charts/redis.tgz", wantDir: "charts/redis", wantOK: true},
+		{name: "charts/redis-1.2.3.tgz", wantDir: "charts/redis-1.2.3", wantOK: true},
+		{name: "charts/app/charts/redis.tgz", wantDir: "charts/app/charts/redis", wantOK: true},
+		{name: "charts/redis.tgz.prov"},
+		{name: "charts/redis/Chart.yaml"},
+		{name: "charts/_redis.tgz"},
+		{name: "charts/.redis.tgz"},
+		{name: "templates/charts/redis.tgz"},
+		{name: "files/redis.tgz"},
+		{name: "redis.tgz"},
+	}
+	for _, tt := range tests {
+		dir, ok := subchartArchiveDir(tt.name)
+		if dir != tt.wantDir || ok != tt.wantOK {
+			t.Errorf("subchartArchiveDir(%q) = %q, %v, want %q, %v", tt.name, dir, ok, tt.wantDir, tt.wantOK)
+		}
+	}
+}
+
+// TestLoadChartSubcharts는 하위 차트 아카이브(중첩 포함)를 펼쳐서 Helm 차트 구조로 읽는지 확인합니다.
+func TestLoadChartSubcharts(t *testing.T) {
+	leaf := chartArchive(t, "leaf", regularFile("templates/cm.yaml", "kind: ConfigMap"))
+	mid := chartArchive(t, "mid", regularFile("charts/leaf-1.0.0.tgz", string(leaf)))
+	archive := chartArchive(t, "app",
+		regularFile("charts/mid-1.0.0.tgz", string(mid)),
+		regularFile("charts/plain/Chart.yaml", chartYAML("plain")),
+		regularFile("charts/plain/charts/leaf.tgz", string(leaf)),
+	)
+
+	files, err := readChartFiles(archive, defaultArchiveLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"Chart.yaml",
+		"charts/mid-1.0.0/Chart.yaml",
+		"charts/mid-1.0.0/charts/leaf-1.0.0/Chart.yaml",
+		"charts/mid-1.0.0/charts/leaf-1.0.0/templates/cm.yaml",
+		"charts/plain/Chart.yaml",
+		"charts/plain/charts/leaf/Chart.yaml",
+		"charts/plain/charts/leaf/templates/cm.yaml",
+	}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Errorf("files = %q, want %q", names, want)
+	}
+
+	ch, err := loadChart(context.Background(), archive, defaultArchiveLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := map[string]int{}
+	for _, dep := range ch.Dependencies() {
+		deps[dep.Name()] = len(dep.Dependencies())
+	}
+	if len(deps) != 2 || deps["mid"] != 1 || deps["plain"] != 1 {
+		t.Errorf("dependencies = %v, want mid and plain with one subchart each", deps)
+	}
+}
+
+// TestLoadChartMaliciousSubchart는 하위 차트 아카이브에도 같은 제한과 경로 검증을 적용하고,
+// 제한이 상위 아카이브와 공유되어 중첩으로 제한을 여러 번 받을 수 없는지 확인합니다.
+func TestLoadChartMaliciousSubchart(t *testing.T) {
+	limits := ArchiveLimits{MaxUncompressedSize: 64 << 10, MaxEntries: 20, MaxPathLength: 256}
+
+	// 압축률이 높은 큰 파일. 압축된 하위 차트는 작아서 상위 아카이브의 제한에 걸리지 않습니다.
+	bomb := chartArchive(t, "bomb", regularFile("files/zeros", strings.Repeat("\x00", 1<<20)))
+	// 각각은 제한의 절반을 조금 넘으므로 하나씩은 통과하지만 합치면 제한을 넘습니다.
+	half := chartArchive(t, "half", regularFile("files/data", strings.Repeat("a", 36<<10)))
+	many := make([]testEntry, 16)
+	for i := range many {
+		many[i] = regularFile(fmt.Sprintf("files/f%d", i), "x")
+	}
+
+	tests := []struct {
+		name    string
+		archive []byte
+		wantErr string
+	}{
+		{
+			name:    "decompression bomb in a subchart",
+			archive: chartArchive(t, "app", regularFile("charts/bomb.tgz", string(bomb))),
+			wantErr: "uncompressed size limit",
+		},
+		{
+			name: "subcharts share the uncompressed size budget",
+			archive: chartArchive(t, "app",
+				regularFile("charts/a.tgz", string(half)),
+				regularFile("charts/b.tgz", string(half)),
+			),
+			wantErr: "uncompressed size limit",
+		},
+		{
+			name: "subcharts share the entry budget",
+			archive: chartArchive(t, "app",
+				regularFile("charts/a.tgz", string(chartArchive(t, "a", many[:8]...))),
+				regularFile("charts/b.tgz", string(chartArchive(t, "b", many[8:]...))),
+			),
+			wantErr: "more than 20 entries",
+		},
+		{
+			name: "doubly nested bomb",
+			archive: chartArchive(t, "app", regularFile("charts/mid.tgz",
+				string(chartArchive(t, "mid", regularFile("charts/bomb.tgz", string(bomb)))))),
+			wantErr: "uncompressed size limit",
+		},
+		{
+			name: "bomb under a subchart directory",
+			archive: chartArchive(t, "app",
+				regularFile("charts/dir/Chart.yaml", chartYAML("dir")),
+				regularFile("charts/dir/charts/bomb.tgz", string(bomb)),
+			),
+			wantErr: "uncompressed size limit",
+		},
+		{
+			name: "path traversal in a subchart",
+			archive: chartArchive(t, "app", regularFile("charts/evil.tgz",
+				string(buildArchive(t, regularFile("evil/../../etc/passwd", "x"))))),
+			wantErr: "outside the chart",
+		},
+		{
+			name: "symlink in a subchart",
+			archive: chartArchive(t, "app", regularFile("charts/evil.tgz", string(buildArchive(t,
+				regularFile("evil/Chart.yaml", chartYAML("evil")),
+				testEntry{header: tar.Header{Name: "evil/values.yaml", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+			)))),
+			wantErr: "unsupported entry type",
+		},
+		{
+			name:    "subchart that is not a gzip stream",
+			archive: chartArchive(t, "app", regularFile("charts/broken.tgz", "not a gzip stream")),
+			wantErr: "not a valid gzip stream",
+		},
+		{
+			name: "subchart as an archive and a directory",
+			archive: chartArchive(t, "app",
+				regularFile("charts/dup.tgz", string(chartArchive(t, "dup"))),
+				regularFile("charts/dup/Chart.yaml", chartYAML("dup")),
+			),
+			wantErr: "both as an archive and a directory",
+		},
+		{
+			name: "duplicate subchart archive",
+			archive: chartArchive(t, "app",
+				regularFile("charts/dup.tgz", string(chartArchive(t, "dup"))),
+				regularFile("charts/dup.tgz", string(chartArchive(t, "dup"))),
+			),
+			wantErr: "more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadChart(context.Background(), tt.archive, limits)
+			if ErrorCodeOf(err) != CodeInvalidArchive || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadChart() error = %v, want %s containing %q", err, CodeInvalidArchive, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadChartSubchartDepth(t *testing.T) {
+	archive := chartArchive(t, "c0")
+	for i := 1; i <= maxSubchartDepth+1; i++ {
+		archive = chartArchive(t, fmt.Sprintf("c%d", i), regularFile("charts/sub.tgz", string(archive)))
+	}
+	_, err := loadChart(context.Background(), archive, defaultArchiveLimits)
+	if ErrorCodeOf(err) != CodeInvalidArchive || !strings.Contains(err.Error(), "levels deep") {
+		t.Errorf("loadChart() error = %v, want a nesting depth error", err)
+	}
+}
@@ -0,0 +1,47 @@
+// filepath: helm-ecr-api/internal/service/credentials.go
+package service
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// credentialsError는 AWS 자격 증명을 가져오지 못했음을 나타냅니다.
+// SDK는 자격 증명 조회 실패를 별도의 에러 타입 없이 메시지로만 감싸므로, classifyAWSError가
+// errors.As로 구분할 수 있도록 credentialsProvider가 이 타입으로 감쌉니다.
+type credentialsError struct {
+	Err error
+}
+
+func (e *credentialsError) Error() string {
+	return "failed to retrieve AWS credentials: " + e.Err.Error()
+}
+
+func (e *credentialsError) Unwrap() error {
+	return e.Err
+}
+
+// credentialsProvider는 자격 증명 조회 에러를 credentialsError로 감싸는 aws.CredentialsProvider입니다.
+type credentialsProvider struct {
+	aws.CredentialsProvider
+}
+
+func (p credentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := p.CredentialsProvider.Retrieve(ctx)
+	if err != nil {
+		return creds, &credentialsError{Err: err}
+	}
+	return creds, nil
+}
+
+// wrapCredentials는 cfg의 자격 증명 공급자를 credentialsProvider로 감싼 설정을 반환합니다.
+// 공급자가 없거나 익명 자격 증명이면 SDK가 서명을 생략할 수 있도록 그대로 둡니다.
+func wrapCredentials(cfg aws.Config) aws.Config {
+	switch cfg.Credentials.(type) {
+	case nil, aws.AnonymousCredentials, *aws.AnonymousCredentials, credentialsProvider:
+		return cfg
+	}
+	cfg.Credentials = credentialsProvider{cfg.Credentials}
+	return cfg
+}
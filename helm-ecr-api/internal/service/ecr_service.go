@@ -2,15 +2,21 @@
 package service
 
 import (
-	"archive/tar"
 	"context"
 	"encoding/base64"
-	"errors"
 	"fmt"
+	"helm-ecr-api/internal/logging"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/signature"
+	"helm-ecr-api/internal/tracing"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
@@ -18,23 +24,31 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var (
-	// ErrChartNotFound는 차트를 찾을 수 없을 때 반환되는 에러입니다.
-	ErrChartNotFound = errors.New("chart not found")
-	// ErrRepositoryNotAllowed는 허용되지 않은 리포지토리에 접근 시 반환되는 에러입니다.
-	ErrRepositoryNotAllowed = errors.New("repository not allowed")
-)
+// tracer는 서비스 계층의 스팬을 생성합니다.
+var tracer = otel.Tracer("helm-ecr-api/internal/service")
 
 // ChartService는 Helm 차트 관련 비즈니스 로직에 대한 인터페이스입니다.
 // 이를 통해 핸들러는 실제 구현으로부터 분리되어 테스트 용이성이 높아집니다.
 type ChartService interface {
-	DescribeHelmChart(ctx context.Context, repoName, tag, digest string) ([]types.ImageDetail, error)
-	ListHelmCharts(ctx context.Context) ([]types.Repository, error)
-	GetChartFile(ctx context.Context, repoName, tag, digest, fileName string) ([]byte, error)
+	DescribeHelmChart(ctx context.Context, repoName, tag, digest string) ([]types.ImageDetail, Freshness, error)
+	ListHelmCharts(ctx context.Context) ([]types.Repository, Freshness, error)
+	GetChartFile(ctx context.Context, repoName, tag, digest, fileName string) (*ChartFile, error)
+	ResolveDigest(ctx context.Context, repoName, tag, digest string) (string, error)
+	ListChartImages(ctx context.Context, repoName, tag, digest string, values map[string]any) (*ChartImages, error)
+	VerifyChartImages(ctx context.Context, images *ChartImages, scanFindings bool) error
+	VerifyChartSignature(ctx context.Context, repoName, manifestDigest string) (*SignatureStatus, error)
+	ListReferrers(ctx context.Context, repoName, tag, digest, artifactType string) (*ChartReferrers, error)
+	GetReferrerContent(ctx context.Context, repoName, referrerDigest string, layer int) (*ReferrerContent, error)
+	LintChart(ctx context.Context, repoName, tag, digest, kubeVersion string) (*LintReport, error)
+	SearchCharts(ctx context.Context, query SearchQuery) (*SearchResults, error)
 }
 
 // ECRService는 ChartService 인터페이스의 구현체입니다.
@@ -44,52 +58,385 @@ type ECRService struct {
 	sts    *sts.Client
 	awsCfg aws.Config
 
-	allowedRepos map[string]struct{} // 빠른 조회를 위해 map 사용
+	// 정확한 이름과 glob 패턴을 함께 관리합니다.
+	// 설정 리로드 시 처리 중인 요청에 영향을 주지 않도록 atomic으로 교체합니다.
+	allowedRepos atomic.Pointer[repoMatcher]
+	logger       *slog.Logger
+
+	// 레지스트리 설정 (registryHost가 비어 있으면 계정 ID와 리전으로 ECR 주소를 구성)
+	registryHost      string
+	registryInsecure  bool
+	registryAnonymous bool
+
+	upstreamTimeout time.Duration     // ECR/레지스트리 호출 타임아웃 (0이면 제한 없음)
+	retryPolicy     RetryPolicy       // 멱등한 조회 호출의 재시도 정책
+	transport       http.RoundTripper // trace context를 전파하는 레지스트리 HTTP 트랜스포트
+	downloads       chan struct{}     // 동시 레이어 다운로드 수를 제한하는 세마포어 (nil이면 제한 없음)
+	renders         chan struct{}     // 동시 차트 렌더링 수를 제한하는 세마포어 (nil이면 제한 없음)
+	renderTimeout   time.Duration     // 차트 템플릿 렌더링 제한 시간 (0이면 요청 타임아웃만 적용)
+	maxFileSize     int64             // GetChartFile로 반환할 수 있는 파일의 최대 크기 (0이면 제한 없음)
+	archiveLimits   ArchiveLimits     // 차트 아카이브 해제 시 적용할 제한
+
+	imageVerifyAccounts map[string]struct{} // 서버의 계정 외에 VerifyChartImages가 조회할 수 있는 AWS 계정 ID
+
+	// 차트 서명 검증 정책 (nil이면 검증하지 않음). 설정 리로드 시 키를 교체할 수 있도록 atomic으로 관리합니다.
+	signatures        atomic.Pointer[signaturePolicy]
+	signatureCacheTTL time.Duration
+
+	// 리포지토리와 차트 버전의 로컬 카탈로그 (nil이면 모든 조회에서 ECR을 직접 호출하고 검색을 사용하지 않음)
+	catalog     *catalog
+	tagListener TagListener // 카탈로그에서 새 차트 태그를 발견하면 호출 (nil이면 알리지 않음)
+
+	// 리소스 태그 기반 허용 목록 설정
+	// exposeTagKey가 비어 있으면 태그 기반 허용 목록을 사용하지 않습니다.
+	exposeTagKey       string
+	exposeTagValue     string
+	tagRefreshInterval time.Duration
+	taggedRepos        atomic.Pointer[map[string]struct{}] // 백그라운드 갱신 중에도 안전하게 교체하기 위해 atomic 사용
+
+	// 계정 ID는 성공한 경우에만 캐싱하여, 일시적인 STS 오류 후에도 다시 조회할 수 있도록 합니다.
+	accountIDMu sync.Mutex
+	accountID   string
+
+	// 준비 상태(readiness) 점검 결과 캐시
+	readinessTTL     time.Duration
+	readinessTimeout time.Duration
+	readinessMu      sync.Mutex
+	readiness        *ReadinessReport
+}
+
+// Option은 ECRService의 선택적 설정을 지정하는 함수입니다.
+// 참조: https://github.com/uber-go/guide/blob/master/style.md#functional-options
+type Option func(*ECRService)
+
+// WithLogger는 ECRService가 백그라운드 작업 등에서 사용할 로거를 지정합니다.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *ECRService) {
+		s.logger = logger
+	}
+}
 
-	accountIDOnce sync.Once
-	accountID     string
-	accountIDErr  error
+// WithExposeTag는 지정한 ECR 리소스 태그(key=value)가 붙은 리포지토리를 허용 목록에 추가합니다.
+// 태그가 붙은 리포지토리 목록은 RunTagRefresher가 interval 주기로 갱신합니다.
+func WithExposeTag(key, value string, interval time.Duration) Option {
+	return func(s *ECRService) {
+		s.exposeTagKey = key
+		s.exposeTagValue = value
+		s.tagRefreshInterval = interval
+	}
+}
+
+// WithRegistry는 ECR 기본 주소 대신 사용할 OCI 레지스트리를 지정합니다. (예: 로컬 테스트용 "localhost:5000")
+// insecure가 true이면 HTTP로 접속하고, anonymous가 true이면 ECR 인증 토큰 없이 접속합니다.
+func WithRegistry(host string, insecure, anonymous bool) Option {
+	return func(s *ECRService) {
+		s.registryHost = host
+		s.registryInsecure = insecure
+		s.registryAnonymous = anonymous
+	}
+}
+
+// WithUpstreamTimeout은 요청마다 ECR/레지스트리 호출에 적용할 타임아웃을 지정합니다.
+func WithUpstreamTimeout(d time.Duration) Option {
+	return func(s *ECRService) {
+		s.upstreamTimeout = d
+	}
+}
+
+// WithRetry는 일시적인 업스트림 에러에 대한 재시도 정책을 지정합니다.
+func WithRetry(policy RetryPolicy) Option {
+	return func(s *ECRService) {
+		s.retryPolicy = policy
+	}
+}
+
+// WithMaxConcurrentDownloads는 동시에 진행할 수 있는 차트 레이어 다운로드 수를 n개로 제한합니다.
+// 제한을 초과한 요청은 기다리지 않고 CodeRateLimited 에러를 반환합니다. n이 0 이하이면 제한하지 않습니다.
+func WithMaxConcurrentDownloads(n int) Option {
+	return func(s *ECRService) {
+		s.downloads = nil
+		if n > 0 {
+			s.downloads = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithRenderLimits는 차트 템플릿 렌더링(이미지 목록, 린트)의 제한 시간과 동시 렌더링 수를 지정합니다.
+// 제한 시간을 넘으면 CodeRenderTimeout 에러를 반환하고, 동시 렌더링 수를 초과한 요청은 기다리지 않고 CodeRenderBusy 에러를 반환합니다.
+// timeout이나 maxConcurrent가 0 이하이면 해당 제한을 적용하지 않습니다.
+func WithRenderLimits(timeout time.Duration, maxConcurrent int) Option {
+	return func(s *ECRService) {
+		s.renderTimeout = max(timeout, 0)
+		s.renders = nil
+		if maxConcurrent > 0 {
+			s.renders = make(chan struct{}, maxConcurrent)
+		}
+	}
+}
+
+// WithImageVerifyAccounts는 VerifyChartImages가 서버의 계정 외에 이미지를 조회할 AWS 계정 ID를 지정합니다.
+// 지정하지 않은 계정의 이미지는 조회하지 않고 skipped로 표시합니다.
+func WithImageVerifyAccounts(accountIDs []string) Option {
+	return func(s *ECRService) {
+		s.imageVerifyAccounts = make(map[string]struct{}, len(accountIDs))
+		for _, id := range accountIDs {
+			s.imageVerifyAccounts[id] = struct{}{}
+		}
+	}
+}
+
+// WithMaxFileSize는 GetChartFile로 반환할 수 있는 차트 내 파일의 최대 크기(바이트)를 지정합니다.
+// 이보다 큰 파일을 요청하면 CodeFileTooLarge 에러를 반환합니다. 0 이하이면 제한하지 않습니다.
+func WithMaxFileSize(n int64) Option {
+	return func(s *ECRService) {
+		s.maxFileSize = n
+	}
+}
+
+// WithArchiveLimits는 차트 아카이브를 해제할 때 적용할 크기, 항목 수, 경로 길이 제한을 지정합니다.
+func WithArchiveLimits(limits ArchiveLimits) Option {
+	return func(s *ECRService) {
+		s.archiveLimits = limits
+	}
+}
+
+// WithReadiness는 준비 상태 점검 결과를 캐시할 시간(ttl)과 점검 전체에 적용할 타임아웃을 지정합니다.
+func WithReadiness(ttl, timeout time.Duration) Option {
+	return func(s *ECRService) {
+		s.readinessTTL = ttl
+		s.readinessTimeout = timeout
+	}
+}
+
+// WithSignatureVerification은 차트 서명(cosign) 검증 모드와 공개 키를 지정합니다.
+// 검증 결과는 매니페스트 다이제스트 기준으로 cacheTTL 동안 캐시합니다.
+func WithSignatureVerification(mode SignatureMode, keys []signature.Key, cacheTTL time.Duration) Option {
+	return func(s *ECRService) {
+		s.signatureCacheTTL = cacheTTL
+		s.SetSignaturePolicy(mode, keys)
+	}
 }
 
 // NewECRService는 ECRService의 새 인스턴스를 생성합니다.
-func NewECRService(cfg aws.Config, allowedRepos []string) *ECRService {
-	allowedReposMap := make(map[string]struct{}, len(allowedRepos))
-	for _, repo := range allowedRepos {
-		allowedReposMap[repo] = struct{}{}
+// allowedRepos에는 정확한 리포지토리 이름 또는 "helm-charts/*", "team-a/**"와 같은 패턴을 지정할 수 있습니다.
+func NewECRService(cfg aws.Config, allowedRepos []string, opts ...Option) (*ECRService, error) {
+	// 자격 증명 조회 실패를 classifyAWSError가 타입으로 구분할 수 있도록 공급자를 감쌉니다.
+	cfg = wrapCredentials(cfg)
+
+	// 재시도는 ECRService의 retryPolicy로 일원화하므로 SDK 자체 재시도는 비활성화합니다.
+	// SDK 재시도와 겹치면 요청 제한 상황에서 호출 횟수가 곱절로 늘어납니다.
+	s := &ECRService{
+		client:             ecr.NewFromConfig(cfg, func(o *ecr.Options) { o.Retryer = aws.NopRetryer{} }),
+		sts:                sts.NewFromConfig(cfg, func(o *sts.Options) { o.Retryer = aws.NopRetryer{} }),
+		awsCfg:             cfg,
+		logger:             slog.Default(),
+		tagRefreshInterval: 5 * time.Minute,
+		retryPolicy:        defaultRetryPolicy,
+		archiveLimits:      defaultArchiveLimits,
+		transport:          otelhttp.NewTransport(remote.DefaultTransport),
+		readinessTTL:       10 * time.Second,
+		readinessTimeout:   5 * time.Second,
+	}
+	if err := s.SetAllowedRepos(allowedRepos); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 
-	return &ECRService{
-		client:       ecr.NewFromConfig(cfg),
-		sts:          sts.NewFromConfig(cfg),
-		awsCfg:       cfg,
-		allowedRepos: allowedReposMap,
+	if s.exposeTagKey != "" && s.tagRefreshInterval <= 0 {
+		return nil, fmt.Errorf("tag refresh interval must be positive: %s", s.tagRefreshInterval)
 	}
+	if s.catalog != nil {
+		if s.catalog.interval <= 0 {
+			return nil, fmt.Errorf("catalog sync interval must be positive: %s", s.catalog.interval)
+		}
+		if s.catalog.maxStaleness <= 0 {
+			return nil, fmt.Errorf("catalog max staleness must be positive: %s", s.catalog.maxStaleness)
+		}
+		if err := s.catalog.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// SetAllowedRepos는 허용 목록(이름 또는 패턴)을 원자적으로 교체합니다.
+// 잘못된 패턴이 포함되어 있으면 기존 목록을 유지하고 에러를 반환합니다.
+// 카탈로그를 사용하면 새로 허용된 리포지토리가 목록에 나타나도록 동기화를 요청합니다.
+func (s *ECRService) SetAllowedRepos(allowedRepos []string) error {
+	matcher, err := newRepoMatcher(allowedRepos)
+	if err != nil {
+		return err
+	}
+	s.allowedRepos.Store(matcher)
+	if s.catalog != nil {
+		s.catalog.requestSync()
+	}
+	return nil
 }
 
-// isRepoAllowed는 요청된 리포지토리가 허용 목록에 있는지 확인합니다.
+// isRepoAllowed는 요청된 리포지토리가 허용 목록의 이름 또는 패턴과 일치하거나,
+// 노출 태그가 붙은 리포지토리인지 확인합니다.
 func (s *ECRService) isRepoAllowed(repoName string) bool {
-	_, ok := s.allowedRepos[repoName]
-	return ok
+	if s.allowedRepos.Load().Match(repoName) {
+		return true
+	}
+
+	if tagged := s.taggedRepos.Load(); tagged != nil {
+		_, ok := (*tagged)[repoName]
+		return ok
+	}
+	return false
 }
 
-// getAccountID는 sync.Once를 사용하여 AWS 계정 ID를 한 번만 조회하고 캐싱합니다.
+// RunTagRefresher는 노출 태그가 붙은 리포지토리 목록을 주기적으로 갱신합니다.
+// ctx가 취소될 때까지 블로킹되므로 고루틴으로 실행해야 합니다.
+// 태그 기반 허용 목록이 설정되지 않은 경우 즉시 반환합니다.
+func (s *ECRService) RunTagRefresher(ctx context.Context) {
+	if s.exposeTagKey == "" {
+		return
+	}
+
+	// 시작 직후 한 번 갱신한 뒤 주기적으로 갱신합니다.
+	s.refreshTaggedRepos(ctx)
+
+	ticker := time.NewTicker(s.tagRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshTaggedRepos(ctx)
+		}
+	}
+}
+
+// refreshTaggedRepos는 모든 리포지토리의 태그를 조회하여 노출 태그가 붙은 리포지토리 목록을 교체합니다.
+// 조회에 실패하면 기존 목록을 유지하여 일시적인 AWS 오류로 리포지토리가 사라지지 않도록 합니다.
+func (s *ECRService) refreshTaggedRepos(ctx context.Context) {
+	tagged, err := s.listTaggedRepos(ctx)
+	if err != nil {
+		s.logger.Error("failed to refresh tagged repositories", "error", err, "tag", s.exposeTagKey)
+		return
+	}
+
+	prev := s.taggedRepos.Swap(&tagged)
+	if s.catalog != nil && (prev == nil || !maps.Equal(*prev, tagged)) {
+		s.catalog.requestSync()
+	}
+	s.logger.Info("refreshed tagged repositories", "tag", s.exposeTagKey, "count", len(tagged))
+}
+
+// listTaggedRepos는 ListTagsForResource를 사용하여 노출 태그가 붙은 리포지토리 이름을 수집합니다.
+func (s *ECRService) listTaggedRepos(ctx context.Context) (map[string]struct{}, error) {
+	tagged := make(map[string]struct{})
+
+	paginator := ecr.NewDescribeRepositoriesPaginator(s.client, &ecr.DescribeRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := s.describeRepositoriesPage(ctx, paginator)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe repositories: %w", err)
+		}
+
+		for _, repo := range page.Repositories {
+			var output *ecr.ListTagsForResourceOutput
+			err := s.retry(ctx, "ecr.ListTagsForResource", func(ctx context.Context) (err error) {
+				start := time.Now()
+				output, err = s.client.ListTagsForResource(ctx, &ecr.ListTagsForResourceInput{
+					ResourceArn: repo.RepositoryArn,
+				})
+				metrics.ObserveUpstream("ecr.ListTagsForResource", start, err)
+				if err != nil {
+					return classifyAWSError(err, aws.ToString(repo.RepositoryName))
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list tags for %s: %w", aws.ToString(repo.RepositoryName), err)
+			}
+
+			for _, tag := range output.Tags {
+				if aws.ToString(tag.Key) == s.exposeTagKey && aws.ToString(tag.Value) == s.exposeTagValue {
+					tagged[aws.ToString(repo.RepositoryName)] = struct{}{}
+					break
+				}
+			}
+		}
+	}
+
+	return tagged, nil
+}
+
+// getAccountID는 AWS 계정 ID를 조회하고 캐싱합니다.
+// 조회에 실패하면 캐싱하지 않으므로 다음 호출에서 다시 시도합니다.
 func (s *ECRService) getAccountID(ctx context.Context) (string, error) {
-	s.accountIDOnce.Do(func() {
-		identity, err := s.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	s.accountIDMu.Lock()
+	defer s.accountIDMu.Unlock()
+
+	if s.accountID != "" {
+		return s.accountID, nil
+	}
+
+	var identity *sts.GetCallerIdentityOutput
+	err := s.retry(ctx, "sts.GetCallerIdentity", func(ctx context.Context) (err error) {
+		start := time.Now()
+		identity, err = s.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		metrics.ObserveUpstream("sts.GetCallerIdentity", start, err)
 		if err != nil {
-			s.accountIDErr = fmt.Errorf("failed to get caller identity: %w", err)
-			return
+			return classifyAWSError(err, "")
 		}
-		s.accountID = aws.ToString(identity.Account)
+		return nil
 	})
-	return s.accountID, s.accountIDErr
+	if err != nil {
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	s.accountID = aws.ToString(identity.Account)
+	return s.accountID, nil
+}
+
+// withUpstreamTimeout은 설정된 타임아웃을 적용한 컨텍스트를 반환합니다.
+func (s *ECRService) withUpstreamTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.upstreamTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.upstreamTimeout)
 }
 
 // DescribeHelmChart는 ECR에서 특정 Helm 차트(OCI 이미지)의 상세 정보를 조회합니다.
-func (s *ECRService) DescribeHelmChart(ctx context.Context, repoName, tag, digest string) ([]types.ImageDetail, error) {
+// 카탈로그에 최신 레코드가 있으면 카탈로그에서 반환하고, 없으면 ECR을 직접 조회합니다.
+func (s *ECRService) DescribeHelmChart(ctx context.Context, repoName, tag, digest string) (_ []types.ImageDetail, _ Freshness, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.DescribeHelmChart", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("chart.tag", tag),
+		attribute.String("chart.digest", digest),
+	))
+	defer func() { tracing.End(span, err) }()
+
 	if !s.isRepoAllowed(repoName) {
-		return nil, fmt.Errorf("%w: %s", ErrRepositoryNotAllowed, repoName)
+		return nil, Freshness{}, newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+
+	if images, syncedAt, ok := s.catalogImages(repoName, tag, digest); ok {
+		span.SetAttributes(attribute.String("data.source", string(DataSourceCatalog)))
+		return images, Freshness{Source: DataSourceCatalog, SyncedAt: syncedAt}, nil
 	}
+	span.SetAttributes(attribute.String("data.source", string(DataSourceLive)))
+
+	images, err := s.describeImages(ctx, repoName, tag, digest)
+	if err != nil {
+		return nil, Freshness{}, err
+	}
+	return images, liveFreshness(), nil
+}
+
+// describeImages는 DescribeImages로 리포지토리의 특정 버전 또는 모든 버전의 이미지 정보를 조회합니다.
+func (s *ECRService) describeImages(ctx context.Context, repoName, tag, digest string) ([]types.ImageDetail, error) {
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
 
 	input := &ecr.DescribeImagesInput{
 		RepositoryName: aws.String(repoName),
@@ -102,140 +449,430 @@ func (s *ECRService) DescribeHelmChart(ctx context.Context, repoName, tag, diges
 		input.ImageIds = []types.ImageIdentifier{{ImageDigest: aws.String(digest)}}
 	}
 
-	result, err := s.client.DescribeImages(ctx, input)
+	var result *ecr.DescribeImagesOutput
+	err := s.retry(ctx, "ecr.DescribeImages", func(ctx context.Context) (err error) {
+		start := time.Now()
+		result, err = s.client.DescribeImages(ctx, input)
+		metrics.ObserveUpstream("ecr.DescribeImages", start, err)
+		if err != nil {
+			return classifyAWSError(err, chartDisplayName(repoName, tag, digest))
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	logging.FromContext(ctx).Debug("described chart images", "repo", repoName, "count", len(result.ImageDetails))
+
 	if len(result.ImageDetails) == 0 {
-		if tag != "" {
-			return nil, &types.ImageNotFoundException{Message: aws.String(fmt.Sprintf("chart not found with tag: %s", tag))}
+		if tag != "" || digest != "" {
+			return nil, newError(CodeVersionNotFound, nil, "chart version not found: %s", chartDisplayName(repoName, tag, digest))
 		}
-		if digest != "" {
-			return nil, &types.ImageNotFoundException{Message: aws.String(fmt.Sprintf("chart not found with digest: %s", digest))}
-		}
-		return nil, &types.RepositoryNotFoundException{Message: aws.String(fmt.Sprintf("chart not found in repository: %s", repoName))}
+		return nil, newError(CodeVersionNotFound, nil, "no chart versions in repository: %s", repoName)
 	}
 
 	return result.ImageDetails, nil
 }
 
-// ListHelmCharts는 ECR에 있는 모든 리포지토리를 조회합니다.
-// ECR API는 페이지네이션을 사용하므로, 모든 결과를 가져오기 위해 반복 호출합니다.
-func (s *ECRService) ListHelmCharts(ctx context.Context) ([]types.Repository, error) {
-	repoNames := make([]string, 0, len(s.allowedRepos))
-	for repo := range s.allowedRepos {
-		repoNames = append(repoNames, repo)
+// ListHelmCharts는 ECR에 있는 리포지토리 중 허용 목록과 일치하는 리포지토리를 조회합니다.
+// 카탈로그가 최신 상태이면 카탈로그에서 이름순으로 반환하고, 아니면 ECR을 직접 조회합니다.
+func (s *ECRService) ListHelmCharts(ctx context.Context) (_ []types.Repository, _ Freshness, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.ListHelmCharts")
+	defer func() { tracing.End(span, err) }()
+
+	if repos, syncedAt, ok := s.catalogRepositories(); ok {
+		span.SetAttributes(attribute.String("data.source", string(DataSourceCatalog)))
+		return repos, Freshness{Source: DataSourceCatalog, SyncedAt: syncedAt}, nil
 	}
+	span.SetAttributes(attribute.String("data.source", string(DataSourceLive)))
 
-	if len(repoNames) == 0 {
-		return []types.Repository{}, nil // 허용된 리포지토리가 없으면 빈 목록 반환
+	repos, err := s.listRepositories(ctx)
+	if err != nil {
+		return nil, Freshness{}, err
 	}
+	return repos, liveFreshness(), nil
+}
 
-	result, err := s.client.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
-		RepositoryNames: repoNames,
+// listRepositories는 DescribeRepositories로 허용 목록과 일치하는 리포지토리를 조회합니다.
+// 패턴으로 지정된 리포지토리를 찾기 위해 계정의 모든 리포지토리를 조회한 뒤 필터링합니다.
+// ECR API는 페이지네이션을 사용하므로, 모든 결과를 가져오기 위해 반복 호출합니다.
+func (s *ECRService) listRepositories(ctx context.Context) ([]types.Repository, error) {
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	repos := []types.Repository{}
+
+	paginator := ecr.NewDescribeRepositoriesPaginator(s.client, &ecr.DescribeRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := s.describeRepositoriesPage(ctx, paginator)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range page.Repositories {
+			if s.isRepoAllowed(aws.ToString(repo.RepositoryName)) {
+				repos = append(repos, repo)
+			}
+		}
+	}
+
+	return repos, nil
+}
+
+// describeRepositoriesPage는 DescribeRepositories의 다음 페이지를 조회합니다.
+// 페이지네이터는 실패한 호출에서 다음 토큰을 갱신하지 않으므로 같은 페이지를 안전하게 다시 요청할 수 있습니다.
+func (s *ECRService) describeRepositoriesPage(ctx context.Context, paginator *ecr.DescribeRepositoriesPaginator) (page *ecr.DescribeRepositoriesOutput, err error) {
+	err = s.retry(ctx, "ecr.DescribeRepositories", func(ctx context.Context) (err error) {
+		start := time.Now()
+		page, err = paginator.NextPage(ctx)
+		metrics.ObserveUpstream("ecr.DescribeRepositories", start, err)
+		if err != nil {
+			return classifyAWSError(err, "")
+		}
+		return nil
 	})
+	return page, err
+}
+
+// GetChartFile은 ECR에서 차트(.tar.gz)를 다운로드하고 특정 파일(예: 'values.yaml', 'Chart.yaml')을 엽니다.
+// 파일 내용은 메모리에 풀어 두지 않고 압축 아카이브에서 읽는 동안 해제되므로, 호출자는 반드시 Body를 닫아야 합니다.
+// 이 함수는 go-containerregistry 라이브러리를 사용하여 OCI 표준 방식으로 차트를 가져옵니다.
+func (s *ECRService) GetChartFile(ctx context.Context, repoName, tag, digest, fileName string) (_ *ChartFile, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.GetChartFile", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("chart.tag", tag),
+		attribute.String("chart.digest", digest),
+		attribute.String("chart.file", fileName),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !s.isRepoAllowed(repoName) {
+		return nil, newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	archive, manifestDigest, err := s.fetchChartArchive(ctx, repoName, tag, digest)
 	if err != nil {
 		return nil, err
 	}
 
-	return result.Repositories, nil
+	file, err := openArchiveFile(ctx, archive, fileName, s.archiveLimits)
+	if err != nil {
+		return nil, err
+	}
+	file.Digest = manifestDigest
+
+	if s.maxFileSize > 0 && file.Size > s.maxFileSize {
+		file.Body.Close()
+		return nil, newError(CodeFileTooLarge, nil, "file %s is %d bytes, which exceeds the limit of %d bytes", fileName, file.Size, s.maxFileSize)
+	}
+	return file, nil
 }
 
-// GetChartFile은 ECR에서 차트(.tar.gz)를 다운로드하고 압축을 해제하여
-// 특정 파일(예: 'values.yaml', 'Chart.yaml')의 내용을 반환합니다.
-// 이 함수는 go-containerregistry 라이브러리를 사용하여 OCI 표준 방식으로 차트를 가져옵니다.
-func (s *ECRService) GetChartFile(ctx context.Context, repoName, tag, digest, fileName string) ([]byte, error) {
+// ResolveDigest는 tag 또는 digest가 가리키는 차트 매니페스트 다이제스트를 반환합니다.
+// digest로 요청하면 레지스트리를 호출하지 않고 형식만 검증하여 그대로 반환하고,
+// tag로 요청하면 HEAD 요청 한 번으로 다이제스트를 조회하므로 차트를 내려받지 않고 캐시 검증에 사용할 수 있습니다.
+func (s *ECRService) ResolveDigest(ctx context.Context, repoName, tag, digest string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.ResolveDigest", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("chart.tag", tag),
+		attribute.String("chart.digest", digest),
+	))
+	defer func() { tracing.End(span, err) }()
+
 	if !s.isRepoAllowed(repoName) {
-		return nil, fmt.Errorf("%w: %s", ErrRepositoryNotAllowed, repoName)
+		return "", newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+
+	if tag == "" {
+		if _, err := v1.NewHash(digest); err != nil {
+			return "", newError(CodeInvalidArgument, err, "invalid digest: %s", digest)
+		}
+		return digest, nil
 	}
 
-	// 1. 캐시된 AWS 계정 ID를 가져옵니다.
-	accountID, err := s.getAccountID(ctx)
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	ref, err := s.chartReference(ctx, repoName, tag, "")
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	auth, err := s.registryAuth(ctx)
+	if err != nil {
+		return "", err
 	}
+	return s.resolveTag(ctx, ref, auth)
+}
 
-	// 2. ECR 리포지토리 URI를 구성합니다.
+// chartReference는 리포지토리 이름과 tag 또는 digest로 OCI 이미지 참조를 구성합니다.
+func (s *ECRService) chartReference(ctx context.Context, repoName, tag, digest string) (name.Reference, error) {
+	// 1. 레지스트리 주소를 결정합니다. 별도로 지정하지 않으면 캐시된 AWS 계정 ID로 ECR 주소를 구성합니다.
+	// 예: 123456789012.dkr.ecr.ap-northeast-2.amazonaws.com
+	host := s.registryHost
+	if host == "" {
+		accountID, err := s.getAccountID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		host = fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com", accountID, s.awsCfg.Region)
+	}
+
+	// 2. 리포지토리 URI를 구성합니다.
 	// 예: 123456789012.dkr.ecr.ap-northeast-2.amazonaws.com/my-helm-charts/my-app
-	repoURI := fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s", accountID, s.awsCfg.Region, repoName)
+	repoURI := fmt.Sprintf("%s/%s", host, repoName)
+
+	var nameOpts []name.Option
+	if s.registryInsecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
 
 	var ref name.Reference
+	var err error
 	if tag != "" {
-		ref, err = name.NewTag(fmt.Sprintf("%s:%s", repoURI, tag))
+		ref, err = name.NewTag(fmt.Sprintf("%s:%s", repoURI, tag), nameOpts...)
 	} else if digest != "" {
-		ref, err = name.NewDigest(fmt.Sprintf("%s@%s", repoURI, digest))
+		ref, err = name.NewDigest(fmt.Sprintf("%s@%s", repoURI, digest), nameOpts...)
 	} else {
-		return nil, fmt.Errorf("either tag or digest must be provided")
+		return nil, newError(CodeInvalidArgument, nil, "either tag or digest must be provided")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse image reference: %w", err)
+		return nil, newError(CodeInvalidArgument, err, "invalid chart reference: %s", chartDisplayName(repoName, tag, digest))
 	}
+	return ref, nil
+}
 
-	// 3. ECR 인증 토큰을 가져와서 직접 인증을 설정합니다.
+// registryAuth는 레지스트리 접속에 사용할 인증 정보를 반환합니다.
+func (s *ECRService) registryAuth(ctx context.Context) (authn.Authenticator, error) {
+	if s.registryAnonymous {
+		return authn.Anonymous, nil
+	}
 	token, err := s.getECRAuthToken(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ECR auth token: %w", err)
 	}
+	return token, nil
+}
+
+// remoteOptions는 go-containerregistry 호출에 사용할 공통 옵션을 반환합니다.
+// ctx에 담긴 trace context가 레지스트리 HTTP 요청에 전파되도록 호출마다 ctx를 지정합니다.
+// 상태 코드 기반 재시도는 retryPolicy로 처리하므로 라이브러리의 재시도는 비활성화합니다.
+func (s *ECRService) remoteOptions(ctx context.Context, auth authn.Authenticator) []remote.Option {
+	return []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuth(auth),
+		remote.WithTransport(s.transport),
+		remote.WithRetryBackoff(remote.Backoff{Steps: 1}),
+		remote.WithRetryStatusCodes(),
+	}
+}
+
+// registryError는 레지스트리 호출 에러를 서비스 에러로 변환합니다.
+// 클라이언트에 노출되는 메시지에는 레지스트리 주소(계정 ID 포함)를 제외한 리포지토리 이름만 사용합니다.
+func registryError(ref name.Reference, err error) error {
+	repoName := ref.Context().RepositoryStr()
+	if d, ok := ref.(name.Digest); ok {
+		return classifyRegistryError(err, chartDisplayName(repoName, "", d.DigestStr()))
+	}
+	return classifyRegistryError(err, chartDisplayName(repoName, ref.Identifier(), ""))
+}
+
+// chartDisplayName은 에러 메시지에 사용할 "repo:tag" 또는 "repo@digest" 형태의 이름을 반환합니다.
+func chartDisplayName(repoName, tag, digest string) string {
+	switch {
+	case tag != "":
+		return repoName + ":" + tag
+	case digest != "":
+		return repoName + "@" + digest
+	default:
+		return repoName
+	}
+}
+
+// fetchChartArchive는 차트 매니페스트를 조회하고 Helm 차트 콘텐츠 레이어(.tgz)와 매니페스트 다이제스트를 반환합니다.
+// 매니페스트 다이제스트가 같은 아카이브는 캐시에서 반환하여 매니페스트 조회와 레이어 다운로드를 생략합니다.
+func (s *ECRService) fetchChartArchive(ctx context.Context, repoName, tag, digest string) ([]byte, string, error) {
+	ref, err := s.chartReference(ctx, repoName, tag, digest)
+	if err != nil {
+		return nil, "", err
+	}
 
-	// 4. go-containerregistry를 사용하여 OCI 이미지를 가져옵니다.
-	img, err := remote.Image(ref, remote.WithContext(ctx), remote.WithAuth(token))
+	auth, err := s.registryAuth(ctx)
 	if err != nil {
-		// 404 Not Found와 같은 특정 오류를 확인하여 커스텀 에러를 반환합니다.
-		var transportErr *transport.Error
-		if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("%w: %s", ErrChartNotFound, ref.Name())
+		return nil, "", err
+	}
+
+	// 1. tag로 요청한 경우 매니페스트 다이제스트로 변환합니다.
+	logger := logging.FromContext(ctx)
+	manifestDigest := digest
+	if tag != "" {
+		manifestDigest, err = s.resolveTag(ctx, ref, auth)
+		if err != nil {
+			return nil, "", err
 		}
-		return nil, fmt.Errorf("failed to get remote image: %w", err)
+		logger.Debug("resolved chart tag", "repo", repoName, "tag", tag, "digest", manifestDigest)
+	}
+	digestRef := ref.Context().Digest(manifestDigest)
+
+	// 2. 매니페스트를 가져와 Helm 차트 콘텐츠 레이어를 찾습니다.
+	layer, err := s.fetchChartLayer(ctx, digestRef, auth)
+	if err != nil {
+		return nil, "", err
 	}
 
-	// 5. 이미지 레이어를 순회하여 Helm 차트 콘텐츠 레이어를 찾습니다.
-	layers, err := img.Layers()
+	// 3. 압축된 레이어(.tgz)를 그대로 읽습니다.
+	archive, err := s.downloadLayer(ctx, ref.Context().Digest(layer.Digest.String()), layer.Size, s.archiveLimits.MaxCompressedSize, auth)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get image layers: %w", err)
+		return nil, "", err
 	}
 
-	for _, layer := range layers {
-		mediaType, err := layer.MediaType()
+	logger.Debug("downloaded chart archive", "repo", repoName, "digest", manifestDigest, "bytes", len(archive))
+	return archive, manifestDigest, nil
+}
+
+// resolveTag는 HEAD 요청으로 tag가 가리키는 매니페스트 다이제스트를 조회합니다.
+func (s *ECRService) resolveTag(ctx context.Context, ref name.Reference, auth authn.Authenticator) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "chart.resolve_tag", trace.WithAttributes(
+		attribute.String("oci.reference", ref.Name()),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	var desc *v1.Descriptor
+	err = s.retry(ctx, "registry.Head", func(ctx context.Context) (err error) {
+		start := time.Now()
+		desc, err = remote.Head(ref, s.remoteOptions(ctx, auth)...)
+		metrics.ObserveUpstream("registry.Head", start, err)
 		if err != nil {
-			continue
+			return registryError(ref, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
 
-		// Helm 차트 콘텐츠의 mediaType은 'application/vnd.cncf.helm.chart.content.v1.tar+gzip' 입니다.
-		if string(mediaType) == "application/vnd.cncf.helm.chart.content.v1.tar+gzip" {
-			// 6. 레이어의 압축을 풀고 tar 아카이브에서 원하는 파일을 찾습니다.
-			// layer.Uncompressed()는 라이브러리가 gzip 압축을 자동으로 처리하도록 합니다.
-			rc, err := layer.Uncompressed()
-			if err != nil {
-				return nil, fmt.Errorf("failed to uncompress layer: %w", err)
-			}
-			defer rc.Close()
+	span.SetAttributes(attribute.String("oci.digest", desc.Digest.String()))
+	return desc.Digest.String(), nil
+}
 
-			tarReader := tar.NewReader(rc)
-			for {
-				header, err := tarReader.Next()
-				if err == io.EOF {
-					break // 파일 끝
-				}
-				if err != nil {
-					return nil, fmt.Errorf("failed to read tar archive: %w", err)
-				}
+// fetchChartLayer는 매니페스트를 가져와 Helm 차트 콘텐츠 레이어의 디스크립터(다이제스트, 크기)를 반환합니다.
+func (s *ECRService) fetchChartLayer(ctx context.Context, ref name.Digest, auth authn.Authenticator) (_ v1.Descriptor, err error) {
+	ctx, span := tracer.Start(ctx, "chart.fetch_manifest", trace.WithAttributes(
+		attribute.String("oci.reference", ref.Name()),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	var img v1.Image
+	err = s.retry(ctx, "registry.Image", func(ctx context.Context) (err error) {
+		start := time.Now()
+		img, err = remote.Image(ref, s.remoteOptions(ctx, auth)...)
+		metrics.ObserveUpstream("registry.Image", start, err)
+		if err != nil {
+			return registryError(ref, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
 
-				// 파일 경로는 'chart-name/values.yaml' 형태일 수 있으므로 HasSuffix로 확인합니다.
-				if strings.HasSuffix(header.Name, fileName) {
-					return io.ReadAll(tarReader)
-				}
-			}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return v1.Descriptor{}, fmt.Errorf("failed to get image manifest: %w", err)
+	}
+
+	// Helm 차트 콘텐츠의 mediaType은 'application/vnd.cncf.helm.chart.content.v1.tar+gzip' 입니다.
+	for _, layer := range manifest.Layers {
+		if string(layer.MediaType) == "application/vnd.cncf.helm.chart.content.v1.tar+gzip" {
+			return layer, nil
+		}
+	}
+
+	return v1.Descriptor{}, newError(CodeVersionNotFound, nil, "no helm chart content layer: %s@%s", ref.Context().RepositoryStr(), ref.DigestStr())
+}
+
+// downloadLayer는 압축된 레이어 전체를 내려받고 다운로드 바이트 수와 지연 시간을 기록합니다.
+// size는 매니페스트에 기록된 레이어 크기이며, size나 실제로 읽은 크기가 limit(0이면 제한 없음)을 넘으면 CodeInvalidArchive 에러를 반환합니다.
+// 매니페스트의 크기를 믿지 않고 읽는 양도 제한하므로, 크기를 속인 레이어도 메모리에 모두 읽지 않습니다.
+// 다운로드 도중 연결이 끊기면 레이어 전체를 처음부터 다시 내려받습니다.
+func (s *ECRService) downloadLayer(ctx context.Context, ref name.Digest, size, limit int64, auth authn.Authenticator) (archive []byte, err error) {
+	ctx, span := tracer.Start(ctx, "chart.download_layer", trace.WithAttributes(
+		attribute.String("oci.reference", ref.Name()),
+		attribute.Int64("oci.layer_size", size),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("chart.archive_bytes", len(archive)))
+		tracing.End(span, err)
+	}()
+
+	if limit > 0 && size > limit {
+		return nil, newError(CodeInvalidArchive, nil, "layer %s is %d bytes, exceeding the limit of %d bytes", ref.DigestStr(), size, limit)
+	}
+
+	// 다운로드가 몰리면 레지스트리와 ECR 요청 제한을 소진하므로 대기열을 만들지 않고 바로 거절합니다.
+	if s.downloads != nil {
+		select {
+		case s.downloads <- struct{}{}:
+			defer func() { <-s.downloads }()
+		default:
+			metrics.IncRateLimited("downloads")
+			e := newError(CodeRateLimited, nil, "too many concurrent chart downloads, retry later")
+			e.RetryAfter = downloadsRetryAfter
+			return nil, e
 		}
 	}
 
-	return nil, fmt.Errorf("'%s' not found in chart archive", fileName)
+	err = s.retry(ctx, "registry.LayerDownload", func(ctx context.Context) (err error) {
+		start := time.Now()
+		defer func() { metrics.ObserveUpstream("registry.LayerDownload", start, err) }()
+
+		layer, err := remote.Layer(ref, s.remoteOptions(ctx, auth)...)
+		if err != nil {
+			return registryError(ref, err)
+		}
+
+		rc, err := layer.Compressed()
+		if err != nil {
+			return registryError(ref, err)
+		}
+		defer rc.Close()
+
+		var r io.Reader = rc
+		if limit > 0 {
+			r = io.LimitReader(rc, limit+1)
+		}
+		archive, err = io.ReadAll(r)
+		metrics.AddLayerDownloadBytes(len(archive))
+		if err != nil {
+			return registryError(ref, fmt.Errorf("failed to read layer: %w", err))
+		}
+		if limit > 0 && int64(len(archive)) > limit {
+			return newError(CodeInvalidArchive, nil, "layer %s exceeds the limit of %d bytes (manifest declares %d bytes)", ref.DigestStr(), limit, size)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
 }
 
 // getECRAuthToken은 AWS ECR로부터 인증 토큰을 가져와서 Basic 인증 형태로 반환합니다.
 func (s *ECRService) getECRAuthToken(ctx context.Context) (authn.Authenticator, error) {
 	// ECR GetAuthorizationToken API를 호출하여 인증 토큰을 가져옵니다.
-	output, err := s.client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	var output *ecr.GetAuthorizationTokenOutput
+	err := s.retry(ctx, "ecr.GetAuthorizationToken", func(ctx context.Context) (err error) {
+		start := time.Now()
+		output, err = s.client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+		metrics.ObserveUpstream("ecr.GetAuthorizationToken", start, err)
+		if err != nil {
+			return classifyAWSError(err, "")
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ECR authorization token: %w", err)
 	}
@@ -262,4 +899,4 @@ func (s *ECRService) getECRAuthToken(ctx context.Context) (authn.Authenticator,
 		Username: parts[0],
 		Password: parts[1],
 	}, nil
-}
\ No newline at end of file
+}
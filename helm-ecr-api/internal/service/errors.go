@@ -0,0 +1,249 @@
+// filepath: helm-ecr-api/internal/service/errors.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// ErrorCode는 클라이언트가 에러 유형을 구분할 수 있도록 API 응답에 그대로 노출되는 안정적인 코드입니다.
+// 코드를 추가하면 핸들러의 상태 코드 매핑에도 반드시 추가해야 합니다.
+type ErrorCode string
+
+const (
+	CodeRepoNotAllowed      ErrorCode = "repo_not_allowed"     // 허용 목록에 없는 리포지토리
+	CodeRepoNotFound        ErrorCode = "repo_not_found"       // ECR에 리포지토리가 없음
+	CodeVersionNotFound     ErrorCode = "version_not_found"    // tag 또는 digest에 해당하는 차트 버전이 없음
+	CodeFileNotFound        ErrorCode = "file_not_found"       // 차트 아카이브에 요청한 파일이 없음
+	CodeFileTooLarge        ErrorCode = "file_too_large"       // 요청한 파일이 설정된 최대 크기를 초과함
+	CodeInvalidArchive      ErrorCode = "invalid_archive"      // 차트 아카이브가 손상되었거나 제한을 초과하거나 위험한 항목을 포함함
+	CodeInvalidArgument     ErrorCode = "invalid_argument"     // 요청 파라미터가 잘못됨
+	CodeSignatureRequired   ErrorCode = "signature_required"   // 서명 강제 모드에서 차트에 서명이 없음
+	CodeSignatureInvalid    ErrorCode = "signature_invalid"    // 서명 강제 모드에서 설정된 공개 키로 검증되는 서명이 없음
+	CodeUpstreamThrottled   ErrorCode = "upstream_throttled"   // ECR/레지스트리가 요청을 제한함
+	CodeUpstreamUnavailable ErrorCode = "upstream_unavailable" // ECR/레지스트리에 일시적으로 접근할 수 없음
+	CodeUpstreamAuth        ErrorCode = "upstream_auth_failed" // AWS 자격 증명 만료, 권한 부족 등으로 ECR/레지스트리 인증에 실패함
+	CodeRateLimited         ErrorCode = "rate_limited"         // 서버의 동시 다운로드 제한을 초과함
+	CodeSearchUnavailable   ErrorCode = "search_unavailable"   // 검색 색인이 비활성화되었거나 아직 만들어지지 않음
+	CodeRenderTimeout       ErrorCode = "render_timeout"       // 차트 템플릿 렌더링이 제한 시간 안에 끝나지 않음
+	CodeRenderBusy          ErrorCode = "render_busy"          // 동시 차트 템플릿 렌더링 수 제한을 초과함
+	CodeCanceled            ErrorCode = "canceled"             // 클라이언트가 연결을 끊는 등 요청이 처리 중에 취소됨
+	CodeInternal            ErrorCode = "internal"             // 그 외 분류되지 않은 에러
+)
+
+// Error는 서비스 계층이 반환하는 에러입니다.
+// Message는 클라이언트에 노출해도 안전한 설명이며, 원인 에러(AWS 에러 메시지 등)는 Err에 담아 로그에만 기록합니다.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Err     error
+
+	// RetryAfter는 클라이언트가 다시 시도하기 전에 기다려야 할 시간입니다. 0이면 힌트를 주지 않습니다.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is는 에러 코드가 같으면 같은 에러로 취급하여 errors.Is(err, ErrChartNotFound)와 같은 비교를 지원합니다.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+var (
+	// ErrChartNotFound는 차트를 찾을 수 없을 때 반환되는 에러입니다.
+	ErrChartNotFound = &Error{Code: CodeVersionNotFound, Message: "chart not found"}
+	// ErrRepositoryNotAllowed는 허용되지 않은 리포지토리에 접근 시 반환되는 에러입니다.
+	ErrRepositoryNotAllowed = &Error{Code: CodeRepoNotAllowed, Message: "repository not allowed"}
+)
+
+// newError는 지정한 코드와 메시지로 에러를 생성합니다.
+func newError(code ErrorCode, cause error, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// 일시적인 업스트림 장애에 대해 클라이언트에 전달할 재시도 대기 시간 힌트
+const (
+	throttledRetryAfter   = 2 * time.Second
+	unavailableRetryAfter = 5 * time.Second
+	downloadsRetryAfter   = 1 * time.Second
+	renderRetryAfter      = 5 * time.Second
+)
+
+// awsThrottleCodes는 AWS API가 요청 제한 시 반환하는 에러 코드입니다.
+var awsThrottleCodes = map[string]struct{}{
+	"ThrottlingException":                    {},
+	"Throttling":                             {},
+	"TooManyRequestsException":               {},
+	"RequestLimitExceeded":                   {},
+	"ProvisionedThroughputExceededException": {},
+}
+
+// awsAuthCodes는 자격 증명 만료나 권한 부족을 나타내는 AWS 에러 코드입니다.
+var awsAuthCodes = map[string]struct{}{
+	"AccessDeniedException":       {},
+	"AccessDenied":                {},
+	"ExpiredToken":                {},
+	"ExpiredTokenException":       {},
+	"InvalidClientTokenId":        {},
+	"UnrecognizedClientException": {},
+	"InvalidSignatureException":   {},
+	"SignatureDoesNotMatch":       {},
+}
+
+// ErrorCodeOf는 에러 체인에서 서비스 에러 코드를 찾아 반환합니다. 없으면 CodeInternal을 반환합니다.
+// 요청이 취소되어 실패한 에러는 어느 단계에서 감쌌는지와 관계없이 CodeCanceled를 반환합니다.
+func ErrorCodeOf(err error) ErrorCode {
+	if errors.Is(err, context.Canceled) {
+		return CodeCanceled
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
+
+// RetryAfterOf는 에러 체인에서 재시도 대기 시간 힌트를 찾아 반환합니다. 없으면 0을 반환합니다.
+func RetryAfterOf(err error) time.Duration {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.RetryAfter
+	}
+	return 0
+}
+
+// isRetryable은 같은 요청을 다시 보내면 성공할 수 있는 일시적인 에러인지 확인합니다.
+func isRetryable(err error) bool {
+	switch ErrorCodeOf(err) {
+	case CodeUpstreamThrottled, CodeUpstreamUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// throttled와 unavailable은 재시도 힌트가 포함된 일시적 장애 에러를 생성합니다.
+func throttled(cause error, format string, args ...any) *Error {
+	e := newError(CodeUpstreamThrottled, cause, format, args...)
+	e.RetryAfter = throttledRetryAfter
+	return e
+}
+
+func unavailable(cause error, format string, args ...any) *Error {
+	e := newError(CodeUpstreamUnavailable, cause, format, args...)
+	e.RetryAfter = unavailableRetryAfter
+	return e
+}
+
+// isNetworkError는 연결 실패, 타임아웃, 응답 본문 수신 중 연결 끊김 등 응답을 온전히 받지 못한 에러인지 확인합니다.
+// 업스트림 타임아웃(context.DeadlineExceeded)도 일시적인 장애로 취급합니다.
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	var sendErr *smithyhttp.RequestSendError
+	return errors.As(err, &netErr) || errors.As(err, &sendErr) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// PublicMessage는 클라이언트에 노출해도 안전한 에러 메시지를 반환합니다.
+// 서비스 에러가 아니거나 분류되지 않은 에러는 원인을 숨기고 일반 메시지를 반환합니다.
+func PublicMessage(err error) string {
+	if ErrorCodeOf(err) == CodeCanceled {
+		return "request was canceled"
+	}
+	var e *Error
+	if errors.As(err, &e) && e.Code != CodeInternal {
+		return e.Message
+	}
+	return "internal server error"
+}
+
+// classifyAWSError는 AWS SDK 에러를 서비스 에러로 변환합니다.
+// 분류할 수 없는 에러는 CodeInternal로 감싸며, 원본 메시지는 클라이언트에 노출되지 않습니다.
+func classifyAWSError(err error, resource string) error {
+	var repoNotFound *types.RepositoryNotFoundException
+	var imageNotFound *types.ImageNotFoundException
+	var apiErr smithy.APIError
+	var respErr *awshttp.ResponseError
+	var credsErr *credentialsError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return newError(CodeCanceled, err, "request was canceled")
+	case errors.As(err, &repoNotFound):
+		return newError(CodeRepoNotFound, err, "repository not found: %s", resource)
+	case errors.As(err, &imageNotFound):
+		return newError(CodeVersionNotFound, err, "chart version not found: %s", resource)
+	case errors.As(err, &apiErr) && isAWSErrorCode(apiErr, awsThrottleCodes):
+		return throttled(err, "ECR request was throttled")
+	case errors.As(err, &apiErr) && isAWSErrorCode(apiErr, awsAuthCodes):
+		return newError(CodeUpstreamAuth, err, "ECR rejected the service credentials")
+	case errors.As(err, &credsErr):
+		return newError(CodeUpstreamAuth, err, "failed to obtain AWS credentials")
+	case errors.As(err, &respErr) && respErr.HTTPStatusCode() >= http.StatusInternalServerError:
+		return unavailable(err, "ECR is temporarily unavailable")
+	case isNetworkError(err):
+		return unavailable(err, "ECR is temporarily unavailable")
+	default:
+		return newError(CodeInternal, err, "ECR request failed")
+	}
+}
+
+// isAWSErrorCode는 AWS API 에러 코드가 codes에 포함되는지 확인합니다.
+func isAWSErrorCode(apiErr smithy.APIError, codes map[string]struct{}) bool {
+	_, ok := codes[apiErr.ErrorCode()]
+	return ok
+}
+
+// classifyRegistryError는 OCI 레지스트리 에러를 서비스 에러로 변환합니다.
+// 레지스트리는 리포지토리가 없으면 NAME_UNKNOWN, 매니페스트가 없으면 MANIFEST_UNKNOWN 코드와 함께 404를 반환합니다.
+func classifyRegistryError(err error, resource string) error {
+	var transportErr *transport.Error
+	if !errors.As(err, &transportErr) {
+		if errors.Is(err, context.Canceled) {
+			return newError(CodeCanceled, err, "request was canceled")
+		}
+		if isNetworkError(err) {
+			return unavailable(err, "registry is temporarily unavailable")
+		}
+		return newError(CodeInternal, err, "registry request failed")
+	}
+
+	switch code := transportErr.StatusCode; {
+	case code == http.StatusNotFound:
+		for _, diag := range transportErr.Errors {
+			if diag.Code == transport.NameUnknownErrorCode {
+				return newError(CodeRepoNotFound, err, "repository not found: %s", resource)
+			}
+		}
+		return newError(CodeVersionNotFound, err, "chart version not found: %s", resource)
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return newError(CodeUpstreamAuth, err, "registry rejected the service credentials")
+	case code == http.StatusTooManyRequests:
+		return throttled(err, "registry request was throttled")
+	case code >= http.StatusInternalServerError:
+		return unavailable(err, "registry is temporarily unavailable")
+	default:
+		return newError(CodeInternal, err, "registry request failed")
+	}
+}
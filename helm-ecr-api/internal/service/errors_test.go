@@ -0,0 +1,195 @@
+// filepath: helm-ecr-api/internal/service/errors_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// awsResponseError는 SDK가 HTTP 응답 코드와 함께 반환하는 에러를 만듭니다.
+func awsResponseError(status int) error {
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      errors.New("response error"),
+		},
+	}
+}
+
+// credentialsFailure는 credentialsProvider가 자격 증명 조회 실패를 감싼 에러를 SDK와 같은 방식으로 다시 감싸 반환합니다.
+func credentialsFailure(cause error) error {
+	p := credentialsProvider{aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, cause
+	})}
+	_, err := p.Retrieve(context.Background())
+	return fmt.Errorf("operation error ECR: DescribeImages, get identity: %w", err)
+}
+
+func TestClassifyAWSError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantCode       ErrorCode
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:     "repository not found",
+			err:      &types.RepositoryNotFoundException{Message: aws.String("not found")},
+			wantCode: CodeRepoNotFound,
+		},
+		{
+			name:     "image not found",
+			err:      fmt.Errorf("operation DescribeImages: %w", &types.ImageNotFoundException{}),
+			wantCode: CodeVersionNotFound,
+		},
+		{
+			name:           "throttling",
+			err:            &smithy.GenericAPIError{Code: "ThrottlingException"},
+			wantCode:       CodeUpstreamThrottled,
+			wantRetryAfter: throttledRetryAfter,
+		},
+		{
+			name:     "access denied",
+			err:      &smithy.GenericAPIError{Code: "AccessDeniedException"},
+			wantCode: CodeUpstreamAuth,
+		},
+		{
+			name:     "expired token",
+			err:      &smithy.GenericAPIError{Code: "ExpiredTokenException"},
+			wantCode: CodeUpstreamAuth,
+		},
+		{
+			name:     "missing credentials",
+			err:      credentialsFailure(errors.New("no EC2 IMDS role found")),
+			wantCode: CodeUpstreamAuth,
+		},
+		{
+			// 자격 증명 에러 타입이 아니면 메시지가 같아도 자격 증명 에러로 분류하지 않습니다.
+			name:     "credentials message without type",
+			err:      errors.New("operation error ECR: DescribeImages, failed to retrieve credentials"),
+			wantCode: CodeInternal,
+		},
+		{
+			name:     "canceled",
+			err:      fmt.Errorf("operation error ECR: DescribeImages: %w", context.Canceled),
+			wantCode: CodeCanceled,
+		},
+		{
+			// 자격 증명 조회 중 요청이 취소되면 자격 증명 문제가 아니므로 취소로 분류합니다.
+			name:     "canceled while retrieving credentials",
+			err:      credentialsFailure(context.Canceled),
+			wantCode: CodeCanceled,
+		},
+		{
+			name:           "server error",
+			err:            awsResponseError(http.StatusServiceUnavailable),
+			wantCode:       CodeUpstreamUnavailable,
+			wantRetryAfter: unavailableRetryAfter,
+		},
+		{
+			name:     "client error",
+			err:      awsResponseError(http.StatusBadRequest),
+			wantCode: CodeInternal,
+		},
+		{
+			name:           "connection refused",
+			err:            &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			wantCode:       CodeUpstreamUnavailable,
+			wantRetryAfter: unavailableRetryAfter,
+		},
+		{
+			name:           "deadline exceeded",
+			err:            fmt.Errorf("request canceled: %w", context.DeadlineExceeded),
+			wantCode:       CodeUpstreamUnavailable,
+			wantRetryAfter: unavailableRetryAfter,
+		},
+		{
+			name:     "unknown API error",
+			err:      &smithy.GenericAPIError{Code: "InvalidParameterException", Message: "secret detail"},
+			wantCode: CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyAWSError(tt.err, "charts/app")
+			if got := ErrorCodeOf(err); got != tt.wantCode {
+				t.Errorf("code = %s, want %s", got, tt.wantCode)
+			}
+			if got := RetryAfterOf(err); got != tt.wantRetryAfter {
+				t.Errorf("retry after = %s, want %s", got, tt.wantRetryAfter)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classified error does not wrap the original error")
+			}
+			// 분류하지 못한 에러의 원본 메시지는 클라이언트에 노출하지 않습니다.
+			if tt.wantCode == CodeInternal && PublicMessage(err) != "internal server error" {
+				t.Errorf("public message = %q", PublicMessage(err))
+			}
+		})
+	}
+}
+
+func TestClassifyRegistryErrorCanceled(t *testing.T) {
+	cause := fmt.Errorf("GET https://registry/v2/charts/app/manifests/1.0.0: %w", context.Canceled)
+	err := classifyRegistryError(cause, "charts/app")
+	if got := ErrorCodeOf(err); got != CodeCanceled {
+		t.Errorf("code = %s, want %s", got, CodeCanceled)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("classified error does not wrap the original error")
+	}
+}
+
+// TestErrorCodeOfCanceled는 분류되지 않았거나 다른 코드로 감싼 취소 에러도 CodeCanceled로 보고하는지 확인합니다.
+func TestErrorCodeOfCanceled(t *testing.T) {
+	for _, err := range []error{
+		context.Canceled,
+		fmt.Errorf("render chart: %w", context.Canceled),
+		newError(CodeInternal, context.Canceled, "failed to read chart layer"),
+	} {
+		if got := ErrorCodeOf(err); got != CodeCanceled {
+			t.Errorf("ErrorCodeOf(%v) = %s, want %s", err, got, CodeCanceled)
+		}
+		if got := PublicMessage(err); got != "request was canceled" {
+			t.Errorf("PublicMessage(%v) = %q", err, got)
+		}
+	}
+}
+
+// TestNewECRServiceCredentialsError는 자격 증명 공급자의 실패가 ECR 호출을 거쳐 CodeUpstreamAuth로 분류되는지 확인합니다.
+func TestNewECRServiceCredentialsError(t *testing.T) {
+	cfg := aws.Config{
+		Region: "us-east-1",
+		Credentials: aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{}, errors.New("no EC2 IMDS role found")
+		})),
+	}
+	s, err := NewECRService(cfg, []string{"charts/app"}, WithRetry(RetryPolicy{MaxAttempts: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = s.DescribeHelmChart(context.Background(), "charts/app", "1.0.0", "")
+	if got := ErrorCodeOf(err); got != CodeUpstreamAuth {
+		t.Errorf("code = %s, want %s (error: %v)", got, CodeUpstreamAuth, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = s.DescribeHelmChart(ctx, "charts/app", "1.0.0", "")
+	if got := ErrorCodeOf(err); got != CodeCanceled {
+		t.Errorf("canceled request code = %s, want %s (error: %v)", got, CodeCanceled, err)
+	}
+}
@@ -0,0 +1,153 @@
+// filepath: helm-ecr-api/internal/service/image_events.go
+package service
+
+import (
+	"context"
+	"helm-ecr-api/internal/events"
+	"helm-ecr-api/internal/store"
+	"helm-ecr-api/internal/tracing"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ApplyImageAction은 ECR 이미지 푸시/삭제 이벤트를 카탈로그와 캐시에 반영합니다.
+// 전체 동기화를 기다리지 않고 이벤트의 다이제스트 하나만 DescribeImages로 다시 조회하므로,
+// latest처럼 다른 버전으로 옮겨지는 태그도 몇 초 안에 카탈로그에 반영됩니다.
+// 허용되지 않은 리포지토리의 이벤트는 무시하며, ECR 조회에 실패하면 이벤트를 다시 받을 수 있도록 에러를 반환합니다.
+func (s *ECRService) ApplyImageAction(ctx context.Context, action events.ImageAction) (err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.ApplyImageAction", trace.WithAttributes(
+		attribute.String("chart.repository", action.Repository),
+		attribute.String("chart.tag", action.Tag),
+		attribute.String("chart.digest", action.Digest),
+		attribute.String("event.action", action.Action),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !s.isRepoAllowed(action.Repository) {
+		return nil
+	}
+
+	// 서명 태그(sha256-<hex>.sig)가 푸시되거나 삭제되면 대상 차트의 서명을 다시 확인하도록 캐시에서 제거합니다.
+	if subject, ok := signatureSubject(action.Tag); ok {
+		if policy := s.signatures.Load(); policy != nil {
+			policy.invalidate(action.Repository + "@" + subject)
+		}
+	}
+
+	if s.catalog == nil {
+		return nil
+	}
+	return s.refreshCatalogImage(ctx, action.Repository, action.Digest)
+}
+
+// signatureSubject는 cosign 서명 태그에서 서명 대상 매니페스트 다이제스트를 구합니다. (signatureTag의 역)
+func signatureSubject(tag string) (string, bool) {
+	rest, ok := strings.CutSuffix(tag, ".sig")
+	if !ok {
+		return "", false
+	}
+	algorithm, hex, ok := strings.Cut(rest, "-")
+	if !ok || algorithm != "sha256" || hex == "" {
+		return "", false
+	}
+	return algorithm + ":" + hex, true
+}
+
+// refreshCatalogImage는 다이제스트 하나를 ECR에서 다시 조회하여 카탈로그의 리포지토리 레코드에 반영합니다.
+// 이미지가 삭제되었으면 레코드에서 제거하고, 새 이미지로 옮겨진 태그는 다른 버전에서 제거합니다.
+// 레코드의 SyncedAt은 전체 목록을 조회한 시각이므로 바꾸지 않습니다.
+// 동시에 진행 중인 전체 동기화가 이보다 먼저 조회한 목록으로 레코드를 덮어쓸 수 있지만, 다음 동기화에서 바로잡힙니다.
+func (s *ECRService) refreshCatalogImage(ctx context.Context, repoName, digest string) error {
+	c := s.catalog
+	c.mu.RLock()
+	prev, ok := c.repos[repoName]
+	c.mu.RUnlock()
+	if !ok {
+		// 카탈로그에 없는 리포지토리(새로 만든 리포지토리 등)는 리포지토리 정보가 필요하므로 전체 동기화를 요청합니다.
+		c.requestSync()
+		return nil
+	}
+	if digest == "" {
+		return s.syncRepository(ctx, prev.Repository)
+	}
+
+	var detail *types.ImageDetail
+	details, err := s.describeImages(ctx, repoName, "", digest)
+	switch {
+	case err == nil:
+		detail = &details[0]
+	case ErrorCodeOf(err) == CodeVersionNotFound:
+		// 삭제된 이미지
+	case ErrorCodeOf(err) == CodeRepoNotFound:
+		s.removeCatalogRepository(repoName)
+		return nil
+	default:
+		return err
+	}
+
+	var md *store.ChartMetadata
+	if detail != nil && isChartImage(*detail) {
+		for _, v := range prev.Versions {
+			if aws.ToString(v.Image.ImageDigest) == digest {
+				md = v.Chart
+				break
+			}
+		}
+		if md == nil && !c.isSkipped(repoName, digest) {
+			md = s.loadChartMetadata(ctx, repoName, digest)
+		}
+	}
+
+	c.mu.Lock()
+	cur, ok := c.repos[repoName]
+	if !ok {
+		// 그 사이 전체 동기화가 리포지토리를 제거했습니다.
+		c.mu.Unlock()
+		return nil
+	}
+	next := &store.RepositoryRecord{
+		Repository: cur.Repository,
+		Versions:   make([]store.VersionRecord, 0, len(cur.Versions)+1),
+		SyncedAt:   cur.SyncedAt,
+	}
+	for _, v := range cur.Versions {
+		if aws.ToString(v.Image.ImageDigest) == digest {
+			continue
+		}
+		// 태그는 한 이미지에만 붙을 수 있으므로 새 이미지로 옮겨진 태그를 이전 이미지에서 제거합니다.
+		if detail != nil && len(detail.ImageTags) > 0 {
+			v.Image.ImageTags = slices.DeleteFunc(slices.Clone(v.Image.ImageTags), func(tag string) bool {
+				return slices.Contains(detail.ImageTags, tag)
+			})
+		}
+		next.Versions = append(next.Versions, v)
+	}
+	if detail != nil {
+		next.Versions = append(next.Versions, store.VersionRecord{Image: *detail, Chart: md})
+	}
+	c.repos[repoName] = next
+	c.mu.Unlock()
+
+	s.persistCatalog("put", func(st *store.Store) error { return st.PutRepository(repoName, next) })
+	s.notifyNewTags(repoName, cur, next)
+	return nil
+}
+
+// removeCatalogRepository는 삭제된 리포지토리를 카탈로그에서 제거합니다.
+func (s *ECRService) removeCatalogRepository(repoName string) {
+	c := s.catalog
+	c.mu.Lock()
+	delete(c.repos, repoName)
+	for key := range c.skipped {
+		if strings.HasPrefix(key, repoName+"@") {
+			delete(c.skipped, key)
+		}
+	}
+	c.mu.Unlock()
+	s.persistCatalog("delete", func(st *store.Store) error { return st.DeleteRepository(repoName) })
+}
@@ -0,0 +1,232 @@
+// filepath: helm-ecr-api/internal/service/image_verify.go
+package service
+
+import (
+	"context"
+	"errors"
+	"helm-ecr-api/internal/logging"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/tracing"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 이미지 확인 결과 값
+const (
+	ImageStatusFound   = "found"   // ECR에 이미지가 있음
+	ImageStatusMissing = "missing" // ECR에 리포지토리 또는 태그/다이제스트가 없음
+	ImageStatusSkipped = "skipped" // ECR 이미지가 아니거나 다른 리전 또는 허용하지 않은 계정의 이미지라서 확인하지 않음
+	ImageStatusInvalid = "invalid" // 이미지 참조 형식이 올바르지 않음
+	ImageStatusError   = "error"   // 일시적 장애나 권한 부족으로 확인하지 못함
+)
+
+// imageCheckConcurrency는 이미지 확인을 위해 동시에 호출할 ECR API 수입니다.
+const imageCheckConcurrency = 4
+
+// ecrHostPattern은 ECR 레지스트리 주소(<계정 ID>.dkr.ecr.<리전>.amazonaws.com)입니다.
+var ecrHostPattern = regexp.MustCompile(`^(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// ImageCheck는 이미지 하나를 ECR에서 확인한 결과입니다.
+type ImageCheck struct {
+	Status   string     `json:"status"`
+	Reason   string     `json:"reason,omitempty"`
+	Digest   string     `json:"digest,omitempty"`
+	PushedAt *time.Time `json:"pushedAt,omitempty"`
+	Scan     *ImageScan `json:"scan,omitempty"`
+}
+
+// ImageScan은 ECR 이미지 스캔 결과 요약입니다.
+type ImageScan struct {
+	// Status는 ECR의 스캔 상태(COMPLETE, IN_PROGRESS, FAILED 등)이며, 스캔 결과가 없으면 NOT_SCANNED입니다.
+	Status        string           `json:"status"`
+	CompletedAt   *time.Time       `json:"completedAt,omitempty"`
+	FindingCounts map[string]int32 `json:"findingCounts,omitempty"` // 심각도(CRITICAL, HIGH 등)별 취약점 수
+}
+
+// ImageVerification은 이미지 확인 결과를 상태별로 집계한 값입니다.
+type ImageVerification struct {
+	Found   int `json:"found"`
+	Missing int `json:"missing"`
+	Skipped int `json:"skipped"`
+	Invalid int `json:"invalid"`
+	Errors  int `json:"errors"`
+}
+
+// VerifyChartImages는 ListChartImages로 찾은 이미지가 ECR에 실제로 있는지 DescribeImages로 확인하여
+// 각 이미지의 ECR 필드와 Verification 요약을 채웁니다. scanFindings가 true이면 DescribeImageScanFindings로
+// 심각도별 취약점 수도 함께 조회합니다.
+//
+// 이미지별 확인 실패는 해당 이미지의 결과(error)로만 기록하고 전체 요청은 실패시키지 않습니다.
+// 서버와 같은 리전의 ECR 이미지(또는 registry.host로 지정한 레지스트리의 이미지)만 확인합니다.
+// 차트의 이미지 주소는 차트 작성자가 정하므로, 서버의 계정과 WithImageVerifyAccounts로 허용한 계정이 아닌 이미지는
+// 조회하지 않고 skipped로 표시합니다. 허용한 다른 계정의 이미지는 리포지토리 정책이 서버의 IAM 역할에 조회를 허용해야 확인할 수 있습니다.
+func (s *ECRService) VerifyChartImages(ctx context.Context, images *ChartImages, scanFindings bool) (err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.VerifyChartImages", trace.WithAttributes(
+		attribute.String("chart.repository", images.Repository),
+		attribute.Int("chart.image_count", len(images.Images)),
+		attribute.Bool("image.scan_findings", scanFindings),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, imageCheckConcurrency)
+	for i := range images.Images {
+		ref := &images.Images[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			ref.ECR = s.checkImage(ctx, ref.Image, scanFindings)
+		}()
+	}
+	wg.Wait()
+
+	summary := &ImageVerification{}
+	for _, ref := range images.Images {
+		switch ref.ECR.Status {
+		case ImageStatusFound:
+			summary.Found++
+		case ImageStatusMissing:
+			summary.Missing++
+		case ImageStatusSkipped:
+			summary.Skipped++
+		case ImageStatusInvalid:
+			summary.Invalid++
+		default:
+			summary.Errors++
+		}
+	}
+	images.Verification = summary
+
+	logging.FromContext(ctx).Debug("verified chart images", "repo", images.Repository,
+		"found", summary.Found, "missing", summary.Missing, "skipped", summary.Skipped, "errors", summary.Errors)
+	return nil
+}
+
+// checkImage는 이미지 참조 하나를 ECR에서 확인합니다.
+func (s *ECRService) checkImage(ctx context.Context, image string, scanFindings bool) *ImageCheck {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return &ImageCheck{Status: ImageStatusInvalid, Reason: "invalid image reference"}
+	}
+
+	// 서버가 사용하는 레지스트리 주소이면 서버의 계정, 아니면 주소에 포함된 계정의 ECR에서 조회합니다.
+	var registryID *string
+	host := ref.Context().RegistryStr()
+	if s.registryHost == "" || host != s.registryHost {
+		m := ecrHostPattern.FindStringSubmatch(host)
+		switch {
+		case m == nil:
+			return &ImageCheck{Status: ImageStatusSkipped, Reason: "not an ECR image"}
+		case m[2] != s.awsCfg.Region:
+			return &ImageCheck{Status: ImageStatusSkipped, Reason: "image is in another region: " + m[2]}
+		}
+		if _, ok := s.imageVerifyAccounts[m[1]]; !ok {
+			accountID, err := s.getAccountID(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Warn("failed to verify chart image", "image", image, "error", err)
+				return &ImageCheck{Status: ImageStatusError, Reason: PublicMessage(err)}
+			}
+			if m[1] != accountID {
+				return &ImageCheck{Status: ImageStatusSkipped, Reason: "image is in an account that is not allowed: " + m[1]}
+			}
+		}
+		registryID = aws.String(m[1])
+	}
+
+	repoName := ref.Context().RepositoryStr()
+	imageID := types.ImageIdentifier{}
+	if d, ok := ref.(name.Digest); ok {
+		imageID.ImageDigest = aws.String(d.DigestStr())
+	} else {
+		imageID.ImageTag = aws.String(ref.Identifier())
+	}
+
+	var result *ecr.DescribeImagesOutput
+	err = s.retry(ctx, "ecr.DescribeImages", func(ctx context.Context) (err error) {
+		start := time.Now()
+		result, err = s.client.DescribeImages(ctx, &ecr.DescribeImagesInput{
+			RegistryId:     registryID,
+			RepositoryName: aws.String(repoName),
+			ImageIds:       []types.ImageIdentifier{imageID},
+		})
+		metrics.ObserveUpstream("ecr.DescribeImages", start, err)
+		if err != nil {
+			return classifyAWSError(err, image)
+		}
+		return nil
+	})
+	switch {
+	case ErrorCodeOf(err) == CodeRepoNotFound:
+		return &ImageCheck{Status: ImageStatusMissing, Reason: "repository not found: " + repoName}
+	case ErrorCodeOf(err) == CodeVersionNotFound:
+		return &ImageCheck{Status: ImageStatusMissing, Reason: "image not found: " + ref.Identifier()}
+	case err != nil:
+		logging.FromContext(ctx).Warn("failed to verify chart image", "image", image, "error", err)
+		return &ImageCheck{Status: ImageStatusError, Reason: PublicMessage(err)}
+	case len(result.ImageDetails) == 0:
+		return &ImageCheck{Status: ImageStatusMissing, Reason: "image not found: " + ref.Identifier()}
+	}
+
+	detail := result.ImageDetails[0]
+	check := &ImageCheck{
+		Status:   ImageStatusFound,
+		Digest:   aws.ToString(detail.ImageDigest),
+		PushedAt: detail.ImagePushedAt,
+	}
+	if scanFindings {
+		check.Scan = s.imageScan(ctx, registryID, repoName, check.Digest)
+	}
+	return check
+}
+
+// imageScan은 이미지의 스캔 결과 요약을 조회합니다. 조회에 실패하면 nil을 반환합니다.
+// 심각도별 집계만 필요하므로 개별 취약점은 한 건만 요청합니다.
+func (s *ECRService) imageScan(ctx context.Context, registryID *string, repoName, digest string) *ImageScan {
+	var result *ecr.DescribeImageScanFindingsOutput
+	err := s.retry(ctx, "ecr.DescribeImageScanFindings", func(ctx context.Context) (err error) {
+		start := time.Now()
+		result, err = s.client.DescribeImageScanFindings(ctx, &ecr.DescribeImageScanFindingsInput{
+			RegistryId:     registryID,
+			RepositoryName: aws.String(repoName),
+			ImageId:        &types.ImageIdentifier{ImageDigest: aws.String(digest)},
+			MaxResults:     aws.Int32(1),
+		})
+		metrics.ObserveUpstream("ecr.DescribeImageScanFindings", start, err)
+		if err != nil {
+			return classifyAWSError(err, repoName+"@"+digest)
+		}
+		return nil
+	})
+	// 스캔하지 않은 이미지는 ScanNotFoundException을 반환합니다.
+	var notFound *types.ScanNotFoundException
+	if errors.As(err, &notFound) {
+		return &ImageScan{Status: "NOT_SCANNED"}
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to get image scan findings", "repo", repoName, "digest", digest, "error", err)
+		return nil
+	}
+
+	scan := &ImageScan{}
+	if result.ImageScanStatus != nil {
+		scan.Status = string(result.ImageScanStatus.Status)
+	}
+	if findings := result.ImageScanFindings; findings != nil {
+		scan.CompletedAt = findings.ImageScanCompletedAt
+		scan.FindingCounts = findings.FindingSeverityCounts
+	}
+	return scan
+}
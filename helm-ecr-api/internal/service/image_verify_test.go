@@ -0,0 +1,103 @@
+// filepath: helm-ecr-api/internal/service/image_verify_test.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// fakeECR는 ECR API 요청을 기록하고 모든 리포지토리가 없다고 응답하는 HTTP 클라이언트입니다.
+// STS 등 다른 서비스 요청은 네트워크 에러로 실패시킵니다.
+type fakeECR struct {
+	mu          sync.Mutex
+	registryIDs []string // DescribeImages 요청의 registryId (없으면 빈 문자열)
+}
+
+func (f *fakeECR) Do(req *http.Request) (*http.Response, error) {
+	if !strings.HasPrefix(req.Header.Get("X-Amz-Target"), "AmazonEC2ContainerRegistry") {
+		return nil, errors.New("unexpected request to " + req.URL.Host)
+	}
+	var input struct {
+		RegistryID string `json:"registryId"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.registryIDs = append(f.registryIDs, input.RegistryID)
+	f.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader(`{"__type":"RepositoryNotFoundException","message":"repository does not exist"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestVerifyChartImagesAccounts(t *testing.T) {
+	const (
+		ownAccount     = "111111111111"
+		allowedAccount = "222222222222"
+		otherAccount   = "333333333333"
+	)
+	ecrImage := func(account, region string) string {
+		return account + ".dkr.ecr." + region + ".amazonaws.com/team/app:1.0"
+	}
+
+	tests := []struct {
+		name           string
+		image          string
+		ownAccount     string // 비어 있으면 계정 ID 조회(STS)가 실패
+		wantStatus     string
+		wantRegistryID string // ECR을 조회해야 하면 요청의 registryId
+	}{
+		{name: "own account", image: ecrImage(ownAccount, "us-east-1"), ownAccount: ownAccount, wantStatus: ImageStatusMissing, wantRegistryID: ownAccount},
+		{name: "allowed account", image: ecrImage(allowedAccount, "us-east-1"), ownAccount: ownAccount, wantStatus: ImageStatusMissing, wantRegistryID: allowedAccount},
+		// 허용한 계정은 서버의 계정 ID를 조회하지 않고 확인합니다.
+		{name: "allowed account without the caller identity", image: ecrImage(allowedAccount, "us-east-1"), wantStatus: ImageStatusMissing, wantRegistryID: allowedAccount},
+		{name: "other account", image: ecrImage(otherAccount, "us-east-1"), ownAccount: ownAccount, wantStatus: ImageStatusSkipped},
+		{name: "other account FIPS endpoint", image: otherAccount + ".dkr.ecr-fips.us-east-1.amazonaws.com/team/app:1.0", ownAccount: ownAccount, wantStatus: ImageStatusSkipped},
+		{name: "caller identity unavailable", image: ecrImage(otherAccount, "us-east-1"), wantStatus: ImageStatusError},
+		{name: "other region", image: ecrImage(ownAccount, "eu-west-1"), ownAccount: ownAccount, wantStatus: ImageStatusSkipped},
+		{name: "not ECR", image: "docker.io/library/nginx:1.25", ownAccount: ownAccount, wantStatus: ImageStatusSkipped},
+		{name: "invalid reference", image: "Nginx:1.25", ownAccount: ownAccount, wantStatus: ImageStatusInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeECR{}
+			s, err := NewECRService(aws.Config{Region: "us-east-1", Credentials: aws.AnonymousCredentials{}, HTTPClient: client}, nil,
+				WithRetry(RetryPolicy{MaxAttempts: 1}),
+				WithImageVerifyAccounts([]string{allowedAccount}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.accountID = tt.ownAccount
+
+			images := &ChartImages{Images: []ImageRef{{Image: tt.image}}}
+			if err := s.VerifyChartImages(context.Background(), images, false); err != nil {
+				t.Fatal(err)
+			}
+			if got := images.Images[0].ECR; got.Status != tt.wantStatus {
+				t.Errorf("status = %s (%s), want %s", got.Status, got.Reason, tt.wantStatus)
+			}
+
+			var want []string
+			if tt.wantRegistryID != "" {
+				want = []string{tt.wantRegistryID}
+			}
+			if strings.Join(client.registryIDs, ",") != strings.Join(want, ",") {
+				t.Errorf("DescribeImages registryIds = %v, want %v", client.registryIDs, want)
+			}
+		})
+	}
+}
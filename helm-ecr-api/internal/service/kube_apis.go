@@ -0,0 +1,142 @@
+// filepath: helm-ecr-api/internal/service/kube_apis.go
+package service
+
+import (
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// deprecatedAPI는 지원 중단(deprecated)되었거나 제거된 Kubernetes API 버전입니다.
+// 버전은 "1.22"처럼 major.minor 형식입니다.
+type deprecatedAPI struct {
+	APIVersion   string
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	Replacement  string // 대신 사용할 apiVersion (없으면 빈 문자열)
+}
+
+// deprecatedAPIs는 Helm 차트에서 흔히 사용하는 리소스의 지원 중단·제거 일정입니다.
+// 참조: https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var deprecatedAPIs = []deprecatedAPI{
+	// v1.16에서 제거
+	{"extensions/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "1.10", "1.16", "policy/v1beta1"},
+	{"apps/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+
+	// v1.22에서 제거
+	{"extensions/v1beta1", "Ingress", "1.14", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "1.19", "1.22", "coordination.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "1.14", "1.22", "scheduling.k8s.io/v1"},
+
+	// v1.25에서 제거
+	{"batch/v1beta1", "CronJob", "1.21", "1.25", "batch/v1"},
+	{"policy/v1beta1", "PodDisruptionBudget", "1.21", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "1.21", "1.25", ""},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "1.22", "1.25", "autoscaling/v2"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "1.19", "1.25", "events.k8s.io/v1"},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "1.20", "1.25", "node.k8s.io/v1"},
+
+	// v1.26에서 제거
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "1.23", "1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+
+	// v1.27에서 제거
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.24", "1.27", "storage.k8s.io/v1"},
+
+	// v1.29에서 제거
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+
+	// v1.32에서 제거
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// findDeprecatedAPI는 apiVersion과 kind에 해당하는 지원 중단 정보를 반환합니다.
+func findDeprecatedAPI(apiVersion, kind string) (deprecatedAPI, bool) {
+	for _, api := range deprecatedAPIs {
+		if api.APIVersion == apiVersion && api.Kind == kind {
+			return api, true
+		}
+	}
+	return deprecatedAPI{}, false
+}
+
+// minorVersion은 "1.22" 형식의 버전을 비교할 수 있도록 파싱합니다. deprecatedAPIs의 값은 항상 올바른 형식입니다.
+func minorVersion(v string) *semver.Version {
+	return semver.MustParse(v)
+}
+
+// targetVersion은 대상 클러스터 버전의 major.minor입니다. 패치 버전과 배포판 접미사(-eks-... 등)는 API 제공 여부와 관계없습니다.
+func targetVersion(kv *chartutil.KubeVersion) *semver.Version {
+	return semver.MustParse(kv.Major + "." + kv.Minor)
+}
+
+// removedIn은 대상 버전에서 API가 더 이상 제공되지 않는지 반환합니다.
+func (api deprecatedAPI) removedIn(target *semver.Version) bool {
+	return !target.LessThan(minorVersion(api.RemovedIn))
+}
+
+// deprecatedIn은 대상 버전에서 API가 지원 중단 상태인지 반환합니다.
+func (api deprecatedAPI) deprecatedIn(target *semver.Version) bool {
+	return !target.LessThan(minorVersion(api.DeprecatedIn))
+}
+
+// parseKubeVersion은 "1.30", "v1.30.2"와 같은 대상 클러스터 버전을 파싱합니다.
+func parseKubeVersion(version string) (*chartutil.KubeVersion, error) {
+	kv, err := chartutil.ParseKubeVersion(version)
+	if err != nil {
+		return nil, newError(CodeInvalidArgument, err, "invalid kubeVersion: %q", version)
+	}
+	return kv, nil
+}
+
+// kubeCapabilities는 대상 클러스터 버전으로 렌더링하기 위한 .Capabilities 값입니다.
+// .Capabilities.APIVersions.Has로 API를 선택하는 차트가 대상 버전에서 제거된 API를 고르지 않도록
+// 해당 버전에서 제거된 "<apiVersion>/<kind>"를 API 목록에서 빼고, 종류(kind)가 모두 제거된 "<apiVersion>"도 뺍니다.
+func kubeCapabilities(kv *chartutil.KubeVersion) *chartutil.Capabilities {
+	caps := chartutil.DefaultCapabilities.Copy()
+	caps.KubeVersion = *kv
+
+	target := targetVersion(kv)
+	removed := make(map[string]bool)
+	served := make(map[string]bool) // 제거되지 않은 종류가 남아 있는 apiVersion
+	for _, api := range deprecatedAPIs {
+		if api.removedIn(target) {
+			removed[api.APIVersion+"/"+api.Kind] = true
+			removed[api.APIVersion] = true
+		} else {
+			served[api.APIVersion] = true
+		}
+	}
+	versions := make(chartutil.VersionSet, 0, len(caps.APIVersions))
+	for _, v := range caps.APIVersions {
+		if !removed[v] || served[v] {
+			versions = append(versions, v)
+		}
+	}
+	caps.APIVersions = versions
+	return caps
+}
@@ -0,0 +1,112 @@
+// filepath: helm-ecr-api/internal/service/kube_apis_test.go
+package service
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestDeprecatedAPIRemovedIn(t *testing.T) {
+	cronJob, ok := findDeprecatedAPI("batch/v1beta1", "CronJob")
+	if !ok {
+		t.Fatal("batch/v1beta1 CronJob is not in deprecatedAPIs")
+	}
+
+	tests := []struct {
+		target         string
+		wantDeprecated bool
+		wantRemoved    bool
+	}{
+		{"1.20", false, false},
+		{"1.21", true, false},
+		{"1.24", true, false},
+		{"1.25", true, true},
+		{"1.30", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			target := semver.MustParse(tt.target)
+			if got := cronJob.deprecatedIn(target); got != tt.wantDeprecated {
+				t.Errorf("deprecatedIn(%s) = %v, want %v", tt.target, got, tt.wantDeprecated)
+			}
+			if got := cronJob.removedIn(target); got != tt.wantRemoved {
+				t.Errorf("removedIn(%s) = %v, want %v", tt.target, got, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+// TestDeprecatedAPIsVersions는 deprecatedAPIs의 버전이 minorVersion으로 파싱되고 제거 버전이 지원 중단 버전보다 늦은지 확인합니다.
+func TestDeprecatedAPIsVersions(t *testing.T) {
+	for _, api := range deprecatedAPIs {
+		if !minorVersion(api.DeprecatedIn).LessThan(minorVersion(api.RemovedIn)) {
+			t.Errorf("%s %s: deprecated in %s but removed in %s", api.APIVersion, api.Kind, api.DeprecatedIn, api.RemovedIn)
+		}
+	}
+}
+
+func TestKubeCapabilities(t *testing.T) {
+	tests := []struct {
+		version string
+		present []string
+		absent  []string
+	}{
+		{
+			version: "1.21.3",
+			present: []string{"policy/v1beta1", "batch/v1beta1", "extensions/v1beta1", "policy/v1"},
+		},
+		{
+			version: "v1.22.0",
+			present: []string{"policy/v1beta1", "batch/v1beta1", "networking.k8s.io/v1"},
+			absent:  []string{"extensions/v1beta1", "networking.k8s.io/v1beta1", "extensions/v1beta1/Ingress"},
+		},
+		{
+			// policy/v1beta1의 PodDisruptionBudget과 PodSecurityPolicy가 모두 제거되었으므로 그룹 버전도 빠져야 합니다.
+			version: "1.25",
+			present: []string{"policy/v1", "batch/v1", "autoscaling/v2beta2"},
+			absent:  []string{"policy/v1beta1", "batch/v1beta1", "autoscaling/v2beta1", "policy/v1beta1/PodDisruptionBudget"},
+		},
+		{
+			version: "1.32.1-eks-1234",
+			present: []string{"policy/v1", "apps/v1"},
+			absent:  []string{"flowcontrol.apiserver.k8s.io/v1beta3", "autoscaling/v2beta2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			kv, err := parseKubeVersion(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			caps := kubeCapabilities(kv)
+			if caps.KubeVersion.Version != kv.Version {
+				t.Errorf("KubeVersion = %s, want %s", caps.KubeVersion.Version, kv.Version)
+			}
+			for _, v := range tt.present {
+				if !caps.APIVersions.Has(v) {
+					t.Errorf("APIVersions.Has(%q) = false, want true", v)
+				}
+			}
+			for _, v := range tt.absent {
+				if caps.APIVersions.Has(v) {
+					t.Errorf("APIVersions.Has(%q) = true, want false", v)
+				}
+			}
+		})
+	}
+
+	// 기본 Capabilities는 바뀌지 않아야 합니다.
+	if !chartutil.DefaultCapabilities.APIVersions.Has("policy/v1beta1") {
+		t.Error("kubeCapabilities modified chartutil.DefaultCapabilities")
+	}
+}
+
+func TestParseKubeVersionInvalid(t *testing.T) {
+	_, err := parseKubeVersion("latest")
+	if err == nil || ErrorCodeOf(err) != CodeInvalidArgument {
+		t.Fatalf("error = %v, want %s", err, CodeInvalidArgument)
+	}
+}
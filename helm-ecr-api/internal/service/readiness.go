@@ -0,0 +1,153 @@
+// filepath: helm-ecr-api/internal/service/readiness.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"helm-ecr-api/internal/metrics"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+// 준비 상태 점검 결과 값
+const (
+	CheckStatusOK   = "ok"
+	CheckStatusFail = "fail"
+)
+
+// ReadinessChecker는 서비스가 요청을 처리할 준비가 되었는지 점검하는 인터페이스입니다.
+type ReadinessChecker interface {
+	CheckReadiness(ctx context.Context) ReadinessReport
+}
+
+// ReadinessReport는 준비 상태 점검 결과입니다.
+type ReadinessReport struct {
+	Status    string        `json:"status"` // 모든 점검이 성공하면 "ok", 하나라도 실패하면 "fail"
+	CheckedAt time.Time     `json:"checkedAt"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// CheckResult는 개별 점검 항목의 결과입니다.
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Ready는 모든 점검이 성공했는지 반환합니다.
+func (r ReadinessReport) Ready() bool {
+	return r.Status == CheckStatusOK
+}
+
+// CheckReadiness는 ECR에서 차트를 제공할 수 있는 상태인지 점검합니다.
+// Kubernetes readinessProbe가 짧은 주기로 호출하므로, 결과를 readinessTTL 동안 캐시하여 AWS API 호출을 줄입니다.
+// 점검 항목:
+//   - credentials: AWS 자격 증명을 가져올 수 있는지
+//   - sts: STS GetCallerIdentity로 계정 ID를 조회할 수 있는지
+//   - ecr_token: ECR 인증 토큰을 발급받을 수 있는지 (익명 레지스트리 설정 시 생략)
+//   - repositories: 정확한 이름으로 지정된 리포지토리가 모두 존재하는지 (패턴과 태그 기반 허용 목록은 제외)
+func (s *ECRService) CheckReadiness(ctx context.Context) ReadinessReport {
+	// 동시에 들어온 점검 요청은 하나의 점검 결과를 공유합니다.
+	s.readinessMu.Lock()
+	defer s.readinessMu.Unlock()
+
+	if s.readiness != nil && time.Since(s.readiness.CheckedAt) < s.readinessTTL {
+		return *s.readiness
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.readinessTimeout)
+	defer cancel()
+
+	report := ReadinessReport{
+		Status:    CheckStatusOK,
+		CheckedAt: time.Now(),
+	}
+
+	checks := []struct {
+		name string
+		fn   func(context.Context) error
+	}{
+		{"credentials", s.checkCredentials},
+		{"sts", func(ctx context.Context) error {
+			_, err := s.getAccountID(ctx)
+			return err
+		}},
+		{"ecr_token", s.checkECRToken},
+		{"repositories", s.checkRepositories},
+	}
+
+	for _, check := range checks {
+		start := time.Now()
+		err := check.fn(ctx)
+
+		result := CheckResult{
+			Name:       check.name,
+			Status:     CheckStatusOK,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			result.Status = CheckStatusFail
+			result.Error = err.Error()
+			report.Status = CheckStatusFail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	if !report.Ready() {
+		s.logger.Warn("readiness check failed", "checks", report.Checks)
+	}
+
+	s.readiness = &report
+	return report
+}
+
+// checkCredentials는 AWS 자격 증명을 가져올 수 있는지 확인합니다.
+func (s *ECRService) checkCredentials(ctx context.Context) error {
+	if s.awsCfg.Credentials == nil {
+		return errors.New("no AWS credentials provider configured")
+	}
+	if _, err := s.awsCfg.Credentials.Retrieve(ctx); err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	return nil
+}
+
+// checkECRToken은 ECR 인증 토큰을 발급받을 수 있는지 확인합니다.
+func (s *ECRService) checkECRToken(ctx context.Context) error {
+	if s.registryAnonymous {
+		return nil
+	}
+	_, err := s.getECRAuthToken(ctx)
+	return err
+}
+
+// checkRepositories는 정확한 이름으로 지정된 리포지토리가 모두 존재하는지 확인합니다.
+// 어떤 리포지토리가 없는지 알 수 있도록 리포지토리마다 따로 조회합니다.
+func (s *ECRService) checkRepositories(ctx context.Context) error {
+	var missing []string
+	for _, repoName := range s.allowedRepos.Load().ExactNames() {
+		start := time.Now()
+		_, err := s.client.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+			RepositoryNames: []string{repoName},
+		})
+		metrics.ObserveUpstream("ecr.DescribeRepositories", start, err)
+
+		var notFound *types.RepositoryNotFoundException
+		if errors.As(err, &notFound) {
+			missing = append(missing, repoName)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to describe repository %s: %w", repoName, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("repositories not found: %v", missing)
+	}
+	return nil
+}
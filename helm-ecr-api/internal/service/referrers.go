@@ -0,0 +1,194 @@
+// filepath: helm-ecr-api/internal/service/referrers.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"helm-ecr-api/internal/logging"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/tracing"
+	"io"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ChartReferrers는 차트 매니페스트를 subject로 참조하는 아티팩트(SBOM, 서명, 증명 등) 목록입니다.
+type ChartReferrers struct {
+	Repository string     `json:"repository"`
+	Digest     string     `json:"digest"`
+	Referrers  []Referrer `json:"referrers"`
+}
+
+// Referrer는 차트에 첨부된 아티팩트 하나의 매니페스트 정보입니다.
+type Referrer struct {
+	Digest       string            `json:"digest"`
+	MediaType    string            `json:"mediaType"`
+	ArtifactType string            `json:"artifactType,omitempty"` // 예: application/spdx+json, application/vnd.in-toto+json
+	Size         int64             `json:"size"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// ReferrerContent는 첨부된 아티팩트의 레이어(문서) 내용입니다. 호출자는 Body를 닫아야 합니다.
+type ReferrerContent struct {
+	Body      io.ReadCloser
+	MediaType string
+	Size      int64
+	Digest    string // 레이어 다이제스트
+	Subject   string // 아티팩트가 참조하는 차트 매니페스트 다이제스트
+}
+
+// ListReferrers는 OCI 레퍼러 API로 차트 매니페스트에 첨부된 아티팩트 목록을 조회합니다.
+// 레지스트리가 레퍼러 API를 지원하지 않으면 go-containerregistry가 태그 스키마(sha256-<hex> 태그의 인덱스)로 대신 조회합니다.
+// artifactType을 지정하면 해당 유형의 아티팩트만 반환합니다.
+func (s *ECRService) ListReferrers(ctx context.Context, repoName, tag, digest, artifactType string) (_ *ChartReferrers, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.ListReferrers", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("chart.tag", tag),
+		attribute.String("chart.digest", digest),
+		attribute.String("oci.artifact_type", artifactType),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !s.isRepoAllowed(repoName) {
+		return nil, newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	ref, err := s.chartReference(ctx, repoName, tag, digest)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := s.registryAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 레퍼러 API는 subject가 없어도 빈 목록을 반환하므로, 존재하지 않는 차트 버전은 HEAD 요청으로 먼저 404를 반환합니다.
+	manifestDigest, err := s.resolveTag(ctx, ref, auth)
+	if err != nil {
+		return nil, err
+	}
+	subject := ref.Context().Digest(manifestDigest)
+
+	opts := s.remoteOptions(ctx, auth)
+	if artifactType != "" {
+		opts = append(opts, remote.WithFilter("artifactType", artifactType))
+	}
+	var index *v1.IndexManifest
+	err = s.retry(ctx, "registry.Referrers", func(ctx context.Context) (err error) {
+		start := time.Now()
+		defer func() { metrics.ObserveUpstream("registry.Referrers", start, err) }()
+
+		idx, err := remote.Referrers(subject, opts...)
+		if err != nil {
+			return registryError(subject, err)
+		}
+		index, err = idx.IndexManifest()
+		if err != nil {
+			return registryError(subject, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ChartReferrers{
+		Repository: repoName,
+		Digest:     manifestDigest,
+		Referrers:  make([]Referrer, 0, len(index.Manifests)),
+	}
+	for _, desc := range index.Manifests {
+		result.Referrers = append(result.Referrers, Referrer{
+			Digest:       desc.Digest.String(),
+			MediaType:    string(desc.MediaType),
+			ArtifactType: desc.ArtifactType,
+			Size:         desc.Size,
+			Annotations:  desc.Annotations,
+		})
+	}
+
+	logging.FromContext(ctx).Debug("listed chart referrers", "repo", repoName, "digest", manifestDigest, "count", len(result.Referrers))
+	span.SetAttributes(attribute.Int("oci.referrer_count", len(result.Referrers)))
+	return result, nil
+}
+
+// GetReferrerContent는 첨부된 아티팩트 매니페스트의 layer번째 레이어(SBOM, in-toto 증명 등 문서)를 반환합니다.
+// subject가 없는 매니페스트(차트 자체 등)는 첨부된 아티팩트가 아니므로 CodeVersionNotFound를 반환합니다.
+// 레이어 크기가 파일 최대 크기를 넘으면 CodeFileTooLarge를 반환합니다.
+func (s *ECRService) GetReferrerContent(ctx context.Context, repoName, referrerDigest string, layer int) (_ *ReferrerContent, err error) {
+	ctx, span := tracer.Start(ctx, "ECRService.GetReferrerContent", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("oci.referrer_digest", referrerDigest),
+		attribute.Int("oci.layer", layer),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !s.isRepoAllowed(repoName) {
+		return nil, newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+	if _, err := v1.NewHash(referrerDigest); err != nil {
+		return nil, newError(CodeInvalidArgument, err, "invalid digest: %s", referrerDigest)
+	}
+
+	ctx, cancel := s.withUpstreamTimeout(ctx)
+	defer cancel()
+
+	ref, err := s.chartReference(ctx, repoName, "", referrerDigest)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := s.registryAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var img v1.Image
+	err = s.retry(ctx, "registry.Image", func(ctx context.Context) (err error) {
+		start := time.Now()
+		img, err = remote.Image(ref, s.remoteOptions(ctx, auth)...)
+		metrics.ObserveUpstream("registry.Image", start, err)
+		if err != nil {
+			return registryError(ref, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrer manifest: %w", err)
+	}
+
+	display := chartDisplayName(repoName, "", referrerDigest)
+	if manifest.Subject == nil {
+		return nil, newError(CodeVersionNotFound, nil, "not an attached artifact: %s", display)
+	}
+	if layer < 0 || layer >= len(manifest.Layers) {
+		return nil, newError(CodeFileNotFound, nil, "artifact has no layer %d: %s", layer, display)
+	}
+	desc := manifest.Layers[layer]
+	if s.maxFileSize > 0 && desc.Size > s.maxFileSize {
+		return nil, newError(CodeFileTooLarge, nil, "artifact layer is %d bytes, exceeding the limit of %d bytes", desc.Size, s.maxFileSize)
+	}
+
+	data, err := s.downloadLayer(ctx, ref.Context().Digest(desc.Digest.String()), desc.Size, s.maxFileSize, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &ReferrerContent{
+		Body:      io.NopCloser(bytes.NewReader(data)),
+		MediaType: string(desc.MediaType),
+		Size:      int64(len(data)),
+		Digest:    desc.Digest.String(),
+		Subject:   manifest.Subject.Digest.String(),
+	}, nil
+}
@@ -0,0 +1,102 @@
+// filepath: helm-ecr-api/internal/service/repo_pattern.go
+package service
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// repoMatcher는 허용된 리포지토리 이름과 패턴을 관리합니다.
+// - 정확한 이름(예: "my-charts/app1")은 빠른 조회를 위해 map에 저장합니다.
+// - '*'는 경로 구분자('/')를 제외한 임의의 문자열과 일치합니다. (예: "helm-charts/*")
+// - '**' 세그먼트는 0개 이상의 경로 세그먼트와 일치합니다. (예: "team-a/**")
+type repoMatcher struct {
+	exact    map[string]struct{}
+	patterns [][]string // '/'로 분리된 패턴 세그먼트 목록
+}
+
+// newRepoMatcher는 HELM_REPOSITORIES 항목들로부터 repoMatcher를 생성합니다.
+// 잘못된 패턴이 포함되어 있으면 에러를 반환합니다.
+func newRepoMatcher(entries []string) (*repoMatcher, error) {
+	m := &repoMatcher{
+		exact: make(map[string]struct{}, len(entries)),
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// 패턴 문자가 없으면 정확한 이름으로 취급합니다.
+		if !strings.ContainsAny(entry, "*?[") {
+			m.exact[entry] = struct{}{}
+			continue
+		}
+
+		segments := strings.Split(entry, "/")
+		for _, seg := range segments {
+			if seg == "**" {
+				continue
+			}
+			// path.Match는 패턴 문법이 잘못된 경우에만 ErrBadPattern을 반환합니다.
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, fmt.Errorf("invalid repository pattern %q: %w", entry, err)
+			}
+		}
+		m.patterns = append(m.patterns, segments)
+	}
+
+	return m, nil
+}
+
+// ExactNames는 패턴이 아닌 정확한 이름으로 지정된 리포지토리 목록을 반환합니다.
+func (m *repoMatcher) ExactNames() []string {
+	names := make([]string, 0, len(m.exact))
+	for name := range m.exact {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Match는 리포지토리 이름이 허용 목록의 이름 또는 패턴과 일치하는지 확인합니다.
+func (m *repoMatcher) Match(repoName string) bool {
+	if _, ok := m.exact[repoName]; ok {
+		return true
+	}
+
+	nameSegments := strings.Split(repoName, "/")
+	for _, pattern := range m.patterns {
+		if matchSegments(pattern, nameSegments) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments는 패턴 세그먼트와 이름 세그먼트를 재귀적으로 비교합니다.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// '**'는 남은 이름 세그먼트 중 0개 이상을 소비할 수 있습니다.
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
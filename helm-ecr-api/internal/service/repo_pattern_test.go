@@ -0,0 +1,132 @@
+// filepath: helm-ecr-api/internal/service/repo_pattern_test.go
+package service
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRepoMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		match   []string
+		noMatch []string
+	}{
+		{
+			name:    "exact names",
+			entries: []string{"charts/app", " charts/web ", ""},
+			match:   []string{"charts/app", "charts/web"},
+			noMatch: []string{"charts", "charts/app/sub", "charts/ap", "other/app"},
+		},
+		{
+			name:    "single segment glob",
+			entries: []string{"charts/*"},
+			match:   []string{"charts/app", "charts/a.b-c"},
+			noMatch: []string{"charts", "charts/team/app", "other/app"},
+		},
+		{
+			name:    "trailing double star",
+			entries: []string{"team-a/**"},
+			match:   []string{"team-a", "team-a/app", "team-a/x/y/z"},
+			noMatch: []string{"team-ab/app", "team-b/app", "x/team-a/app"},
+		},
+		{
+			name:    "double star in the middle",
+			entries: []string{"org/**/charts/*"},
+			match:   []string{"org/charts/app", "org/a/charts/app", "org/a/b/charts/app"},
+			noMatch: []string{"org/charts", "org/a/charts", "org/a/charts/app/x", "other/charts/app"},
+		},
+		{
+			name:    "leading double star",
+			entries: []string{"**/app"},
+			match:   []string{"app", "charts/app", "a/b/app"},
+			noMatch: []string{"charts/app2", "app/x"},
+		},
+		{
+			name:    "partial segment glob and character class",
+			entries: []string{"charts/app-?", "charts/[bc]*"},
+			match:   []string{"charts/app-1", "charts/backend", "charts/cache"},
+			noMatch: []string{"charts/app-10", "charts/api", "charts/b/x"},
+		},
+		{
+			// 메타 문자가 포함된 항목은 패턴으로 해석하므로, 문자 그대로 일치시키려면 '\'로 이스케이프합니다.
+			name:    "escaped metacharacters",
+			entries: []string{`charts/\*`, `charts/\[x]`},
+			match:   []string{"charts/*", "charts/[x]"},
+			noMatch: []string{"charts/app", "charts/x"},
+		},
+		{
+			name:    "character class is not a literal name",
+			entries: []string{"charts/[abc]"},
+			match:   []string{"charts/a", "charts/c"},
+			noMatch: []string{"charts/[abc]", "charts/abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newRepoMatcher(tt.entries)
+			if err != nil {
+				t.Fatalf("newRepoMatcher() error = %v", err)
+			}
+			for _, name := range tt.match {
+				if !m.Match(name) {
+					t.Errorf("Match(%q) = false, want true", name)
+				}
+			}
+			for _, name := range tt.noMatch {
+				if m.Match(name) {
+					t.Errorf("Match(%q) = true, want false", name)
+				}
+			}
+		})
+	}
+}
+
+func TestRepoMatcherExactNames(t *testing.T) {
+	m, err := newRepoMatcher([]string{"charts/web", "charts/*", "charts/app", "team/**"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.ExactNames(), []string{"charts/app", "charts/web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExactNames() = %v, want %v", got, want)
+	}
+}
+
+func TestRepoMatcherInvalidPattern(t *testing.T) {
+	for _, entry := range []string{"charts/[a", `charts/app*\`, "charts/[]", "**/[z-a"} {
+		t.Run(entry, func(t *testing.T) {
+			_, err := newRepoMatcher([]string{"charts/ok", entry})
+			if !errors.Is(err, path.ErrBadPattern) {
+				t.Errorf("newRepoMatcher(%q) error = %v, want path.ErrBadPattern", entry, err)
+			}
+		})
+	}
+}
+
+// TestNewECRServiceInvalidPattern은 잘못된 패턴이 있으면 서버가 시작되지 않도록 서비스 생성이 실패하는지 확인합니다.
+func TestNewECRServiceInvalidPattern(t *testing.T) {
+	_, err := NewECRService(aws.Config{Region: "us-east-1"}, []string{"charts/[a"})
+	if !errors.Is(err, path.ErrBadPattern) {
+		t.Fatalf("NewECRService() error = %v, want path.ErrBadPattern", err)
+	}
+}
+
+// TestSetAllowedReposKeepsPrevious는 리로드한 목록에 잘못된 패턴이 있으면 기존 목록을 유지하는지 확인합니다.
+func TestSetAllowedReposKeepsPrevious(t *testing.T) {
+	s, err := NewECRService(aws.Config{Region: "us-east-1"}, []string{"charts/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetAllowedRepos([]string{"other/*", "charts/[a"}); err == nil {
+		t.Fatal("SetAllowedRepos() accepted an invalid pattern")
+	}
+	if !s.isRepoAllowed("charts/app") || s.isRepoAllowed("other/app") {
+		t.Error("allow-list changed after a rejected reload")
+	}
+}
@@ -0,0 +1,67 @@
+// filepath: helm-ecr-api/internal/service/retry.go
+package service
+
+import (
+	"context"
+	"helm-ecr-api/internal/logging"
+	"helm-ecr-api/internal/metrics"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy는 멱등한 업스트림 조회 호출의 재시도 정책입니다.
+// 요청 제한(upstream_throttled)과 일시적 장애(upstream_unavailable)로 분류된 에러만 재시도합니다.
+type RetryPolicy struct {
+	MaxAttempts    int           // 첫 시도를 포함한 최대 시도 횟수 (1이면 재시도하지 않음)
+	InitialBackoff time.Duration // 첫 재시도 전 최대 대기 시간
+	MaxBackoff     time.Duration // 재시도 간 최대 대기 시간
+}
+
+// defaultRetryPolicy는 WithRetry를 지정하지 않았을 때 사용하는 정책입니다.
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// backoff는 attempt번째 재시도 전에 기다릴 시간을 "full jitter" 방식으로 계산합니다.
+// 여러 인스턴스가 동시에 재시도하여 요청 제한이 악화되지 않도록 [0, min(max, initial*2^attempt)) 범위에서 무작위로 선택합니다.
+// 참조: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.InitialBackoff << attempt
+	if ceiling <= 0 || ceiling > p.MaxBackoff {
+		ceiling = p.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// retry는 fn이 재시도 가능한 에러를 반환하면 정책에 따라 다시 호출합니다.
+// fn은 서비스 에러로 분류된 에러를 반환해야 하며, 상태를 변경하지 않는 조회 호출에만 사용해야 합니다.
+// ctx가 취소되면 즉시 마지막 에러를 반환합니다.
+func (s *ECRService) retry(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	maxAttempts := max(s.retryPolicy.MaxAttempts, 1)
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err = fn(ctx); err == nil || !isRetryable(err) || attempt == maxAttempts-1 {
+			return err
+		}
+
+		wait := s.retryPolicy.backoff(attempt)
+		logging.FromContext(ctx).Debug("retrying upstream call",
+			"operation", operation, "attempt", attempt+1, "backoff", wait, "error", err)
+		metrics.IncUpstreamRetry(operation)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
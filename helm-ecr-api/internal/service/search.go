@@ -0,0 +1,276 @@
+// filepath: helm-ecr-api/internal/service/search.go
+package service
+
+import (
+	"context"
+	"helm-ecr-api/internal/store"
+	"helm-ecr-api/internal/tracing"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 검색 결과 수 제한
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	maxSearchQueryLen  = 200
+	maxValuesMatches   = 10 // 결과 하나에 표시할 values 키 일치 항목의 최대 수
+)
+
+// searchNotReadyRetryAfter는 카탈로그의 첫 동기화가 끝나지 않았을 때 클라이언트에 알려 줄 재시도 대기 시간입니다.
+const searchNotReadyRetryAfter = 30 * time.Second
+
+// 검색 필드별 점수. 차트 이름이 일치하는 결과를 values 키만 일치하는 결과보다 앞에 둡니다.
+const (
+	scoreNameExact      = 100
+	scoreName           = 40
+	scoreRepository     = 20
+	scoreKeywordExact   = 30
+	scoreKeyword        = 15
+	scoreValuesKeyExact = 20
+	scoreValuesKey      = 10
+	scoreDescription    = 10
+	scoreMaintainer     = 10
+)
+
+// SearchMatch.Field 값
+const (
+	searchFieldName       = "name"
+	searchFieldRepo       = "repository"
+	searchFieldKeyword    = "keywords"
+	searchFieldDesc       = "description"
+	searchFieldMaintainer = "maintainers"
+	searchFieldValuesKey  = "values"
+)
+
+// SearchQuery는 차트 검색 조건입니다.
+type SearchQuery struct {
+	Text        string // 공백으로 구분한 검색어. 모든 검색어가 일치하는 차트 버전만 반환합니다. (대소문자 구분 없음)
+	Limit       int    // 최대 결과 수 (0이면 DefaultSearchLimit)
+	AllVersions bool   // false이면 리포지토리별로 검색어와 일치하는 최신 버전 하나만 반환합니다.
+}
+
+// SearchResults는 차트 검색 결과입니다.
+type SearchResults struct {
+	Query   string            `json:"query"`
+	Total   int               `json:"total"` // Limit을 적용하기 전의 결과 수
+	Results []SearchHit       `json:"results"`
+	Index   SearchIndexStatus `json:"index"`
+}
+
+// SearchHit는 검색어와 일치하는 차트 버전입니다.
+type SearchHit struct {
+	Repository  string        `json:"repository"`
+	Digest      string        `json:"digest"`
+	Tags        []string      `json:"tags,omitempty"`
+	Chart       string        `json:"chart"`
+	Version     string        `json:"version"`
+	AppVersion  string        `json:"appVersion,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Score       int           `json:"score"`
+	Matches     []SearchMatch `json:"matches"`
+}
+
+// SearchMatch는 검색어가 일치한 필드와 값입니다. (예: {"field": "values", "value": "podDisruptionBudget.enabled"})
+type SearchMatch struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// SearchIndexStatus는 검색에 사용한 카탈로그의 상태입니다.
+type SearchIndexStatus struct {
+	Repositories int       `json:"repositories"`
+	Versions     int       `json:"versions"`
+	UpdatedAt    time.Time `json:"updatedAt"` // 마지막 전체 동기화를 마친 시각
+}
+
+// SearchCharts는 카탈로그에서 차트 이름, 설명, 키워드, 관리자, values 키를 검색합니다.
+// 카탈로그는 백그라운드에서 동기화되므로 최근에 푸시한 차트는 다음 동기화까지 검색되지 않을 수 있습니다.
+// 카탈로그와 검색 색인(WithSearchIndex)이 모두 없거나 첫 동기화를 마치지 않았으면 CodeSearchUnavailable 에러를 반환합니다.
+func (s *ECRService) SearchCharts(ctx context.Context, query SearchQuery) (_ *SearchResults, err error) {
+	_, span := tracer.Start(ctx, "ECRService.SearchCharts", trace.WithAttributes(
+		attribute.String("search.query", query.Text),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	terms := strings.Fields(strings.ToLower(query.Text))
+	switch {
+	case len(terms) == 0:
+		return nil, newError(CodeInvalidArgument, nil, "search query is required")
+	case len(query.Text) > maxSearchQueryLen:
+		return nil, newError(CodeInvalidArgument, nil, "search query must not exceed %d characters", maxSearchQueryLen)
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		return nil, newError(CodeInvalidArgument, nil, "limit must not exceed %d", MaxSearchLimit)
+	}
+
+	c := s.catalog
+	if c == nil {
+		return nil, newError(CodeSearchUnavailable, nil, "chart search is disabled")
+	}
+
+	c.mu.RLock()
+	if c.syncedAt.IsZero() {
+		c.mu.RUnlock()
+		e := newError(CodeSearchUnavailable, nil, "catalog is being built, retry later")
+		e.RetryAfter = searchNotReadyRetryAfter
+		return nil, e
+	}
+	status := SearchIndexStatus{UpdatedAt: c.syncedAt}
+	var hits []SearchHit
+	for repoName, rec := range c.repos {
+		// 설정 리로드로 허용 목록에서 빠진 리포지토리는 다음 동기화 전이라도 검색하지 않습니다.
+		if !s.isRepoAllowed(repoName) {
+			continue
+		}
+		status.Repositories++
+
+		var repoHits []SearchHit
+		var latest *store.VersionRecord
+		for i := range rec.Versions {
+			v := &rec.Versions[i]
+			if v.Chart == nil {
+				continue
+			}
+			status.Versions++
+			hit, ok := matchVersion(repoName, v, terms)
+			if !ok {
+				continue
+			}
+			if query.AllVersions {
+				repoHits = append(repoHits, hit)
+			} else if latest == nil || newerVersion(v, latest) {
+				latest = v
+				repoHits = []SearchHit{hit}
+			}
+		}
+		hits = append(hits, repoHits...)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return compareVersions(a.Version, b.Version) > 0
+	})
+
+	results := &SearchResults{Query: query.Text, Total: len(hits), Index: status}
+	results.Results = hits[:min(limit, len(hits))]
+	if results.Results == nil {
+		results.Results = []SearchHit{}
+	}
+	span.SetAttributes(attribute.Int("search.total", results.Total))
+	return results, nil
+}
+
+// matchVersion은 모든 검색어가 차트 버전의 어느 필드와 일치하는지 확인하고 점수를 계산합니다.
+func matchVersion(repoName string, v *store.VersionRecord, terms []string) (SearchHit, bool) {
+	md := v.Chart
+	hit := SearchHit{
+		Repository:  repoName,
+		Digest:      aws.ToString(v.Image.ImageDigest),
+		Tags:        v.Image.ImageTags,
+		Chart:       md.Name,
+		Version:     md.Version,
+		AppVersion:  md.AppVersion,
+		Description: md.Description,
+	}
+	seen := make(map[SearchMatch]struct{})
+	valuesMatches := 0
+	add := func(field, value string, score int) {
+		m := SearchMatch{Field: field, Value: value}
+		if _, ok := seen[m]; ok {
+			return
+		}
+		if field == searchFieldValuesKey {
+			if valuesMatches >= maxValuesMatches {
+				return
+			}
+			valuesMatches++
+		}
+		seen[m] = struct{}{}
+		hit.Matches = append(hit.Matches, m)
+		hit.Score += score
+	}
+
+	for _, term := range terms {
+		matched := false
+		match := func(field, value string, exact, partial int) {
+			lower := strings.ToLower(value)
+			switch {
+			case exact > 0 && lower == term:
+				add(field, value, exact)
+			case strings.Contains(lower, term):
+				add(field, value, partial)
+			default:
+				return
+			}
+			matched = true
+		}
+
+		match(searchFieldName, md.Name, scoreNameExact, scoreName)
+		match(searchFieldRepo, repoName, 0, scoreRepository)
+		for _, k := range md.Keywords {
+			match(searchFieldKeyword, k, scoreKeywordExact, scoreKeyword)
+		}
+		match(searchFieldDesc, md.Description, 0, scoreDescription)
+		for _, m := range md.Maintainers {
+			match(searchFieldMaintainer, m, 0, scoreMaintainer)
+		}
+		for _, k := range md.ValuesKeys {
+			// 키 경로의 마지막 부분이 검색어와 같으면 정확히 일치한 것으로 봅니다. (예: "replicas"는 "autoscaling.maxReplicas"와 부분 일치, "worker.replicas"와 정확히 일치)
+			lower := strings.ToLower(k)
+			last := lower[strings.LastIndexAny(lower, ".]")+1:]
+			switch {
+			case last == term:
+				add(searchFieldValuesKey, k, scoreValuesKeyExact)
+			case strings.Contains(lower, term):
+				add(searchFieldValuesKey, k, scoreValuesKey)
+			default:
+				continue
+			}
+			matched = true
+		}
+
+		if !matched {
+			return SearchHit{}, false
+		}
+	}
+	return hit, true
+}
+
+// newerVersion은 a가 b보다 새 차트 버전인지 반환합니다. 버전이 semver가 아니면 푸시 시각으로 비교합니다.
+func newerVersion(a, b *store.VersionRecord) bool {
+	if c := compareVersions(a.Chart.Version, b.Chart.Version); c != 0 {
+		return c > 0
+	}
+	return aws.ToTime(a.Image.ImagePushedAt).After(aws.ToTime(b.Image.ImagePushedAt))
+}
+
+// compareVersions는 두 semver 버전을 비교합니다. 어느 한쪽이라도 semver가 아니면 0을 반환합니다.
+func compareVersions(a, b string) int {
+	va, err := semver.NewVersion(a)
+	if err != nil {
+		return 0
+	}
+	vb, err := semver.NewVersion(b)
+	if err != nil {
+		return 0
+	}
+	return va.Compare(vb)
+}
@@ -0,0 +1,168 @@
+// filepath: helm-ecr-api/internal/service/search_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"helm-ecr-api/internal/store"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+func TestMatchVersion(t *testing.T) {
+	record := &store.VersionRecord{
+		Image: types.ImageDetail{ImageDigest: aws.String("sha256:abc"), ImageTags: []string{"1.0.0"}},
+		Chart: &store.ChartMetadata{
+			Name:        "nginx",
+			Version:     "1.0.0",
+			Description: "Ingress controller",
+			Keywords:    []string{"web", "proxy"},
+			Maintainers: []string{"platform-team"},
+			ValuesKeys:  []string{"autoscaling.maxReplicas", "worker.replicas"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		terms     []string
+		wantOK    bool
+		wantScore int
+		want      []SearchMatch
+	}{
+		{
+			name:      "exact name",
+			terms:     []string{"nginx"},
+			wantOK:    true,
+			wantScore: scoreNameExact + scoreRepository,
+			want:      []SearchMatch{{searchFieldName, "nginx"}, {searchFieldRepo, "charts/nginx"}},
+		},
+		{
+			name:      "partial name",
+			terms:     []string{"ngi"},
+			wantOK:    true,
+			wantScore: scoreName + scoreRepository,
+			want:      []SearchMatch{{searchFieldName, "nginx"}, {searchFieldRepo, "charts/nginx"}},
+		},
+		{
+			name:      "exact keyword and description",
+			terms:     []string{"web", "ingress"},
+			wantOK:    true,
+			wantScore: scoreKeywordExact + scoreDescription,
+			want:      []SearchMatch{{searchFieldKeyword, "web"}, {searchFieldDesc, "Ingress controller"}},
+		},
+		{
+			name:      "maintainer",
+			terms:     []string{"platform"},
+			wantOK:    true,
+			wantScore: scoreMaintainer,
+			want:      []SearchMatch{{searchFieldMaintainer, "platform-team"}},
+		},
+		{
+			name:      "values key matches the last segment exactly",
+			terms:     []string{"replicas"},
+			wantOK:    true,
+			wantScore: scoreValuesKey + scoreValuesKeyExact,
+			want:      []SearchMatch{{searchFieldValuesKey, "autoscaling.maxReplicas"}, {searchFieldValuesKey, "worker.replicas"}},
+		},
+		{
+			name:      "same match is counted once",
+			terms:     []string{"nginx", "nginx"},
+			wantOK:    true,
+			wantScore: scoreNameExact + scoreRepository,
+			want:      []SearchMatch{{searchFieldName, "nginx"}, {searchFieldRepo, "charts/nginx"}},
+		},
+		{
+			name:   "every term must match",
+			terms:  []string{"nginx", "redis"},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := matchVersion("charts/nginx", record, tt.terms)
+			if ok != tt.wantOK {
+				t.Fatalf("matched = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if hit.Score != tt.wantScore {
+				t.Errorf("score = %d, want %d", hit.Score, tt.wantScore)
+			}
+			if !reflect.DeepEqual(hit.Matches, tt.want) {
+				t.Errorf("matches = %v, want %v", hit.Matches, tt.want)
+			}
+			if hit.Digest != "sha256:abc" || hit.Chart != "nginx" || hit.Version != "1.0.0" {
+				t.Errorf("hit = %+v", hit)
+			}
+		})
+	}
+}
+
+// TestMatchVersionValuesLimit는 values 키가 많이 일치해도 결과 하나에 maxValuesMatches개까지만 표시하는지 확인합니다.
+func TestMatchVersionValuesLimit(t *testing.T) {
+	md := &store.ChartMetadata{Name: "app"}
+	for i := 0; i < maxValuesMatches+5; i++ {
+		md.ValuesKeys = append(md.ValuesKeys, fmt.Sprintf("sidecar%d.image", i))
+	}
+	hit, ok := matchVersion("charts/app", &store.VersionRecord{Chart: md}, []string{"image"})
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if len(hit.Matches) != maxValuesMatches || hit.Score != maxValuesMatches*scoreValuesKeyExact {
+		t.Errorf("got %d matches with score %d, want %d matches", len(hit.Matches), hit.Score, maxValuesMatches)
+	}
+}
+
+// TestSearchIndexWithoutCatalog는 카탈로그 없이 검색 색인만 있을 때 검색은 가능하고, 목록과 상세 조회에는 색인을 사용하지 않는지 확인합니다.
+func TestSearchIndexWithoutCatalog(t *testing.T) {
+	s, err := NewECRService(aws.Config{Region: "us-east-1"}, []string{"charts/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SearchCharts(context.Background(), SearchQuery{Text: "nginx"}); ErrorCodeOf(err) != CodeSearchUnavailable {
+		t.Fatalf("search without an index: error = %v, want %s", err, CodeSearchUnavailable)
+	}
+
+	s, err = NewECRService(aws.Config{Region: "us-east-1"}, []string{"charts/*"}, WithSearchIndex(time.Minute, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 첫 동기화 전에는 잠시 뒤에 다시 시도하도록 안내합니다.
+	_, err = s.SearchCharts(context.Background(), SearchQuery{Text: "nginx"})
+	var e *Error
+	if !errors.As(err, &e) || e.Code != CodeSearchUnavailable || e.RetryAfter == 0 {
+		t.Fatalf("search before the first sync: error = %v, want %s with Retry-After", err, CodeSearchUnavailable)
+	}
+
+	now := time.Now()
+	s.catalog.repos["charts/nginx"] = &store.RepositoryRecord{
+		Repository: types.Repository{RepositoryName: aws.String("charts/nginx")},
+		SyncedAt:   now,
+		Versions: []store.VersionRecord{{
+			Image: types.ImageDetail{ImageDigest: aws.String("sha256:abc"), ImageTags: []string{"1.0.0"}},
+			Chart: &store.ChartMetadata{Name: "nginx", Version: "1.0.0"},
+		}},
+	}
+	s.catalog.syncedAt = now
+
+	res, err := s.SearchCharts(context.Background(), SearchQuery{Text: "nginx"})
+	if err != nil {
+		t.Fatalf("SearchCharts() error = %v", err)
+	}
+	if res.Total != 1 || res.Results[0].Digest != "sha256:abc" {
+		t.Errorf("SearchCharts() = %+v", res)
+	}
+	if _, _, ok := s.catalogRepositories(); ok {
+		t.Error("repository list was served from the search index")
+	}
+	if _, _, ok := s.catalogImages("charts/nginx", "1.0.0", ""); ok {
+		t.Error("chart details were served from the search index")
+	}
+}
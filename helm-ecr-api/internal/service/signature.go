@@ -0,0 +1,359 @@
+// filepath: helm-ecr-api/internal/service/signature.go
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"helm-ecr-api/internal/logging"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/signature"
+	"helm-ecr-api/internal/tracing"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SignatureMode는 차트 서명 검증 모드입니다.
+type SignatureMode string
+
+const (
+	SignatureOff     SignatureMode = "off"     // 서명을 확인하지 않음
+	SignatureVerify  SignatureMode = "verify"  // 서명을 확인하여 결과만 알려 줌
+	SignatureEnforce SignatureMode = "enforce" // 검증되지 않은 차트의 제공을 거절함
+)
+
+// maxSignatureCacheEntries는 서명 검증 결과 캐시의 최대 항목 수입니다.
+const maxSignatureCacheEntries = 1024
+
+// SignatureStatus는 차트 매니페스트의 서명 검증 결과입니다.
+type SignatureStatus struct {
+	Signed   bool   `json:"signed"`           // cosign 서명이 하나 이상 있음
+	Verified bool   `json:"verified"`         // 설정된 공개 키로 검증된 서명이 있음
+	Signer   string `json:"signer,omitempty"` // 검증에 성공한 공개 키 이름
+}
+
+// signaturePolicy는 검증 모드, 공개 키와 그 키로 검증한 결과의 캐시입니다.
+// 키가 바뀌면 이전 결과를 재사용할 수 없으므로 정책을 교체할 때 캐시도 함께 새로 만듭니다.
+type signaturePolicy struct {
+	mode     SignatureMode
+	verifier *signature.Verifier
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]signatureCacheEntry
+}
+
+type signatureCacheEntry struct {
+	status    SignatureStatus
+	expiresAt time.Time
+}
+
+func (p *signaturePolicy) get(key string) (SignatureStatus, bool) {
+	if p.ttl <= 0 {
+		return SignatureStatus{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return SignatureStatus{}, false
+	}
+	return entry.status, true
+}
+
+func (p *signaturePolicy) put(key string, status SignatureStatus) {
+	if p.ttl <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if len(p.cache) >= maxSignatureCacheEntries {
+		for k, entry := range p.cache {
+			if now.After(entry.expiresAt) {
+				delete(p.cache, k)
+			}
+		}
+		// 만료된 항목이 없으면 전체를 비웁니다. 다음 요청에서 다시 검증할 뿐이므로 정확한 LRU는 필요하지 않습니다.
+		if len(p.cache) >= maxSignatureCacheEntries {
+			clear(p.cache)
+		}
+	}
+	p.cache[key] = signatureCacheEntry{status: status, expiresAt: now.Add(p.ttl)}
+}
+
+// invalidate는 key의 검증 결과를 캐시에서 제거합니다.
+func (p *signaturePolicy) invalidate(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.cache, key)
+}
+
+// SetSignaturePolicy는 서명 검증 모드와 공개 키를 교체합니다. 처리 중인 요청에는 영향을 주지 않습니다.
+func (s *ECRService) SetSignaturePolicy(mode SignatureMode, keys []signature.Key) {
+	if mode == "" || mode == SignatureOff {
+		s.signatures.Store(nil)
+		return
+	}
+	s.signatures.Store(&signaturePolicy{
+		mode:     mode,
+		verifier: signature.NewVerifier(keys),
+		ttl:      s.signatureCacheTTL,
+		cache:    make(map[string]signatureCacheEntry),
+	})
+}
+
+// VerifyChartSignature는 차트 매니페스트에 cosign 방식으로 첨부된 서명을 찾아 설정된 공개 키로 검증합니다.
+// 서명은 태그 방식(sha256-<hex>.sig 태그)과 OCI 레퍼러 방식(artifactType이 signature.ArtifactType인 레퍼러)을 모두 찾습니다.
+// Notation 서명과 sigstore 번들 형식의 cosign 서명은 검증하지 않으며, 이런 서명만 있는 차트는 서명되지 않은 것으로 판단합니다.
+// 검증 모드가 off이면 nil을 반환합니다.
+//
+// enforce 모드에서는 검증된 서명이 없으면 CodeSignatureRequired 또는 CodeSignatureInvalid 에러를 반환하고,
+// 서명을 조회하지 못한 경우에도 업스트림 에러를 그대로 반환하여 차트를 제공하지 않도록 합니다.
+// verify 모드에서는 서명 조회에 실패해도 요청을 실패시키지 않고 서명되지 않은 것으로 표시합니다.
+func (s *ECRService) VerifyChartSignature(ctx context.Context, repoName, manifestDigest string) (_ *SignatureStatus, err error) {
+	policy := s.signatures.Load()
+	if policy == nil {
+		return nil, nil
+	}
+
+	ctx, span := tracer.Start(ctx, "ECRService.VerifyChartSignature", trace.WithAttributes(
+		attribute.String("chart.repository", repoName),
+		attribute.String("chart.digest", manifestDigest),
+		attribute.String("signature.mode", string(policy.mode)),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !s.isRepoAllowed(repoName) {
+		return nil, newError(CodeRepoNotAllowed, nil, "repository not allowed: %s", repoName)
+	}
+
+	cacheKey := repoName + "@" + manifestDigest
+	status, ok := policy.get(cacheKey)
+	if !ok {
+		ctx, cancel := s.withUpstreamTimeout(ctx)
+		defer cancel()
+
+		status, err = s.fetchSignatureStatus(ctx, policy.verifier, repoName, manifestDigest)
+		if err != nil {
+			if policy.mode == SignatureEnforce {
+				return nil, err
+			}
+			logging.FromContext(ctx).Warn("failed to look up chart signature", "repo", repoName, "digest", manifestDigest, "error", err)
+			return &SignatureStatus{}, nil
+		}
+		policy.put(cacheKey, status)
+	}
+
+	span.SetAttributes(
+		attribute.Bool("signature.signed", status.Signed),
+		attribute.Bool("signature.verified", status.Verified),
+	)
+	if policy.mode == SignatureEnforce && !status.Verified {
+		if !status.Signed {
+			return nil, newError(CodeSignatureRequired, nil, "chart is not signed: %s", chartDisplayName(repoName, "", manifestDigest))
+		}
+		return nil, newError(CodeSignatureInvalid, nil, "chart signature could not be verified with the configured keys: %s", chartDisplayName(repoName, "", manifestDigest))
+	}
+	return &status, nil
+}
+
+// signatureTag는 cosign이 매니페스트 다이제스트에 대한 서명을 저장하는 태그 이름입니다. (예: sha256-abcd....sig)
+func signatureTag(manifestDigest string) string {
+	return strings.Replace(manifestDigest, ":", "-", 1) + ".sig"
+}
+
+// maxSignatureReferrers는 레퍼러로 찾은 서명 매니페스트 중 검증을 시도할 최대 개수입니다.
+// 리포지토리에 푸시할 수 있으면 누구나 서명을 첨부할 수 있으므로, 서명 수만큼 레지스트리 호출이 늘어나지 않도록 제한합니다.
+const maxSignatureReferrers = 8
+
+// fetchSignatureStatus는 서명 태그의 매니페스트와 서명 레퍼러 매니페스트를 차례로 조회하여 각 서명 레이어를 검증합니다.
+// 태그 방식의 서명이 검증되면 레퍼러는 조회하지 않습니다. 둘 다 없으면 서명되지 않은 것으로 판단합니다.
+func (s *ECRService) fetchSignatureStatus(ctx context.Context, verifier *signature.Verifier, repoName, manifestDigest string) (SignatureStatus, error) {
+	if _, err := v1.NewHash(manifestDigest); err != nil {
+		return SignatureStatus{}, newError(CodeInvalidArgument, err, "invalid digest: %s", manifestDigest)
+	}
+	ref, err := s.chartReference(ctx, repoName, "", manifestDigest)
+	if err != nil {
+		return SignatureStatus{}, err
+	}
+	repo := ref.Context()
+	auth, err := s.registryAuth(ctx)
+	if err != nil {
+		return SignatureStatus{}, err
+	}
+
+	var status SignatureStatus
+	manifest, err := s.fetchSignatureManifest(ctx, repo.Tag(signatureTag(manifestDigest)), auth)
+	if err != nil {
+		return SignatureStatus{}, err
+	}
+	if manifest != nil {
+		if status, err = s.verifySignatureLayers(ctx, verifier, repo, manifestDigest, manifest, auth); err != nil || status.Verified {
+			return status, err
+		}
+	}
+
+	referrers, err := s.signatureReferrers(ctx, repo.Digest(manifestDigest), auth)
+	if err != nil {
+		return SignatureStatus{}, err
+	}
+	if len(referrers) > maxSignatureReferrers {
+		logging.FromContext(ctx).Warn("chart has too many signature referrers, verifying only the first ones",
+			"repo", repoName, "digest", manifestDigest, "count", len(referrers), "limit", maxSignatureReferrers)
+		referrers = referrers[:maxSignatureReferrers]
+	}
+	for _, desc := range referrers {
+		manifest, err := s.fetchSignatureManifest(ctx, repo.Digest(desc.Digest.String()), auth)
+		if err != nil {
+			return SignatureStatus{}, err
+		}
+		if manifest == nil {
+			continue // 목록을 조회한 뒤 삭제된 서명
+		}
+		referrerStatus, err := s.verifySignatureLayers(ctx, verifier, repo, manifestDigest, manifest, auth)
+		if err != nil {
+			return SignatureStatus{}, err
+		}
+		status.Signed = status.Signed || referrerStatus.Signed
+		if referrerStatus.Verified {
+			return referrerStatus, nil
+		}
+	}
+	return status, nil
+}
+
+// fetchSignatureManifest는 서명 매니페스트를 조회합니다. 매니페스트가 없으면 nil을 반환합니다.
+func (s *ECRService) fetchSignatureManifest(ctx context.Context, ref name.Reference, auth authn.Authenticator) (*v1.Manifest, error) {
+	var img v1.Image
+	err := s.retry(ctx, "registry.Image", func(ctx context.Context) (err error) {
+		start := time.Now()
+		img, err = remote.Image(ref, s.remoteOptions(ctx, auth)...)
+		metrics.ObserveUpstream("registry.Image", start, err)
+		if err != nil {
+			return registryError(ref, err)
+		}
+		return nil
+	})
+	switch ErrorCodeOf(err) {
+	case CodeVersionNotFound, CodeRepoNotFound:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// signatureReferrers는 OCI 레퍼러 API로 subject에 첨부된 cosign 서명 매니페스트를 찾습니다.
+// 레지스트리가 레퍼러 API를 지원하지 않으면 go-containerregistry가 태그 스키마로 대신 조회합니다.
+func (s *ECRService) signatureReferrers(ctx context.Context, subject name.Digest, auth authn.Authenticator) ([]v1.Descriptor, error) {
+	opts := append(s.remoteOptions(ctx, auth), remote.WithFilter("artifactType", signature.ArtifactType))
+	var index *v1.IndexManifest
+	err := s.retry(ctx, "registry.Referrers", func(ctx context.Context) (err error) {
+		start := time.Now()
+		defer func() { metrics.ObserveUpstream("registry.Referrers", start, err) }()
+
+		idx, err := remote.Referrers(subject, opts...)
+		if err != nil {
+			return registryError(subject, err)
+		}
+		index, err = idx.IndexManifest()
+		if err != nil {
+			return registryError(subject, err)
+		}
+		return nil
+	})
+	switch ErrorCodeOf(err) {
+	case CodeVersionNotFound, CodeRepoNotFound:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 필터를 지원하지 않는 레지스트리의 응답은 go-containerregistry가 거르지만, 다른 아티팩트를 검증하지 않도록 한 번 더 확인합니다.
+	var referrers []v1.Descriptor
+	for _, desc := range index.Manifests {
+		if desc.ArtifactType == signature.ArtifactType {
+			referrers = append(referrers, desc)
+		}
+	}
+	return referrers, nil
+}
+
+// verifySignatureLayers는 서명 매니페스트의 각 서명 레이어를 검증합니다.
+func (s *ECRService) verifySignatureLayers(ctx context.Context, verifier *signature.Verifier, repo name.Repository, manifestDigest string, manifest *v1.Manifest, auth authn.Authenticator) (SignatureStatus, error) {
+	logger := logging.FromContext(ctx)
+	var status SignatureStatus
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[signature.SignatureAnnotation]
+		if string(layer.MediaType) != signature.SimpleSigningMediaType || !ok {
+			continue
+		}
+		status.Signed = true
+
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || layer.Size > signature.MaxPayloadSize {
+			logger.Debug("skipping malformed chart signature", "repo", repo.RepositoryStr(), "layer", layer.Digest.String())
+			continue
+		}
+		payload, err := s.fetchSignaturePayload(ctx, repo.Digest(layer.Digest.String()), auth)
+		if err != nil {
+			return SignatureStatus{}, err
+		}
+		// 서명이 다른 차트의 것이면 키로 검증되더라도 인정하지 않습니다.
+		if signed, err := signature.ManifestDigest(payload); err != nil || signed != manifestDigest {
+			logger.Debug("chart signature payload does not match manifest", "repo", repo.RepositoryStr(), "digest", manifestDigest, "signed_digest", signed, "error", err)
+			continue
+		}
+		if signer, ok := verifier.Verify(payload, sig); ok {
+			status.Verified = true
+			status.Signer = signer
+			break
+		}
+	}
+	return status, nil
+}
+
+// fetchSignaturePayload는 서명 레이어(payload)를 내려받습니다.
+// go-containerregistry는 읽은 내용의 다이제스트를 검증하므로 레지스트리가 payload를 바꿔치기할 수 없습니다.
+func (s *ECRService) fetchSignaturePayload(ctx context.Context, ref name.Digest, auth authn.Authenticator) (payload []byte, err error) {
+	err = s.retry(ctx, "registry.LayerDownload", func(ctx context.Context) (err error) {
+		start := time.Now()
+		defer func() { metrics.ObserveUpstream("registry.LayerDownload", start, err) }()
+
+		layer, err := remote.Layer(ref, s.remoteOptions(ctx, auth)...)
+		if err != nil {
+			return registryError(ref, err)
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return registryError(ref, err)
+		}
+		defer rc.Close()
+
+		payload, err = io.ReadAll(io.LimitReader(rc, signature.MaxPayloadSize))
+		if err != nil {
+			return registryError(ref, fmt.Errorf("failed to read signature payload: %w", err))
+		}
+		return nil
+	})
+	return payload, err
+}
@@ -0,0 +1,293 @@
+// filepath: helm-ecr-api/internal/service/signature_test.go
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"helm-ecr-api/internal/signature"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// signatureRegistry는 레퍼러 API를 지원하는 메모리 레지스트리와 차트, 서명을 푸시하는 헬퍼입니다.
+type signatureRegistry struct {
+	t    *testing.T
+	host string
+}
+
+func newSignatureRegistry(t *testing.T) *signatureRegistry {
+	t.Helper()
+	srv := httptest.NewServer(registry.New(
+		registry.WithReferrersSupport(true),
+		registry.Logger(log.New(io.Discard, "", 0)),
+	))
+	t.Cleanup(srv.Close)
+	return &signatureRegistry{t: t, host: strings.TrimPrefix(srv.URL, "http://")}
+}
+
+func (r *signatureRegistry) ref(s string) name.Reference {
+	r.t.Helper()
+	ref, err := name.ParseReference(r.host+"/charts/app"+s, name.Insecure)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return ref
+}
+
+func (r *signatureRegistry) push(ref name.Reference, img v1.Image) {
+	r.t.Helper()
+	if err := remote.Write(ref, img); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// pushChart는 차트 대신 임의의 이미지를 푸시하고 매니페스트 디스크립터를 반환합니다.
+func (r *signatureRegistry) pushChart() v1.Descriptor {
+	r.t.Helper()
+	img, err := random.Image(64, 1)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	desc, err := partial.Descriptor(img)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	r.push(r.ref("@"+desc.Digest.String()), img)
+	return *desc
+}
+
+// pushTagSignature는 cosign 태그 방식(sha256-<hex>.sig)으로 서명 매니페스트를 푸시합니다.
+func (r *signatureRegistry) pushTagSignature(subject v1.Descriptor, img v1.Image) {
+	r.t.Helper()
+	r.push(r.ref(":"+signatureTag(subject.Digest.String())), img)
+}
+
+// pushReferrer는 subject를 참조하는 아티팩트 매니페스트를 artifactType으로 푸시합니다.
+func (r *signatureRegistry) pushReferrer(subject v1.Descriptor, artifactType types.MediaType, img v1.Image) {
+	r.t.Helper()
+	img = mutate.ConfigMediaType(img, artifactType)
+	img = mutate.Subject(img, subject).(v1.Image)
+	d, err := img.Digest()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	r.push(r.ref("@"+d.String()), img)
+}
+
+// simpleSigningPayload는 cosign이 manifestDigest에 대해 서명하는 payload입니다.
+func simpleSigningPayload(manifestDigest string) []byte {
+	return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"charts/app"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, manifestDigest))
+}
+
+// signatureImage는 payload와 그에 대한 서명 sig를 담은 cosign 서명 매니페스트를 만듭니다.
+func signatureImage(t *testing.T, payload, sig []byte) v1.Image {
+	t.Helper()
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer(payload, signature.SimpleSigningMediaType),
+		Annotations: map[string]string{signature.SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mutate.MediaType(img, types.OCIManifestSchema1)
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestVerifyChartSignature(t *testing.T) {
+	releaseKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []signature.Key{{Name: "release", PublicKey: &releaseKey.PublicKey}}
+	reg := newSignatureRegistry(t)
+
+	// 서명을 첨부하는 방식. 같은 서명 매니페스트를 태그 또는 레퍼러로 첨부합니다.
+	attach := map[string]func(subject v1.Descriptor, img v1.Image){
+		"tag": reg.pushTagSignature,
+		"referrer": func(subject v1.Descriptor, img v1.Image) {
+			reg.pushReferrer(subject, signature.ArtifactType, img)
+		},
+	}
+
+	tests := []struct {
+		name string
+		// sign은 chart에 서명을 만들어 반환합니다. nil이면 서명하지 않습니다.
+		sign        func(t *testing.T, chart v1.Descriptor) v1.Image
+		want        SignatureStatus
+		wantEnforce ErrorCode // enforce 모드의 에러 코드 (비어 있으면 성공)
+	}{
+		{
+			name:        "unsigned",
+			want:        SignatureStatus{},
+			wantEnforce: CodeSignatureRequired,
+		},
+		{
+			name: "signed",
+			sign: func(t *testing.T, chart v1.Descriptor) v1.Image {
+				payload := simpleSigningPayload(chart.Digest.String())
+				return signatureImage(t, payload, sign(t, releaseKey, payload))
+			},
+			want: SignatureStatus{Signed: true, Verified: true, Signer: "release"},
+		},
+		{
+			name: "wrong key",
+			sign: func(t *testing.T, chart v1.Descriptor) v1.Image {
+				payload := simpleSigningPayload(chart.Digest.String())
+				return signatureImage(t, payload, sign(t, otherKey, payload))
+			},
+			want:        SignatureStatus{Signed: true},
+			wantEnforce: CodeSignatureInvalid,
+		},
+		{
+			name: "tampered payload",
+			sign: func(t *testing.T, chart v1.Descriptor) v1.Image {
+				payload := simpleSigningPayload(chart.Digest.String())
+				sig := sign(t, releaseKey, payload)
+				tampered := []byte(strings.Replace(string(payload), `"optional":null`, `"optional":{"tampered":true}`, 1))
+				return signatureImage(t, tampered, sig)
+			},
+			want:        SignatureStatus{Signed: true},
+			wantEnforce: CodeSignatureInvalid,
+		},
+		{
+			// 다른 차트에 대한 유효한 서명을 복사해도 인정하지 않습니다.
+			name: "signature of another chart",
+			sign: func(t *testing.T, chart v1.Descriptor) v1.Image {
+				other := reg.pushChart()
+				payload := simpleSigningPayload(other.Digest.String())
+				return signatureImage(t, payload, sign(t, releaseKey, payload))
+			},
+			want:        SignatureStatus{Signed: true},
+			wantEnforce: CodeSignatureInvalid,
+		},
+	}
+
+	for _, scheme := range []string{"tag", "referrer"} {
+		for _, tt := range tests {
+			t.Run(scheme+"/"+tt.name, func(t *testing.T) {
+				chart := reg.pushChart()
+				if tt.sign != nil {
+					attach[scheme](chart, tt.sign(t, chart))
+				}
+
+				for _, mode := range []SignatureMode{SignatureVerify, SignatureEnforce} {
+					s, err := NewECRService(aws.Config{Region: "us-east-1"}, []string{"charts/*"},
+						WithRegistry(reg.host, true, true),
+						WithRetry(RetryPolicy{MaxAttempts: 1}),
+						WithSignatureVerification(mode, keys, 0))
+					if err != nil {
+						t.Fatal(err)
+					}
+					status, err := s.VerifyChartSignature(context.Background(), "charts/app", chart.Digest.String())
+
+					if mode == SignatureEnforce && tt.wantEnforce != "" {
+						if ErrorCodeOf(err) != tt.wantEnforce {
+							t.Errorf("%s: VerifyChartSignature() error = %v, want %s", mode, err, tt.wantEnforce)
+						}
+						continue
+					}
+					if err != nil {
+						t.Fatalf("%s: VerifyChartSignature() error = %v", mode, err)
+					}
+					if *status != tt.want {
+						t.Errorf("%s: VerifyChartSignature() = %+v, want %+v", mode, *status, tt.want)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestVerifyChartSignatureSources는 태그와 레퍼러의 서명을 함께 확인하고, 서명이 아닌 레퍼러는 무시하는지 확인합니다.
+func TestVerifyChartSignatureSources(t *testing.T) {
+	releaseKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := newSignatureRegistry(t)
+	s, err := NewECRService(aws.Config{Region: "us-east-1"}, []string{"charts/*"},
+		WithRegistry(reg.host, true, true),
+		WithRetry(RetryPolicy{MaxAttempts: 1}),
+		WithSignatureVerification(SignatureVerify, []signature.Key{{Name: "release", PublicKey: &releaseKey.PublicKey}}, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := func(t *testing.T, chart v1.Descriptor, key *ecdsa.PrivateKey) v1.Image {
+		payload := simpleSigningPayload(chart.Digest.String())
+		return signatureImage(t, payload, sign(t, key, payload))
+	}
+	verify := func(t *testing.T, chart v1.Descriptor) SignatureStatus {
+		t.Helper()
+		status, err := s.VerifyChartSignature(context.Background(), "charts/app", chart.Digest.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *status
+	}
+
+	t.Run("tag signature with another key and a valid referrer", func(t *testing.T) {
+		chart := reg.pushChart()
+		reg.pushTagSignature(chart, signed(t, chart, otherKey))
+		reg.pushReferrer(chart, signature.ArtifactType, signed(t, chart, releaseKey))
+		if got, want := verify(t, chart), (SignatureStatus{Signed: true, Verified: true, Signer: "release"}); got != want {
+			t.Errorf("VerifyChartSignature() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("several referrers", func(t *testing.T) {
+		chart := reg.pushChart()
+		reg.pushReferrer(chart, signature.ArtifactType, signed(t, chart, otherKey))
+		reg.pushReferrer(chart, signature.ArtifactType, signed(t, chart, releaseKey))
+		if got := verify(t, chart); !got.Verified {
+			t.Errorf("VerifyChartSignature() = %+v, want verified", got)
+		}
+	})
+
+	// Notation 서명 등 다른 유형의 아티팩트는 cosign 서명 레이어가 있어도 확인하지 않습니다.
+	t.Run("other artifact types", func(t *testing.T) {
+		chart := reg.pushChart()
+		reg.pushReferrer(chart, "application/vnd.cncf.notary.signature", signed(t, chart, releaseKey))
+		reg.pushReferrer(chart, "application/spdx+json", signed(t, chart, releaseKey))
+		if got := verify(t, chart); got != (SignatureStatus{}) {
+			t.Errorf("VerifyChartSignature() = %+v, want unsigned", got)
+		}
+	})
+}
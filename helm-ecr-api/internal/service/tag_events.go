@@ -0,0 +1,72 @@
+// filepath: helm-ecr-api/internal/service/tag_events.go
+package service
+
+import (
+	"helm-ecr-api/internal/store"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// TagEvent는 카탈로그를 갱신하면서 새로 발견한 차트 태그입니다.
+type TagEvent struct {
+	Repository     string
+	Tag            string
+	Digest         string
+	PreviousDigest string // 태그가 다른 버전에서 옮겨 왔으면 이전 매니페스트 다이제스트
+	PushedAt       time.Time
+	Chart          store.ChartMetadata
+}
+
+// TagListener는 새 차트 태그를 받는 함수입니다. 카탈로그 갱신을 지연시키지 않도록 바로 반환해야 합니다.
+type TagListener func(TagEvent)
+
+// WithTagListener는 카탈로그 동기화나 ECR 이미지 이벤트로 허용된 리포지토리에서 새 차트 태그를 발견하면 fn을 호출합니다.
+// 카탈로그를 사용하지 않으면 호출되지 않습니다.
+func WithTagListener(fn TagListener) Option {
+	return func(s *ECRService) {
+		s.tagListener = fn
+	}
+}
+
+// notifyNewTags는 prev에 없던 (태그, 다이제스트) 조합을 리스너에 알립니다.
+// 차트 메타데이터를 읽은 버전만 비교하므로, 메타데이터를 읽지 못한 버전은 이후 동기화에서 읽었을 때 알립니다.
+// prev가 nil이면 next의 모든 태그가 새 태그입니다.
+func (s *ECRService) notifyNewTags(repoName string, prev, next *store.RepositoryRecord) {
+	if s.tagListener == nil {
+		return
+	}
+
+	known := make(map[string]string) // 태그 → 다이제스트
+	if prev != nil {
+		for _, v := range prev.Versions {
+			if v.Chart == nil {
+				continue
+			}
+			for _, tag := range v.Image.ImageTags {
+				known[tag] = aws.ToString(v.Image.ImageDigest)
+			}
+		}
+	}
+
+	for _, v := range next.Versions {
+		if v.Chart == nil {
+			continue
+		}
+		digest := aws.ToString(v.Image.ImageDigest)
+		for _, tag := range v.Image.ImageTags {
+			previous, ok := known[tag]
+			if ok && previous == digest {
+				continue
+			}
+			s.tagListener(TagEvent{
+				Repository:     repoName,
+				Tag:            tag,
+				Digest:         digest,
+				PreviousDigest: previous,
+				PushedAt:       aws.ToTime(v.Image.ImagePushedAt),
+				Chart:          *v.Chart,
+			})
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// filepath: helm-ecr-api/internal/signature/signature.go
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// cosign 서명 형식 관련 상수
+// 참조: https://github.com/sigstore/cosign/blob/main/specs/SIGNATURE_SPEC.md
+const (
+	// SimpleSigningMediaType은 서명 대상(payload)을 담은 레이어의 미디어 타입입니다.
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureAnnotation은 payload에 대한 base64 서명을 담은 레이어 어노테이션입니다.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// ArtifactType은 cosign이 OCI 레퍼러 방식(--registry-referrers-mode=oci-1-1)으로 첨부한 서명 매니페스트의 아티팩트 유형입니다.
+	// 레이어 형식은 태그 방식(sha256-<hex>.sig)의 서명 매니페스트와 같습니다.
+	ArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	// simpleSigningType은 cosign 컨테이너 이미지 서명 payload의 type 값입니다.
+	simpleSigningType = "cosign container image signature"
+)
+
+// MaxPayloadSize는 서명 payload의 최대 크기입니다. payload는 작은 JSON이므로 이보다 큰 레이어는 읽지 않습니다.
+const MaxPayloadSize = 64 << 10
+
+// Key는 서명 검증에 사용할 이름이 붙은 공개 키입니다.
+type Key struct {
+	Name      string
+	PublicKey crypto.PublicKey
+}
+
+// LoadKey는 PEM 형식(PKIX "PUBLIC KEY")의 공개 키 파일을 읽습니다.
+// cosign generate-key-pair가 만드는 cosign.pub 파일을 그대로 사용할 수 있습니다.
+// ECDSA, RSA, Ed25519 키를 지원합니다.
+func LoadKey(name, path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to read public key %q: %w", name, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return Key{}, fmt.Errorf("public key %q: %s does not contain a PEM \"PUBLIC KEY\" block", name, path)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to parse public key %q: %w", name, err)
+	}
+	switch pub.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return Key{}, fmt.Errorf("public key %q: unsupported key type %T", name, pub)
+	}
+	return Key{Name: name, PublicKey: pub}, nil
+}
+
+// Verifier는 설정된 공개 키 중 하나로 서명을 검증합니다.
+type Verifier struct {
+	keys []Key
+}
+
+// NewVerifier는 keys로 서명을 검증하는 Verifier를 생성합니다.
+func NewVerifier(keys []Key) *Verifier {
+	return &Verifier{keys: keys}
+}
+
+// Verify는 payload에 대한 서명 sig를 검증하고, 검증에 성공한 키의 이름을 반환합니다.
+// 어떤 키로도 검증되지 않으면 false를 반환합니다.
+func (v *Verifier) Verify(payload, sig []byte) (string, bool) {
+	digest := sha256.Sum256(payload)
+	for _, key := range v.keys {
+		var ok bool
+		switch pub := key.PublicKey.(type) {
+		case *ecdsa.PublicKey:
+			ok = ecdsa.VerifyASN1(pub, digest[:], sig)
+		case *rsa.PublicKey:
+			ok = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+		case ed25519.PublicKey:
+			ok = ed25519.Verify(pub, payload, sig)
+		}
+		if ok {
+			return key.Name, true
+		}
+	}
+	return "", false
+}
+
+// simpleSigning은 cosign이 서명하는 payload(Red Hat simple signing 형식)입니다.
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// ManifestDigest는 서명 payload가 가리키는 매니페스트 다이제스트를 반환합니다.
+// 서명이 다른 매니페스트로 복사되어 재사용되는 것을 막기 위해, 검증에 성공하더라도 이 값이 대상 다이제스트와 같아야 합니다.
+func ManifestDigest(payload []byte) (string, error) {
+	var p simpleSigning
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return "", fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if p.Critical.Type != simpleSigningType {
+		return "", fmt.Errorf("unexpected signature payload type: %q", p.Critical.Type)
+	}
+	if p.Critical.Image.DockerManifestDigest == "" {
+		return "", errors.New("signature payload has no manifest digest")
+	}
+	return p.Critical.Image.DockerManifestDigest, nil
+}
@@ -0,0 +1,192 @@
+// filepath: helm-ecr-api/internal/store/store.go
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// schemaVersion은 저장 형식의 버전입니다. 레코드 구조를 바꾸면 올려야 하며,
+// 저장된 버전과 다르면 Open이 모든 데이터를 지웁니다. 저장소는 ECR의 사본이므로 다시 동기화하면 복구됩니다.
+const schemaVersion = "1"
+
+// 버킷 이름
+var (
+	bucketMeta         = []byte("meta")
+	bucketRepositories = []byte("repositories")
+	bucketSkipped      = []byte("skipped")
+
+	keySchemaVersion = []byte("schemaVersion")
+	keyLastSync      = []byte("lastSync")
+)
+
+// RepositoryRecord는 리포지토리 하나와 그 안의 모든 이미지(차트 버전)의 스냅숏입니다.
+// 리포지토리 단위로 한꺼번에 교체하므로 리포지토리 안의 버전 목록은 항상 같은 시점의 조회 결과입니다.
+type RepositoryRecord struct {
+	Repository types.Repository `json:"repository"`
+	Versions   []VersionRecord  `json:"versions"`
+	SyncedAt   time.Time        `json:"syncedAt"` // 이 리포지토리를 마지막으로 ECR에서 조회한 시각
+}
+
+// VersionRecord는 이미지(차트 버전) 하나의 ECR 정보와 차트 메타데이터입니다.
+type VersionRecord struct {
+	Image types.ImageDetail `json:"image"`
+	Chart *ChartMetadata    `json:"chart,omitempty"` // Helm 차트가 아니거나 아직 읽지 못했으면 nil
+}
+
+// ChartMetadata는 차트 아카이브의 Chart.yaml과 values.yaml에서 읽은 검색용 메타데이터입니다.
+type ChartMetadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	AppVersion  string   `json:"appVersion,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+	ValuesKeys  []string `json:"valuesKeys,omitempty"` // "podDisruptionBudget.enabled"와 같이 점으로 연결한 values 키 경로
+}
+
+// Snapshot은 저장소에 있는 전체 데이터입니다.
+type Snapshot struct {
+	Repositories map[string]*RepositoryRecord
+	Skipped      map[string]struct{} // 차트 메타데이터를 읽을 수 없는 버전 ("repo@digest")
+	LastSync     time.Time           // 마지막 전체 동기화를 마친 시각 (동기화한 적이 없으면 zero)
+}
+
+// Store는 bbolt 파일에 리포지토리와 차트 버전 레코드를 저장합니다. 여러 고루틴에서 동시에 사용할 수 있습니다.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open은 path의 저장소 파일을 열거나 새로 만듭니다.
+// 다른 프로세스가 같은 파일을 사용 중이면 잠시 기다린 뒤 에러를 반환합니다.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(bucketMeta)
+		if err != nil {
+			return err
+		}
+		if v := meta.Get(keySchemaVersion); v != nil && string(v) != schemaVersion {
+			for _, name := range [][]byte{bucketRepositories, bucketSkipped} {
+				if err := tx.DeleteBucket(name); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+					return err
+				}
+			}
+			if err := meta.Delete(keyLastSync); err != nil {
+				return err
+			}
+		}
+		for _, name := range [][]byte{bucketRepositories, bucketSkipped} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return meta.Put(keySchemaVersion, []byte(schemaVersion))
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close는 저장소 파일을 닫습니다.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Load는 저장된 모든 데이터를 읽습니다. 읽을 수 없는 레코드는 건너뜁니다. (다음 동기화에서 다시 저장됩니다)
+func (s *Store) Load() (*Snapshot, error) {
+	snap := &Snapshot{
+		Repositories: make(map[string]*RepositoryRecord),
+		Skipped:      make(map[string]struct{}),
+	}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(bucketMeta).Get(keyLastSync); v != nil {
+			if err := snap.LastSync.UnmarshalText(v); err != nil {
+				return fmt.Errorf("invalid last sync time: %w", err)
+			}
+		}
+		err := tx.Bucket(bucketRepositories).ForEach(func(k, v []byte) error {
+			var rec RepositoryRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return nil
+			}
+			snap.Repositories[string(k)] = &rec
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(bucketSkipped).ForEach(func(k, _ []byte) error {
+			snap.Skipped[string(k)] = struct{}{}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load store: %w", err)
+	}
+	return snap, nil
+}
+
+// PutRepository는 리포지토리 레코드를 저장합니다. 같은 이름의 기존 레코드는 교체됩니다.
+func (s *Store) PutRepository(name string, rec *RepositoryRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode repository %s: %w", name, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRepositories).Put([]byte(name), data)
+	})
+}
+
+// DeleteRepository는 리포지토리 레코드와 해당 리포지토리의 건너뛴 버전 기록을 삭제합니다.
+func (s *Store) DeleteRepository(name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(bucketRepositories).Delete([]byte(name)); err != nil {
+			return err
+		}
+		// 커서로 순회하면서 삭제하면 다음 항목을 건너뛸 수 있으므로 키를 모은 뒤 삭제합니다.
+		skipped := tx.Bucket(bucketSkipped)
+		prefix := []byte(name + "@")
+		var keys [][]byte
+		c := skipped.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, bytes.Clone(k))
+		}
+		for _, k := range keys {
+			if err := skipped.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutSkipped는 차트 메타데이터를 읽을 수 없는 버전("repo@digest")을 기록합니다.
+func (s *Store) PutSkipped(key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSkipped).Put([]byte(key), []byte{})
+	})
+}
+
+// SetLastSync는 전체 동기화를 마친 시각을 기록합니다.
+func (s *Store) SetLastSync(t time.Time) error {
+	v, err := t.UTC().MarshalText()
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Put(keyLastSync, v)
+	})
+}
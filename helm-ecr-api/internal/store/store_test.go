@@ -0,0 +1,226 @@
+// filepath: helm-ecr-api/internal/store/store_test.go
+package store
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// openTemp는 테스트용 임시 디렉터리에 저장소를 엽니다.
+func openTemp(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "catalog.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func repoRecord(name, digest string) *RepositoryRecord {
+	return &RepositoryRecord{
+		Repository: types.Repository{RepositoryName: aws.String(name)},
+		Versions: []VersionRecord{{
+			Image: types.ImageDetail{ImageDigest: aws.String(digest), ImageTags: []string{"1.0.0"}},
+			Chart: &ChartMetadata{Name: "app", Version: "1.0.0", ValuesKeys: []string{"image.tag"}},
+		}},
+		SyncedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStoreEmpty(t *testing.T) {
+	s, _ := openTemp(t)
+	defer s.Close()
+
+	snap, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Repositories) != 0 || len(snap.Skipped) != 0 || !snap.LastSync.IsZero() {
+		t.Errorf("Load() on a new store = %+v", snap)
+	}
+}
+
+func TestStorePutDelete(t *testing.T) {
+	s, _ := openTemp(t)
+	defer s.Close()
+
+	steps := []struct {
+		name        string
+		apply       func() error
+		wantRepos   []string
+		wantDigest  string // charts/app 레코드의 다이제스트
+		wantSkipped []string
+	}{
+		{
+			name:       "put",
+			apply:      func() error { return s.PutRepository("charts/app", repoRecord("charts/app", "sha256:a")) },
+			wantRepos:  []string{"charts/app"},
+			wantDigest: "sha256:a",
+		},
+		{
+			name:       "put replaces the record",
+			apply:      func() error { return s.PutRepository("charts/app", repoRecord("charts/app", "sha256:b")) },
+			wantRepos:  []string{"charts/app"},
+			wantDigest: "sha256:b",
+		},
+		{
+			name: "put another repository and skipped versions",
+			apply: func() error {
+				if err := s.PutRepository("charts/app2", repoRecord("charts/app2", "sha256:c")); err != nil {
+					return err
+				}
+				for _, key := range []string{"charts/app@sha256:x", "charts/app@sha256:y", "charts/app2@sha256:z"} {
+					if err := s.PutSkipped(key); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			wantRepos:   []string{"charts/app", "charts/app2"},
+			wantDigest:  "sha256:b",
+			wantSkipped: []string{"charts/app2@sha256:z", "charts/app@sha256:x", "charts/app@sha256:y"},
+		},
+		{
+			// 이름이 접두사인 다른 리포지토리(charts/app2)의 기록은 남아야 합니다.
+			name:        "delete removes the repository and its skipped versions",
+			apply:       func() error { return s.DeleteRepository("charts/app") },
+			wantRepos:   []string{"charts/app2"},
+			wantSkipped: []string{"charts/app2@sha256:z"},
+		},
+		{
+			name:        "delete a missing repository",
+			apply:       func() error { return s.DeleteRepository("charts/missing") },
+			wantRepos:   []string{"charts/app2"},
+			wantSkipped: []string{"charts/app2@sha256:z"},
+		},
+	}
+
+	for _, st := range steps {
+		if err := st.apply(); err != nil {
+			t.Fatalf("%s: %v", st.name, err)
+		}
+		snap, err := s.Load()
+		if err != nil {
+			t.Fatalf("%s: Load() error = %v", st.name, err)
+		}
+		if got := sortedKeys(snap.Repositories); !reflect.DeepEqual(got, st.wantRepos) {
+			t.Errorf("%s: repositories = %v, want %v", st.name, got, st.wantRepos)
+		}
+		if st.wantDigest != "" {
+			if got := aws.ToString(snap.Repositories["charts/app"].Versions[0].Image.ImageDigest); got != st.wantDigest {
+				t.Errorf("%s: digest = %s, want %s", st.name, got, st.wantDigest)
+			}
+		}
+		if got := sortedKeys(snap.Skipped); !reflect.DeepEqual(got, st.wantSkipped) {
+			t.Errorf("%s: skipped = %v, want %v", st.name, got, st.wantSkipped)
+		}
+	}
+}
+
+// TestStoreReopen은 저장소를 닫았다가 다시 열어도 기록한 레코드와 동기화 시각이 유지되는지 확인합니다.
+func TestStoreReopen(t *testing.T) {
+	s, path := openTemp(t)
+	want := repoRecord("charts/app", "sha256:a")
+	lastSync := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("KST", 9*60*60))
+	if err := s.PutRepository("charts/app", want); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutSkipped("charts/app@sha256:x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetLastSync(lastSync); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	snap, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(snap.Repositories["charts/app"], want) {
+		t.Errorf("repository after reopen = %+v, want %+v", snap.Repositories["charts/app"], want)
+	}
+	if _, ok := snap.Skipped["charts/app@sha256:x"]; !ok || len(snap.Skipped) != 1 {
+		t.Errorf("skipped after reopen = %v", snap.Skipped)
+	}
+	if !snap.LastSync.Equal(lastSync) {
+		t.Errorf("last sync after reopen = %s, want %s", snap.LastSync, lastSync)
+	}
+}
+
+// TestStoreSchemaChange는 저장된 스키마 버전이 다르면 Open이 기존 데이터를 지우는지 확인합니다.
+func TestStoreSchemaChange(t *testing.T) {
+	s, path := openTemp(t)
+	s.PutRepository("charts/app", repoRecord("charts/app", "sha256:a"))
+	s.PutSkipped("charts/app@sha256:x")
+	s.SetLastSync(time.Now())
+	s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Put(keySchemaVersion, []byte("0"))
+	})
+	s.Close()
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	snap, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Repositories) != 0 || len(snap.Skipped) != 0 || !snap.LastSync.IsZero() {
+		t.Errorf("Load() after a schema change = %+v, want empty", snap)
+	}
+}
+
+// TestStoreSkipsInvalidRecord는 읽을 수 없는 레코드가 있어도 나머지 레코드를 읽는지 확인합니다.
+func TestStoreSkipsInvalidRecord(t *testing.T) {
+	s, _ := openTemp(t)
+	defer s.Close()
+	s.PutRepository("charts/app", repoRecord("charts/app", "sha256:a"))
+	s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRepositories).Put([]byte("charts/broken"), []byte("{"))
+	})
+
+	snap, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sortedKeys(snap.Repositories); !reflect.DeepEqual(got, []string{"charts/app"}) {
+		t.Errorf("repositories = %v", got)
+	}
+}
+
+// TestStoreLocked는 다른 연결이 파일을 사용 중이면 Open이 기다린 뒤 실패하는지 확인합니다.
+func TestStoreLocked(t *testing.T) {
+	s, path := openTemp(t)
+	defer s.Close()
+	if s2, err := Open(path); err == nil {
+		s2.Close()
+		t.Fatal("Open() succeeded while the file is locked")
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+// filepath: helm-ecr-api/internal/tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 지원하는 exporter 종류
+const (
+	ExporterNone   = "none"   // 트레이싱 비활성화 (기본값)
+	ExporterOTLP   = "otlp"   // OTLP/HTTP로 컬렉터에 전송
+	ExporterStdout = "stdout" // 표준 출력에 JSON으로 출력 (로컬 디버깅용)
+)
+
+// Options는 트레이싱 설정입니다.
+type Options struct {
+	ServiceName string
+	Exporter    string  // none, otlp, stdout
+	Endpoint    string  // OTLP 컬렉터 주소 (예: "localhost:4318"). 비어 있으면 OTEL_EXPORTER_OTLP_ENDPOINT 또는 기본값 사용
+	Insecure    bool    // OTLP 컬렉터에 HTTP(평문)로 접속
+	SampleRatio float64 // 0.0 ~ 1.0, 부모 스팬이 없는 요청에 적용할 샘플링 비율
+}
+
+// Setup은 전역 TracerProvider와 W3C Trace Context 전파기를 설정합니다.
+// 반환된 shutdown 함수는 서버 종료 시 호출하여 버퍼에 남은 스팬을 모두 내보내야 합니다.
+// 참조: https://opentelemetry.io/docs/languages/go/getting-started/
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	// traceparent/tracestate 헤더를 읽고 쓰도록 전파기를 설정합니다.
+	// exporter가 none이어도 설정하여 상위 서비스의 trace context를 하위 호출로 전달합니다.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var clientOpts []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, clientOpts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// 상위 서비스가 샘플링한 요청은 항상 기록하고, 새로 시작하는 trace에만 비율을 적용합니다.
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// End는 err가 있으면 스팬에 에러를 기록한 뒤 스팬을 종료합니다.
+// defer와 함께 이름 있는 반환 값을 사용하면 함수의 최종 에러가 기록됩니다.
+//
+//	ctx, span := tracer.Start(ctx, "chart.fetch_manifest")
+//	defer func() { tracing.End(span, err) }()
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,75 @@
+// filepath: helm-ecr-api/internal/webhook/delivery.go
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// 전송 상태
+const (
+	StatusPending   = "pending"   // 큐에서 기다리거나 재시도 중
+	StatusDelivered = "delivered" // 2xx 응답을 받음
+	StatusFailed    = "failed"    // 재시도할 수 없는 응답을 받았거나 최대 시도 횟수를 넘음
+	StatusDropped   = "dropped"   // 큐가 가득 차서 보내지 않음
+)
+
+// Delivery는 알림 하나를 엔드포인트 하나에 보낸 기록입니다.
+type Delivery struct {
+	ID          string     `json:"id"`      // X-Webhook-Delivery 헤더 값
+	EventID     string     `json:"eventId"` // 페이로드의 id (같은 태그와 다이제스트면 같은 값)
+	Endpoint    string     `json:"endpoint"`
+	Repository  string     `json:"repository"`
+	Tag         string     `json:"tag"`
+	Digest      string     `json:"digest"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	StatusCode  int        `json:"statusCode,omitempty"` // 마지막 시도의 HTTP 응답 코드
+	Error       string     `json:"error,omitempty"`      // 마지막 시도의 에러
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// deliveryLog는 최근 전송 기록을 메모리에 보관하는 고정 크기 링 버퍼입니다. 재시작하면 비워집니다.
+type deliveryLog struct {
+	mu      sync.Mutex
+	entries []*Delivery
+	next    int // 다음에 덮어쓸 위치 (버퍼가 가득 찬 뒤에만 사용)
+	size    int
+}
+
+func newDeliveryLog(size int) *deliveryLog {
+	return &deliveryLog{entries: make([]*Delivery, 0, size), size: size}
+}
+
+// add는 새 전송 기록을 추가하고, 버퍼가 가득 찼으면 가장 오래된 기록을 덮어씁니다.
+func (l *deliveryLog) add(d *Delivery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) < l.size {
+		l.entries = append(l.entries, d)
+		return
+	}
+	l.entries[l.next] = d
+	l.next = (l.next + 1) % l.size
+}
+
+// update는 잠금을 잡은 상태에서 fn으로 기록을 수정합니다. 로그에서 밀려난 기록도 수정할 수 있습니다.
+func (l *deliveryLog) update(d *Delivery, fn func(d *Delivery)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fn(d)
+}
+
+// list는 기록의 복사본을 최신 순으로 반환합니다.
+func (l *deliveryLog) list() []Delivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]Delivery, 0, len(l.entries))
+	for i := range l.entries {
+		// 가장 최근에 추가한 기록은 next 바로 앞에 있습니다.
+		d := *l.entries[(l.next-1-i+2*len(l.entries))%len(l.entries)]
+		out = append(out, d)
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+// filepath: helm-ecr-api/internal/webhook/delivery_test.go
+package webhook
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestDeliveryLogList(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		added int
+		want  []string // 최신 순 ID
+	}{
+		{name: "empty", size: 3, added: 0, want: []string{}},
+		{name: "partly filled", size: 3, added: 2, want: []string{"d2", "d1"}},
+		{name: "exactly full", size: 3, added: 3, want: []string{"d3", "d2", "d1"}},
+		{name: "wrapped once", size: 3, added: 4, want: []string{"d4", "d3", "d2"}},
+		{name: "wrapped to the end", size: 3, added: 6, want: []string{"d6", "d5", "d4"}},
+		{name: "wrapped several times", size: 3, added: 8, want: []string{"d8", "d7", "d6"}},
+		{name: "single entry", size: 1, added: 5, want: []string{"d5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newDeliveryLog(tt.size)
+			for i := 1; i <= tt.added; i++ {
+				log.add(&Delivery{ID: "d" + strconv.Itoa(i)})
+			}
+			ids := []string{}
+			for _, d := range log.list() {
+				ids = append(ids, d.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("list() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+// TestDeliveryLogUpdate는 list가 복사본을 반환하고, update로 바꾼 내용이 다음 list에 나타나는지 확인합니다.
+func TestDeliveryLogUpdate(t *testing.T) {
+	log := newDeliveryLog(2)
+	d := &Delivery{ID: "d1", Status: StatusPending}
+	log.add(d)
+
+	listed := log.list()
+	listed[0].Status = StatusFailed
+	if got := log.list()[0].Status; got != StatusPending {
+		t.Fatalf("modifying the listed copy changed the log: status = %s", got)
+	}
+
+	log.update(d, func(d *Delivery) {
+		d.Status = StatusDelivered
+		d.Attempts++
+	})
+	if got := log.list()[0]; got.Status != StatusDelivered || got.Attempts != 1 {
+		t.Errorf("after update: %+v", got)
+	}
+}
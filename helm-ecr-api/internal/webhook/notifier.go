@@ -0,0 +1,351 @@
+// filepath: helm-ecr-api/internal/webhook/notifier.go
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"helm-ecr-api/internal/metrics"
+	"helm-ecr-api/internal/service"
+	"io"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// EventTagPushed는 새 차트 태그 알림의 이벤트 이름입니다. (페이로드의 event, X-Webhook-Event 헤더)
+const EventTagPushed = "chart.tag.pushed"
+
+// 응답 본문은 에러 메시지에 표시할 만큼만 읽습니다.
+const maxErrorBody = 512
+
+// Endpoint는 알림을 받을 URL과 서명 키입니다.
+type Endpoint struct {
+	Name   string
+	URL    string
+	Secret string // 비어 있으면 서명하지 않음
+}
+
+// Payload는 웹훅 요청 본문입니다.
+type Payload struct {
+	ID             string    `json:"id"` // 저장소, 태그, 다이제스트로 정해지는 값 (수신 측 중복 제거용)
+	Event          string    `json:"event"`
+	Timestamp      time.Time `json:"timestamp"`
+	Repository     string    `json:"repository"`
+	Tag            string    `json:"tag"`
+	Version        string    `json:"version"` // Chart.yaml의 version
+	Digest         string    `json:"digest"`
+	PreviousDigest string    `json:"previousDigest,omitempty"` // 태그가 다른 이미지에서 옮겨 왔을 때 이전 다이제스트
+	PushedAt       time.Time `json:"pushedAt"`
+	Chart          Chart     `json:"chart"`
+}
+
+// Chart는 페이로드에 포함하는 Chart.yaml 요약입니다.
+type Chart struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	AppVersion  string   `json:"appVersion,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+}
+
+// Notifier는 새 차트 태그를 설정된 엔드포인트에 비동기로 POST합니다.
+// 알림은 메모리 큐에 쌓였다가 워커가 보내므로, 서버가 종료되면 보내지 못한 알림은 사라집니다.
+type Notifier struct {
+	endpoints      []Endpoint
+	client         *http.Client
+	logger         *slog.Logger
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	workers        int
+	queue          chan job
+	log            *deliveryLog
+}
+
+// job은 알림 하나를 엔드포인트 하나에 보내는 작업입니다.
+type job struct {
+	endpoint Endpoint
+	body     []byte
+	delivery *Delivery
+}
+
+// Option은 Notifier 설정 함수입니다.
+type Option func(*Notifier)
+
+// WithLogger는 로거를 설정합니다.
+func WithLogger(logger *slog.Logger) Option {
+	return func(n *Notifier) {
+		n.logger = logger
+	}
+}
+
+// WithTimeout은 요청 하나의 타임아웃을 설정합니다.
+func WithTimeout(d time.Duration) Option {
+	return func(n *Notifier) {
+		n.client.Timeout = d
+	}
+}
+
+// WithRetry는 최대 시도 횟수와 재시도 대기 시간을 설정합니다.
+// 대기 시간은 시도마다 두 배로 늘어나며(최대 maxBackoff), 0부터 그 값 사이에서 무작위로 정합니다.
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(n *Notifier) {
+		n.maxAttempts = maxAttempts
+		n.initialBackoff = initialBackoff
+		n.maxBackoff = maxBackoff
+	}
+}
+
+// WithQueueSize는 전송을 기다리는 최대 작업 수를 설정합니다.
+func WithQueueSize(size int) Option {
+	return func(n *Notifier) {
+		n.queue = make(chan job, size)
+	}
+}
+
+// WithDeliveryLogSize는 보관할 최근 전송 기록 수를 설정합니다.
+func WithDeliveryLogSize(size int) Option {
+	return func(n *Notifier) {
+		n.log = newDeliveryLog(size)
+	}
+}
+
+// WithWorkers는 동시에 보내는 요청 수를 설정합니다.
+// 같은 엔드포인트에 보내는 알림의 순서는 워커가 하나일 때만 보장됩니다.
+func WithWorkers(workers int) Option {
+	return func(n *Notifier) {
+		n.workers = workers
+	}
+}
+
+// New는 endpoints에 알림을 보내는 Notifier를 생성합니다. 알림을 보내려면 Run을 실행해야 합니다.
+func New(endpoints []Endpoint, opts ...Option) *Notifier {
+	n := &Notifier{
+		endpoints:      endpoints,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		logger:         slog.Default(),
+		maxAttempts:    5,
+		initialBackoff: time.Second,
+		maxBackoff:     time.Minute,
+		workers:        2,
+		queue:          make(chan job, 1000),
+		log:            newDeliveryLog(200),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// NotifyTag는 새 차트 태그 알림을 모든 엔드포인트의 전송 큐에 넣습니다. service.TagListener로 사용합니다.
+// 카탈로그 갱신을 지연시키지 않도록 기다리지 않으며, 큐가 가득 차면 알림을 버리고 전송 기록에 dropped로 남깁니다.
+func (n *Notifier) NotifyTag(ev service.TagEvent) {
+	payload := Payload{
+		ID:             eventID(ev.Repository, ev.Tag, ev.Digest),
+		Event:          EventTagPushed,
+		Timestamp:      time.Now().UTC(),
+		Repository:     ev.Repository,
+		Tag:            ev.Tag,
+		Version:        ev.Chart.Version,
+		Digest:         ev.Digest,
+		PreviousDigest: ev.PreviousDigest,
+		PushedAt:       ev.PushedAt.UTC(),
+		Chart: Chart{
+			Name:        ev.Chart.Name,
+			Version:     ev.Chart.Version,
+			AppVersion:  ev.Chart.AppVersion,
+			Description: ev.Chart.Description,
+			Keywords:    ev.Chart.Keywords,
+			Maintainers: ev.Chart.Maintainers,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		n.logger.Error("failed to encode webhook payload", "repo", ev.Repository, "tag", ev.Tag, "error", err)
+		return
+	}
+
+	for _, ep := range n.endpoints {
+		d := &Delivery{
+			ID:         newDeliveryID(),
+			EventID:    payload.ID,
+			Endpoint:   ep.Name,
+			Repository: ev.Repository,
+			Tag:        ev.Tag,
+			Digest:     ev.Digest,
+			Status:     StatusPending,
+			CreatedAt:  payload.Timestamp,
+		}
+		n.log.add(d)
+		select {
+		case n.queue <- job{endpoint: ep, body: body, delivery: d}:
+		default:
+			n.logger.Warn("dropping webhook delivery because the queue is full", "endpoint", ep.Name, "repo", ev.Repository, "tag", ev.Tag)
+			n.complete(d, StatusDropped)
+		}
+	}
+}
+
+// Deliveries는 최근 전송 기록을 최신 순으로 반환합니다.
+func (n *Notifier) Deliveries() []Delivery {
+	return n.log.list()
+}
+
+// Run은 ctx가 취소될 때까지 큐의 알림을 보냅니다. 고루틴으로 실행해야 합니다.
+func (n *Notifier) Run(ctx context.Context) {
+	n.logger.Info("starting webhook notifier", "endpoints", len(n.endpoints), "workers", n.workers)
+	done := make(chan struct{})
+	for range n.workers {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-n.queue:
+					n.deliver(ctx, j)
+				}
+			}
+		}()
+	}
+	for range n.workers {
+		<-done
+	}
+}
+
+// deliver는 성공하거나 재시도할 수 없는 응답을 받을 때까지 작업을 보냅니다.
+func (n *Notifier) deliver(ctx context.Context, j job) {
+	d := j.delivery
+	logger := n.logger.With("endpoint", j.endpoint.Name, "delivery_id", d.ID, "repo", d.Repository, "tag", d.Tag)
+
+	for attempt := 0; attempt < n.maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				// 서버 종료 중에는 재시도하지 않습니다. 기록은 pending으로 남습니다.
+				return
+			case <-time.After(n.backoff(attempt - 1)):
+			}
+		}
+
+		status, err := n.post(ctx, j)
+		n.log.update(d, func(d *Delivery) {
+			d.Attempts = attempt + 1
+			d.StatusCode = status
+			d.Error = ""
+			if err != nil {
+				d.Error = err.Error()
+			}
+		})
+		if err == nil {
+			logger.Info("delivered webhook", "attempts", attempt+1, "status", status)
+			n.complete(d, StatusDelivered)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !retryable(status) {
+			logger.Warn("webhook delivery rejected", "attempts", attempt+1, "status", status, "error", err)
+			n.complete(d, StatusFailed)
+			return
+		}
+		logger.Debug("webhook delivery attempt failed", "attempt", attempt+1, "status", status, "error", err)
+	}
+	logger.Warn("webhook delivery failed after retries", "attempts", n.maxAttempts, "error", d.Error)
+	n.complete(d, StatusFailed)
+}
+
+// post는 서명한 요청을 한 번 보내고 HTTP 응답 코드를 반환합니다. 2xx가 아니면 에러를 반환합니다.
+// 네트워크 에러는 응답 코드 0으로 반환합니다.
+func (n *Notifier) post(ctx context.Context, j job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.endpoint.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return 0, err
+	}
+	// 재전송 공격을 막을 수 있도록 시도마다 현재 시각으로 다시 서명합니다.
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "helm-ecr-api-webhook")
+	req.Header.Set("X-Webhook-Event", EventTagPushed)
+	req.Header.Set("X-Webhook-Delivery", j.delivery.ID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	if j.endpoint.Secret != "" {
+		req.Header.Set("X-Webhook-Signature", Sign(j.endpoint.Secret, timestamp, j.body))
+	}
+
+	start := time.Now()
+	resp, err := n.client.Do(req)
+	metrics.ObserveUpstream("webhook.Post", start, err)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+}
+
+// complete는 전송 기록을 최종 상태로 바꾸고 메트릭을 기록합니다.
+func (n *Notifier) complete(d *Delivery, status string) {
+	now := time.Now().UTC()
+	var endpoint string
+	n.log.update(d, func(d *Delivery) {
+		d.Status = status
+		d.CompletedAt = &now
+		endpoint = d.Endpoint
+	})
+	metrics.IncWebhookDelivery(endpoint, status)
+}
+
+// backoff는 attempt번째 재시도 전에 기다릴 시간을 반환합니다. (full jitter)
+func (n *Notifier) backoff(attempt int) time.Duration {
+	ceiling := n.initialBackoff << attempt
+	if ceiling <= 0 || ceiling > n.maxBackoff {
+		ceiling = n.maxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return mathrand.N(ceiling)
+}
+
+// retryable은 다시 보내면 성공할 수 있는 응답인지 확인합니다. 네트워크 에러(0), 408, 429, 5xx가 해당합니다.
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// Sign은 X-Webhook-Signature 헤더 값을 계산합니다.
+// 값은 "sha256=" 뒤에 "<X-Webhook-Timestamp>.<요청 본문>"의 HMAC-SHA256을 16진수로 붙인 것입니다.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// eventID는 같은 태그가 같은 다이제스트로 다시 알려져도 수신 측에서 중복을 걸러낼 수 있도록 고정된 ID를 만듭니다.
+func eventID(repo, tag, digest string) string {
+	sum := sha256.Sum256([]byte(repo + "\x00" + tag + "\x00" + digest))
+	return hex.EncodeToString(sum[:16])
+}
+
+// newDeliveryID는 128비트 난수로 새 전송 ID를 생성합니다.
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	rand.Read(b) // crypto/rand.Read는 에러를 반환하지 않습니다. (Go 1.24+)
+	return hex.EncodeToString(b)
+}
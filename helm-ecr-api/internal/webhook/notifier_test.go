@@ -0,0 +1,65 @@
+// filepath: helm-ecr-api/internal/webhook/notifier_test.go
+package webhook
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{
+			name:      "push event",
+			secret:    "secret",
+			timestamp: "1700000000",
+			body:      `{"event":"push"}`,
+			want:      "sha256=a4f629eb236ac066052a3b02bf93e72ba1b53885dec3d408012e8d484a18a9b8",
+		},
+		{
+			name:      "empty secret and body",
+			timestamp: "0",
+			want:      "sha256=b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// 타임스탬프도 서명에 포함되어야 재전송 공격을 막을 수 있습니다.
+	if Sign("secret", "1", []byte("body")) == Sign("secret", "2", []byte("body")) {
+		t.Error("signature does not depend on the timestamp")
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{0, true},
+		{http.StatusOK, false},
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusRequestTimeout, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.status); got != tt.want {
+			t.Errorf("retryable(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}